@@ -16,17 +16,69 @@ limitations under the License.
 package v1
 
 import (
+	"slices"
+
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	DefaultSolrCollectionSetActive           = true
-	DefaultSolrCollectionSetCleanupEnabled   = false
-	DefaultSolrCollectionSetBlueGreenEnabled = true
-	DefaultSolrCollectionReplicationFactor   = int32(1)
+	DefaultSolrCollectionSetActive              = true
+	DefaultSolrCollectionSetCleanupEnabled      = false
+	DefaultSolrCollectionSetBlueGreenEnabled    = true
+	DefaultSolrCollectionSetCreateAliasesAlways = false
+	DefaultSolrCollectionSetOptimizeAfterReload = false
+	DefaultSolrCollectionReplicationFactor      = int32(1)
+	DefaultSolrCollectionAutoAddReplicas        = true
+	DefaultSolrCollectionSetDefaultColor        = "blue"
+	DefaultSolrCollectionSetMode                = SolrCollectionSetModeManage
+	DefaultSolrCollectionSetSharedChecksums     = false
+	DefaultSolrCollectionSetVerifyConfigSets    = false
+	DefaultSolrCollectionSetScopeStatus         = false
+	DefaultSolrCollectionSetScalingEnabled      = true
+	DefaultSolrCollectionSetSolrPort            = int32(8983)
+	DefaultSolrCollectionSetSolrScheme          = "http"
+	DefaultSolrCollectionSetCleanupMaxDeletions = int32(3)
+	DefaultSolrCollectionSetCleanupMaxPercent   = int32(50)
+	// DefaultSolrCollectionSetReplicaRepairDelaySeconds matches the waitFor of Solr's own autoAddReplicas ...
+	DefaultSolrCollectionSetReplicaRepairDelaySeconds = int32(120)
 )
 
+// Collection set modes ...
+const (
+	// SolrCollectionSetModeManage means the operator makes changes to the Solr cluster to match the spec
+	SolrCollectionSetModeManage = "manage"
+	// SolrCollectionSetModeObserve means the operator only reports the status of the Solr cluster and never changes it
+	SolrCollectionSetModeObserve = "observe"
+)
+
+// Shard health, as reported in ShardStatus ...
+const (
+	// ShardHealthHealthy means the shard has the target number of replicas and they're all active
+	ShardHealthHealthy = "healthy"
+	// ShardHealthUnderReplicated means the shard has fewer live replicas than its target
+	ShardHealthUnderReplicated = "underReplicated"
+	// ShardHealthOverReplicated means the shard has more replicas than its target
+	ShardHealthOverReplicated = "overReplicated"
+	// ShardHealthRecovering means the shard has the target number of replicas but some of them aren't active
+	ShardHealthRecovering = "recovering"
+)
+
+// Collection settings that are reconciled on existing collections with MODIFYCOLLECTION ...
+const (
+	// SolrCollectionSettingReplicationFactor is the replication factor recorded by Solr for a collection
+	SolrCollectionSettingReplicationFactor = "replicationFactor"
+	// SolrCollectionSettingAutoAddReplicas is whether Solr automatically adds replicas to replace lost replicas
+	SolrCollectionSettingAutoAddReplicas = "autoAddReplicas"
+)
+
+// DefaultSolrCollectionSetReconciledSettings are the settings reconciled on existing collections if the spec doesn't
+// say otherwise ...
+var DefaultSolrCollectionSetReconciledSettings = []string{
+	SolrCollectionSettingReplicationFactor,
+	SolrCollectionSettingAutoAddReplicas,
+}
+
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // SolrCollectionSetSpec defines the desired state of SolrCollectionSet
@@ -38,41 +90,246 @@ type SolrCollectionSetSpec struct {
 	// SolrClusterName The name of Solr Cluster to which this cluster set belongs. This value is really just informational.
 	SolrClusterName string `json:"clusterName"`
 
-	// SolrClusterUrl The URL to use to interact with the Solr cluster. If omitted defaults to `http://<name>-solrcloud:8389/solr/admin
+	// Mode Determines whether the operator manages the Solr cluster (manage) or only reports its status (observe). In
+	// observe mode nothing in the Solr cluster is created, changed, or removed (not even the checksums collection).
+	// +kubebuilder:validation:Enum:=manage;observe
+	// +optional
+	// +default:manage
+	Mode string `json:"mode,omitempty"`
+
+	// SolrClusterUrl The URL to use to interact with the Solr cluster. If omitted defaults to the operator's
+	// --default-solr-cluster-url flag, or if that isn't set either to <solrScheme>://<name>-solrcloud:<solrPort>/solr
+	// where <name> is the name of the collection set.
 	// +optional
 	SolrClusterUrl string `json:"clusterUrl"`
 
+	// SolrScheme The scheme (http or https) of the default cluster URL. Ignored if clusterUrl is given.
+	// +kubebuilder:validation:Enum:=http;https
+	// +optional
+	// +default:http
+	SolrScheme string `json:"solrScheme,omitempty"`
+
+	// SolrPort The port of the default cluster URL. Ignored if clusterUrl is given.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +optional
+	// +default:8983
+	SolrPort *int32 `json:"solrPort,omitempty"`
+
 	// SecretRef The name of the Kubernetes Secret that stores the basic auth secret used to call the Solr API.
-	// This secret must be in the same namespace as the collections operator.
-	// It should be hashed in the format that Solr expects.
-	SecretRef string `json:"secretName"`
+	// This secret must be in the namespace given by the operator's --default-solr-secret-namespace flag.
+	// It should be hashed in the format that Solr expects. Any keys of the form "header.<Header-Name>" (e.g.
+	// header.X-Api-Key) are sent as headers on every request. If the secret has a "token" key then it's sent as a
+	// bearer token rather than using basic auth. If omitted the credentials are read from the operator's
+	// --solr-credentials-path if it's given, and otherwise default to the operator's --default-solr-secret-name flag.
+	// +optional
+	SecretRef string `json:"secretName,omitempty"`
+
+	// AuthMode How the operator authenticates with the Solr API: basic (basic auth with the username and password) or
+	// bearer (a bearer token, e.g. a JWT for Solr's JWTAuthPlugin, from the "token" key of the secret, which is re-read
+	// on every request so that it can be refreshed). If omitted a bearer token is used if there is one and basic auth
+	// otherwise.
+	// +kubebuilder:validation:Enum:=basic;bearer
+	// +optional
+	AuthMode string `json:"authMode,omitempty"`
 
 	// Active Determines if the CollectionSet is being actively managed or management has been paused
 	// +optional
 	// +default:true
 	Active *bool `json:"active"`
 
-	// ReplicationFactor The replication factor of the collections in the set
+	// ReplicationFactor The replication factor of the collections in the set. Individual collections can override it
+	// (including disabling a collection with a replication factor of 0).
+	// +kubebuilder:validation:Minimum=1
 	// +optional
 	// +default:1
 	ReplicationFactor *int32 `json:"replicationFactor"`
 
-	// BlueGreenEnabled Determines if the _blue/_green strategy for managing collections is used.
+	// ScalingEnabled Determines if replicas are added and removed to match the spec. Turning it off pauses scaling
+	// (e.g. during a known node shortage) while collections and config sets are still managed.
+	// +optional
+	// +default:true
+	ScalingEnabled *bool `json:"scalingEnabled"`
+
+	// ScaleOutBatchSize The most replicas added (across every collection) in one reconcile. A large scale out is
+	// spread over several reconciles, which gives the autoscaler time to provision nodes for the new replicas rather
+	// than asking Solr for all of them at once. If omitted every missing replica is asked for at once.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	ScaleOutBatchSize *int32 `json:"scaleOutBatchSize,omitempty"`
+
+	// Replicas The number of replicas each shard of the collections in the set is scaled to. This is separate from the
+	// replication factor, which is only recorded by Solr as the collection's metadata, so that collections can run
+	// more replicas than their replication factor (e.g. for read capacity). If not given then each collection is
+	// scaled to its replication factor. Disabled collections are never scaled.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// InactiveReplicas The number of replicas each shard of the inactive color of a blue/green collection (i.e. the
+	// color no alias points at) is scaled to, e.g. to save resources. The active color is scaled to replicas (or the
+	// replication factor) as usual, as is the color named by activeColor. Note that changing activeColor switches the
+	// alias before the new color has been scaled up, so scale it up beforehand (e.g. by removing this) to avoid serving
+	// from fewer replicas. Ignored if blue/green isn't enabled and for collections that alias all colors.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	InactiveReplicas *int32 `json:"inactiveReplicas,omitempty"`
+
+	// ChecksumReplicationFactor The replication factor of the internal checksums collection. If not given then the
+	// replication factor of the set is used, and the checksums collection is scaled along with the set when that
+	// changes. The checksums collection is always scaled to its replication factor (replicas doesn't apply to it). A
+	// shared checksums collection keeps the replication factor it was created with, as the sets sharing it may disagree.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ChecksumReplicationFactor *int32 `json:"checksumReplicationFactor,omitempty"`
+
+	// CreateNodeSet The Solr nodes (e.g. solr-0.solr-headless:8983_solr) that replicas added during scale out are placed
+	// on. Useful for rack/zone awareness. If omitted Solr places the replicas itself.
+	// +optional
+	CreateNodeSet []string `json:"createNodeSet,omitempty"`
+
+	// PlacementPolicy The name of the Solr placement policy that the replicas the operator creates follow (passed as
+	// policy when collections are created and when replicas are added), so that they're placed the same way as
+	// collections created by hand. Only versions of Solr that support policies use it. If omitted the cluster's
+	// default placement is used.
+	// +optional
+	PlacementPolicy string `json:"placementPolicy,omitempty"`
+
+	// SharedChecksums Determines if the config set checksums are kept in a single checksums collection shared by
+	// all the collection sets in the Solr cluster (_sharedChecksums) rather than in a collection of the set's own.
+	// Its replication factor is only set when it's created (see checksumReplicationFactor).
+	// +optional
+	// +default:false
+	SharedChecksums *bool `json:"sharedChecksums"`
+
+	// VerifyConfigSets Determines if the config sets in Solr are periodically downloaded and compared with their
+	// configmaps (how often is set by the operator's --config-set-verify-interval). Config sets which were changed in
+	// Solr out-of-band (e.g. uploaded by hand) are re-uploaded. Without this only changes to the configmaps are detected.
+	// +optional
+	// +default:false
+	VerifyConfigSets *bool `json:"verifyConfigSets"`
+
+	// ScopeClusterStatus Determines if the cluster status is only fetched for the collections managed by the set (plus
+	// the checksums collection and routed aliases) rather than for every collection in the cluster. This keeps the
+	// responses small on a large shared cluster, but takes a Solr request per collection. Collections that were stamped
+	// as belonging to the set, but are neither specified nor recorded in the status, aren't seen (or cleaned up).
+	// +optional
+	// +default:false
+	ScopeClusterStatus *bool `json:"scopeClusterStatus"`
+
+	// AutoAddReplicas Determines if Solr automatically adds replicas to replace lost replicas. This is set when
+	// collections are created and is also applied to existing collections. Clusters without shared storage should
+	// probably turn it off.
+	// +optional
+	// +default:true
+	AutoAddReplicas *bool `json:"autoAddReplicas"`
+
+	// ReplicaRepairDelaySeconds How long a Solr node has to be missing from the live nodes before the replicas on it are
+	// treated as lost and replaced (like the waitFor of Solr's own autoAddReplicas). Until then the replicas are still
+	// counted, so that a node restarting (e.g. during a rolling restart) doesn't cause replicas to be added and then
+	// removed again. Use 0 to replace them right away.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	// +default:120
+	ReplicaRepairDelaySeconds *int32 `json:"replicaRepairDelaySeconds,omitempty"`
+
+	// ReconciledSettings The settings of existing collections that are kept in step with the spec (with
+	// MODIFYCOLLECTION) when they drift. Settings that are left out are only applied when collections are created.
+	// If replicationFactor is left out then the replicas of each shard are still scaled to the spec, but the
+	// replication factor recorded by Solr is left alone. Settings Solr can't change on a live collection (e.g. the
+	// number of shards or the number of TLOG replicas) can't be reconciled. Defaults to all of them.
+	// +optional
+	// +listType:=set
+	// +kubebuilder:validation:items:Enum=replicationFactor;autoAddReplicas
+	ReconciledSettings []string `json:"reconciledSettings"`
+
+	// BlueGreenEnabled Determines if the _blue/_green strategy for managing collections is used. Changing this for a set
+	// whose collections already exist isn't done automatically since the existing collections would be replaced by
+	// empty ones. Instead the set is left alone (with the reason blueGreenTransition) until the existing collections
+	// have been migrated and removed.
 	// +optional
 	// +default:true
 	BlueGreenEnabled *bool `json:"blueGreenEnabled"`
 
-	// CleanupEnabled Determines if collections which aren't in the spec are deleted. If this is false you could deploy
-	// multiple collection sets on the same Solr cluster. Otherwise, during the reconcile process collections that
-	// aren't in the spec would be removed.
+	// CreateAliasesAlways Determines if aliases are created (and kept pointing at their collections) even if blue/green
+	// isn't enabled. Only collections given an alias other than their name get one, since an alias can't have the same
+	// name as a collection. Ignored if blue/green is enabled.
+	// +optional
+	// +default:false
+	CreateAliasesAlways *bool `json:"createAliasesAlways"`
+
+	// OptimizeAfterReload Determines if a collection is optimized (i.e. its index is merged down to one segment) after
+	// it's reloaded to pick up a change to its config set, e.g. after a schema rollout. Optimizing rewrites the whole
+	// index so it's slow and expensive. It runs in the background (tracked in status.pendingOperations). A one-shot
+	// optimize can be requested with the solrcollections.solr.sis.uw.edu/optimize annotation instead.
+	// +optional
+	// +default:false
+	OptimizeAfterReload *bool `json:"optimizeAfterReload"`
+
+	// DefaultColor The color (blue or green) that the alias of a new blue/green collection points at when the collection
+	// is first created. Ignored if blue/green isn't enabled.
+	// +kubebuilder:validation:Enum:=blue;green
+	// +optional
+	// +default:blue
+	DefaultColor string `json:"defaultColor,omitempty"`
+
+	// CleanupEnabled Determines if collections which aren't in the spec are deleted. Only collections which were
+	// previously managed by this set (see status.managedCollections) are removed, so multiple collection sets can be
+	// deployed on the same Solr cluster.
 	// +optional
 	// +default:false
 	CleanupEnabled *bool `json:"cleanupEnabled"`
 
+	// MaxCollections A safety limit on the number of collections (counting both blue and green collections if
+	// blue/green is enabled) the operator will manage for this set. If the spec calls for more collections than this
+	// then no collections are created. If omitted there is no limit.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxCollections *int32 `json:"maxCollections,omitempty"`
+
+	// MinLiveNodes A safety limit on the number of live Solr nodes the cluster must have before the operator changes it.
+	// If fewer nodes are live (e.g. during an outage) then the status is still updated but nothing is created, deleted
+	// or scaled, and the set is left unstable with the reason clusterDegraded. If omitted there is no limit.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MinLiveNodes *int32 `json:"minLiveNodes,omitempty"`
+
+	// CleanupGracePeriodSeconds If cleanup is enabled, how long a collection which has been removed from the spec is
+	// kept before it's deleted. While the grace period runs the collection is listed in status.markedForDeletion, and if
+	// it's put back in the spec in the meantime it's kept. If omitted (or 0) collections are deleted right away.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	CleanupGracePeriodSeconds *int32 `json:"cleanupGracePeriodSeconds,omitempty"`
+
+	// CleanupMaxDeletions A safety limit on the number of collections cleanup will delete in one reconcile, in case the
+	// collections were removed from the spec by mistake. If more would be deleted then nothing is changed, and the set is
+	// left unstable with the reason cleanupGuardTripped until the solrcollections.solr.sis.uw.edu/allow-cleanup
+	// annotation is set to "true". The colors of a blue/green collection count as one collection. Use 0 for no limit.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	// +default:3
+	CleanupMaxDeletions *int32 `json:"cleanupMaxDeletions,omitempty"`
+
+	// CleanupMaxPercent Like cleanupMaxDeletions, but a limit on the percentage of the collections managed by the set
+	// that cleanup will delete in one reconcile. A single collection (both colors, with blue/green) can always be
+	// deleted. Use 0 for no limit.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	// +default:50
+	CleanupMaxPercent *int32 `json:"cleanupMaxPercent,omitempty"`
+
 	// Collections The collections that will be managed.
 	// +listType:=map
 	// +listMapKey:=name
 	Collections []SolrCollection `json:"collections"`
+
+	// RoutedAliases Solr routed aliases (time or category partitioned) that will be managed. Solr creates the
+	// collections behind a routed alias itself, so they aren't listed in collections.
+	// +listType:=map
+	// +listMapKey:=name
+	// +optional
+	RoutedAliases []SolrRoutedAlias `json:"routedAliases,omitempty"`
 }
 
 // +kubebuilder:validation:MinProperties:=0
@@ -86,7 +343,8 @@ type SolrCollection struct {
 	Name string `json:"name"`
 
 	// The name of alias that will be created for this collection. If blue/green isn't enabled this will be the same as
-	// name and no alias will actually be created (as it isn't necessary).
+	// name and no alias will actually be created (as it isn't necessary), unless createAliasesAlways is set and this is
+	// given a different name.
 	//
 	// +kubebuilder:validation:Pattern:=[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?
 	// +kubebuilder:validation:MinLength:=1
@@ -95,12 +353,144 @@ type SolrCollection struct {
 	Alias string `json:"alias"`
 
 	// configsetName The name of the Kubernetes configmap that contains the schema for this collection. If not provided
-	// this will be the same as "alias".
+	// this will be the same as "alias". If the configmap has a configSetVersion label then the config set is versioned,
+	// and this has to be the versioned name (e.g. books-v3), so a collection is rolled forward or back by changing it.
 	//
 	// +kubebuilder:validation:MinLength:=1
 	// +kubebuilder:validation:MaxLength:=100
 	// +optional
 	ConfigsetName string `json:"configsetName,omitempty"`
+
+	// aliasAllColors If blue/green is enabled, the alias targets both the _blue and _green collections rather than a
+	// single color. This is useful during a transition between colors. Ignored if blue/green isn't enabled.
+	//
+	// +optional
+	AliasAllColors bool `json:"aliasAllColors,omitempty"`
+
+	// activeColor If blue/green is enabled, the color (blue or green) the alias should point at. If omitted the alias
+	// keeps pointing at whichever color it was last pointing at. Either way, an alias that is found pointing somewhere
+	// else is repointed. Ignored if blue/green isn't enabled or aliasAllColors is set. Switching it emits a
+	// PromotedColor event and is recorded in the status of the collection.
+	//
+	// +kubebuilder:validation:Enum:=blue;green
+	// +optional
+	ActiveColor string `json:"activeColor,omitempty"`
+
+	// replicationFactor The replication factor of this collection, overriding the replication factor of the set. A
+	// replication factor of 0 disables the collection: it isn't created, scaled, aliased, or counted as ready, but it
+	// stays in the spec along with its config set. A disabled collection that already exists is left alone unless
+	// cleanup is enabled, in which case it's removed.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	ReplicationFactor *int32 `json:"replicationFactor,omitempty"`
+
+	// numShards The number of shards the collection is created with. If omitted the collection is created with one
+	// shard and its shard count isn't checked. Solr can't change the number of shards of an existing collection, so if
+	// it's given and doesn't match the shard count of the collection in Solr then the operator leaves the collection
+	// set alone until the spec is fixed. Since splitting a shard adds a shard, the shards of a collection that gives
+	// numShards aren't split by the operator.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	NumShards *int32 `json:"numShards,omitempty"`
+
+	// routerField The field Solr routes documents to shards on, rather than the document id (passed as router.field
+	// when the collection is created). If omitted documents are routed on their id and the router isn't checked. Solr
+	// can't change the router of an existing collection, so if it's given and doesn't match the router field of the
+	// collection in Solr then the operator leaves the collection set alone until the spec is fixed.
+	//
+	// +optional
+	RouterField string `json:"routerField,omitempty"`
+
+	// waitForFinalState If true then creating the collection waits until all of its replicas are active, so that
+	// collections created one after another are placed knowing where the earlier ones went. Only used when the
+	// collection is created.
+	//
+	// +optional
+	WaitForFinalState *bool `json:"waitForFinalState,omitempty"`
+
+	// maxShardsPerNode The most replicas of the collection that can be placed on one Solr node when it's created (older
+	// versions of Solr only, newer versions ignore it). If the live nodes can't hold all of the replicas of a collection
+	// that still needs to be created then the operator leaves the collection set alone with the reason
+	// placementInfeasible. Only used when the collection is created.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxShardsPerNode *int32 `json:"maxShardsPerNode,omitempty"`
+
+	// createNodeSet The Solr nodes (as named in the live nodes, e.g. 10.0.0.1:8983_solr) the replicas of the collection
+	// are placed on when it's created. If any of them aren't live then the operator leaves the collection set alone
+	// with the reason placementInfeasible. If omitted the replicas can be placed on any node. Only used when the
+	// collection is created.
+	//
+	// +listType:=set
+	// +optional
+	CreateNodeSet []string `json:"createNodeSet,omitempty"`
+
+	// configOverlay Config properties of the collection (e.g. query.maxBooleanClauses: "2048") which override its
+	// config set, so that collections can share a config set while differing in a few settings. They're set in the
+	// config overlay of the collection via the Solr Config API (which reloads the collection) and the overlay is kept
+	// matching them, i.e. properties that aren't given are removed from it. Values are given as strings, Solr converts
+	// them to the type of the property. If omitted the overlay is left alone.
+	//
+	// +optional
+	ConfigOverlay map[string]string `json:"configOverlay,omitempty"`
+}
+
+// IsDisabled tests if the collection has been disabled by giving it a replication factor of 0 ...
+func (collection *SolrCollection) IsDisabled() bool {
+	return collection.ReplicationFactor != nil && *collection.ReplicationFactor == 0
+}
+
+// SolrRoutedAlias defines a Solr routed alias. See
+// https://solr.apache.org/guide/solr/latest/deployment-guide/alias-management.html#createalias
+type SolrRoutedAlias struct {
+	// The name of the alias.
+	//
+	// +kubebuilder:validation:Pattern:=[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=100
+	Name string `json:"name"`
+
+	// router The type of router (router.name), either time or category.
+	//
+	// +kubebuilder:validation:Enum:=time;category
+	Router string `json:"router"`
+
+	// field The field whose value determines which collection a document is routed to (router.field).
+	//
+	// +kubebuilder:validation:MinLength:=1
+	Field string `json:"field"`
+
+	// start The start of the first collection (router.start) e.g. NOW/DAY. Required by time routers.
+	//
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// interval The date math interval covered by each collection (router.interval) e.g. +1DAY. Required by time
+	// routers.
+	//
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// maxFutureMs How far into the future documents are accepted (router.maxFutureMs). Time routers only.
+	//
+	// +optional
+	MaxFutureMs *int64 `json:"maxFutureMs,omitempty"`
+
+	// maxCardinality The maximum number of categories (router.maxCardinality). Category routers only.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxCardinality *int32 `json:"maxCardinality,omitempty"`
+
+	// configsetName The name of the Kubernetes configmap that contains the schema for the collections created by the
+	// alias.
+	//
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=100
+	ConfigsetName string `json:"configsetName"`
 }
 
 // SolrCollectionSetStatus defines the observed state of SolrCollectionSet.
@@ -112,7 +502,9 @@ type SolrCollectionSetStatus struct {
 	//
 	// This collection should be treated as a map with a key of 'type'
 	//
-	// This operator has only one condition: Stable (ie is the collection set stable?)
+	// This operator has the conditions CollectionsReady, ConfigSetsSynced and ReplicasReady, and the aggregate condition
+	// Stable (ie is the collection set stable?). It also has the condition Initialized, which is True once the checksums
+	// collection has been set up (it isn't set on collection sets that are only being observed).
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -120,21 +512,178 @@ type SolrCollectionSetStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
-	// ReplicationFactor is the replication factor of the collection set. (Currently it's assumed that all collections
-	// in a set have the same replication factor)
+	// ReplicationFactor is the replication factor of the collection set. Collections may override it.
 	ReplicationFactor int32 `json:"replicationFactor"`
 
+	// Replicas is the number of replicas each shard of the collections is scaled to if it differs from the
+	// replication factor
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
 	// ReadyRatio is the ratio of specified collections to collections provisioned
 	ReadyRatio string `json:"readyRatio"`
 
-	// ScaleStatus is the overall scaling status of the collection set. V
+	// ScaleStatus is the overall scaling status of the collection set. If scaling is paused it's scalingPaused.
 	ScaleStatus string `json:"scaleStatus"`
 
-	// SolrNodes contain the statuses of each solr node running in this solr cloud.
+	// SolrCollections contain the statuses of each specified collection (each color of it if blue/green is enabled).
+	// They're sorted by name and then instance name, so the order is the same from one update to the next.
 	// +optional
 	// +listType:=map
 	// +listMapKey:=instanceName
 	SolrCollections []SolrCollectionStatus `json:"collections"`
+
+	// ConfigSets contain the statuses of each config set managed by the collection set, sorted by name.
+	// +optional
+	// +listType:=map
+	// +listMapKey:=name
+	ConfigSets []ConfigSetStatus `json:"configSets,omitempty"`
+
+	// ManagedCollections are the names of the collections in the cluster that are managed by this collection set. Only
+	// these collections are removed by cleanup, so that collection sets sharing a cluster don't remove each other's
+	// collections.
+	// +optional
+	// +listType:=set
+	ManagedCollections []string `json:"managedCollections,omitempty"`
+
+	// Aliases are the statuses of the aliases of the collections managed by the collection set (blue/green aliases, or
+	// every alias if createAliasesAlways is set). Aliases which are no longer managed (e.g. their collection was
+	// disabled) are listed until the operator has removed them.
+	// +optional
+	// +listType:=map
+	// +listMapKey:=name
+	Aliases []AliasStatus `json:"aliases,omitempty"`
+
+	// ObservedGeneration is the metadata.generation of the spec most recently reconciled successfully. If it's
+	// less than metadata.generation then the operator hasn't caught up with the latest spec change.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastReconcileRequest is the value of the solrcollections.solr.sis.uw.edu/reconcile-now annotation most recently
+	// acknowledged. Changing the annotation (e.g. to the current time) forces a reconcile right away, even if the set is
+	// backing off after errors.
+	// +optional
+	LastReconcileRequest string `json:"lastReconcileRequest,omitempty"`
+
+	// InterruptedOperation describes a Solr operation that was in-flight when the operator shut down (or the reconcile
+	// timed out), and why. It's cleared once a reconcile has got all the way through, finishing the operation if needed.
+	// +optional
+	InterruptedOperation string `json:"interruptedOperation,omitempty"`
+
+	// PendingOperations are the long-running Solr operations (e.g. shard splits) that have been submitted
+	// asynchronously and not finished yet. Later reconciles check on them rather than waiting on them.
+	// +optional
+	PendingOperations []AsyncOperation `json:"pendingOperations,omitempty"`
+
+	// DeleteFailures is the number of times in a row deleting a collection has failed, mapped by collection name. Once
+	// it reaches a limit the collection is force deleted.
+	// +optional
+	DeleteFailures map[string]int32 `json:"deleteFailures,omitempty"`
+
+	// MarkedForDeletion are the collections which have been removed from the spec and will be deleted once the cleanup
+	// grace period is up, mapped by collection name.
+	// +optional
+	MarkedForDeletion map[string]DeletionMark `json:"markedForDeletion,omitempty"`
+
+	// MissingNodes are the Solr nodes hosting replicas which aren't live, mapped to when they were first found missing.
+	// Once a node has been missing for replicaRepairDelaySeconds the replicas on it are replaced.
+	// +optional
+	MissingNodes map[string]metav1.Time `json:"missingNodes,omitempty"`
+
+	// ConsecutiveImmediateRequeues is the number of reconciles in a row that changed Solr and were requeued immediately
+	// without getting through the rest of the reconcile. Once it passes a limit the reconciles are delayed so that the
+	// operator doesn't hot-loop against Solr when a change never shows up in the cluster status.
+	// +optional
+	ConsecutiveImmediateRequeues int32 `json:"consecutiveImmediateRequeues,omitempty"`
+
+	// ConsecutiveErrors is the number of reconciles in a row that failed. The reconciles are delayed for longer the more
+	// of them fail, and the count is reset once a reconcile succeeds.
+	// +optional
+	ConsecutiveErrors int32 `json:"consecutiveErrors,omitempty"`
+
+	// UnstableSince is when the collection set last became unstable (i.e. the Stable condition became False). It's
+	// cleared once the collection set is stable again.
+	// +optional
+	UnstableSince *metav1.Time `json:"unstableSince,omitempty"`
+
+	// UnstableWarningEmitted indicates a warning event has been emitted because the collection set has been unstable
+	// for too long. Only one warning is emitted each time the collection set becomes unstable.
+	// +optional
+	UnstableWarningEmitted bool `json:"unstableWarningEmitted,omitempty"`
+
+	// ChecksumCollection is the status of the internal collection that holds the config set checksums
+	// +optional
+	ChecksumCollection *ChecksumCollectionStatus `json:"checksumCollection,omitempty"`
+
+	// CollectionStatsUpdatedAt is when the document counts and index sizes of the collections were last fetched
+	// +optional
+	CollectionStatsUpdatedAt *metav1.Time `json:"collectionStatsUpdatedAt,omitempty"`
+}
+
+// ChecksumCollectionStatus defines the observed state of the internal checksums collection.
+type ChecksumCollectionStatus struct {
+	// Name is the name of the checksums collection
+	Name string `json:"name"`
+	// Exists indicates whether the checksums collection exists in the Solr cluster
+	Exists bool `json:"exists"`
+	// Healthy indicates every shard of the checksums collection has a leader and all of its replicas are active
+	Healthy bool `json:"healthy"`
+	// ReplicationStatus is a string representing the actual number of replicas vs the replication factor ...
+	ReplicationStatus string `json:"replicationStatus"`
+	// RecordCount is the number of checksum records held for the collection set. It's omitted if the collection
+	// couldn't be queried.
+	// +optional
+	RecordCount *int64 `json:"recordCount,omitempty"`
+}
+
+// ConfigSetStatus defines the observed state of a Solr config set.
+type ConfigSetStatus struct {
+	// Name is the name of the config set in Solr
+	Name string `json:"name"`
+	// Checksum is the checksum of the config set that is currently uploaded to Solr
+	Checksum string `json:"checksum"`
+	// LastUpdated is the last time the operator uploaded the config set to Solr
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+	// LastVerified is the last time the operator found the config set in Solr to match its configmap (see
+	// verifyConfigSets)
+	// +optional
+	LastVerified *metav1.Time `json:"lastVerified,omitempty"`
+}
+
+// AliasStatus defines the observed state of an alias managed by the collection set.
+type AliasStatus struct {
+	// Name is the name of the alias
+	Name string `json:"name"`
+	// Targets are the collections the alias currently points at in Solr. It's empty if the alias doesn't exist.
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+	// DesiredTargets are the collections the alias should point at. It's empty if the collections don't exist (yet).
+	// +optional
+	DesiredTargets []string `json:"desiredTargets,omitempty"`
+	// InSync indicates the alias points at the collections it should
+	InSync bool `json:"inSync"`
+}
+
+// DeletionMark records when a collection was marked for deletion and when it will be deleted ...
+type DeletionMark struct {
+	// MarkedAt is when the collection was found missing from the spec
+	MarkedAt metav1.Time `json:"markedAt"`
+	// DeleteAfter is when the grace period is up and the collection will be deleted (if it's still missing from the
+	// spec)
+	DeleteAfter metav1.Time `json:"deleteAfter"`
+}
+
+// AsyncOperation records a Solr operation that was submitted asynchronously ...
+type AsyncOperation struct {
+	// Operation is the kind of operation (e.g. SplitShard)
+	Operation string `json:"operation"`
+	// Target is what the operation acts on (e.g. <collection>/<shard> for a shard split)
+	Target string `json:"target"`
+	// RequestId is the id the operation was submitted to Solr with, which its status is checked by
+	RequestId string `json:"requestId"`
+	// SubmittedAt is when the operation was submitted to Solr
+	SubmittedAt metav1.Time `json:"submittedAt"`
 }
 
 // SolrCollectionStatus defines the observed state of a SolrCollection.
@@ -156,8 +705,56 @@ type SolrCollectionStatus struct {
 	ReplicationFactor int32 `json:"replicationFactor"`
 	// ReplicaCount is the number of replicas of the collection
 	ReplicaCount int32 `json:"replicas"`
-	// ReplicationStatus is a string representing the desired number of replicas vs the actual number ...
+	// ReplicationStatus is a string representing the actual number of replicas vs the number of replicas the
+	// collection is scaled to ...
 	ReplicationStatus string `json:"replicationStatus"`
+	// TargetReplicas is the number of replicas each shard of the collection is scaled to
+	// +optional
+	TargetReplicas int32 `json:"targetReplicas,omitempty"`
+	// ReplicasNotActive is the number of replicas of the collection which aren't active (e.g. down or recovering)
+	// +optional
+	ReplicasNotActive int32 `json:"replicasNotActive,omitempty"`
+	// Disabled indicates the collection has been disabled with a replication factor of 0
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// CreatedAt is when the collection was created in Solr (if Solr reports it)
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+	// DocCount is the number of documents in the collection (if Solr reports it). It's refreshed periodically rather
+	// than on every reconcile.
+	// +optional
+	DocCount *int64 `json:"docCount,omitempty"`
+	// SizeBytes is the size of the index of the collection in bytes, not counting replicas (if Solr reports it). It's
+	// refreshed periodically rather than on every reconcile.
+	// +optional
+	SizeBytes *int64 `json:"sizeBytes,omitempty"`
+	// LastPromotedColor is the color the alias of the collection was last switched to because of a change to
+	// activeColor (blue/green only)
+	// +optional
+	LastPromotedColor string `json:"lastPromotedColor,omitempty"`
+	// LastPromotionTime is when the alias of the collection was last switched to another color
+	// +optional
+	LastPromotionTime *metav1.Time `json:"lastPromotionTime,omitempty"`
+	// Shards is the status of each active shard of the collection (sorted by name), since a single ReplicationStatus
+	// can hide that one shard of a multi-shard collection is under-replicated
+	// +optional
+	Shards []ShardStatus `json:"shards,omitempty"`
+}
+
+// ShardStatus is the status of a shard of a collection ...
+type ShardStatus struct {
+	// Name is the name of the shard (e.g. shard1)
+	Name string `json:"name"`
+	// ReplicaCount is the number of replicas of the managed type (i.e. not PULL replicas) on live nodes
+	ReplicaCount int32 `json:"replicas"`
+	// TargetReplicas is the number of replicas the shard is scaled to
+	TargetReplicas int32 `json:"targetReplicas"`
+	// ReplicasNotActive is the number of replicas of the shard which aren't active (e.g. down or recovering)
+	// +optional
+	ReplicasNotActive int32 `json:"replicasNotActive,omitempty"`
+	// Health sums up the shard. One of healthy, underReplicated, overReplicated or recovering.
+	// +kubebuilder:validation:Enum:=healthy;underReplicated;overReplicated;recovering
+	Health string `json:"health"`
 }
 
 // WithDefaults set default values when not defined in the spec.
@@ -180,12 +777,98 @@ func (spec *SolrCollectionSetSpec) withDefaults() (changed bool) {
 		spec.BlueGreenEnabled = &r
 	}
 
+	if spec.CreateAliasesAlways == nil {
+		changed = true
+		r := DefaultSolrCollectionSetCreateAliasesAlways
+		spec.CreateAliasesAlways = &r
+	}
+
+	if spec.ReconciledSettings == nil {
+		changed = true
+		spec.ReconciledSettings = slices.Clone(DefaultSolrCollectionSetReconciledSettings)
+	}
+
+	if spec.OptimizeAfterReload == nil {
+		changed = true
+		r := DefaultSolrCollectionSetOptimizeAfterReload
+		spec.OptimizeAfterReload = &r
+	}
+
+	if spec.DefaultColor == "" {
+		changed = true
+		spec.DefaultColor = DefaultSolrCollectionSetDefaultColor
+	}
+
+	if spec.Mode == "" {
+		changed = true
+		spec.Mode = DefaultSolrCollectionSetMode
+	}
+
+	if spec.SolrScheme == "" {
+		changed = true
+		spec.SolrScheme = DefaultSolrCollectionSetSolrScheme
+	}
+
+	if spec.SolrPort == nil {
+		changed = true
+		r := DefaultSolrCollectionSetSolrPort
+		spec.SolrPort = &r
+	}
+
 	if spec.CleanupEnabled == nil {
 		changed = true
 		r := DefaultSolrCollectionSetCleanupEnabled
 		spec.CleanupEnabled = &r
 	}
 
+	if spec.CleanupMaxDeletions == nil {
+		changed = true
+		r := DefaultSolrCollectionSetCleanupMaxDeletions
+		spec.CleanupMaxDeletions = &r
+	}
+
+	if spec.CleanupMaxPercent == nil {
+		changed = true
+		r := DefaultSolrCollectionSetCleanupMaxPercent
+		spec.CleanupMaxPercent = &r
+	}
+
+	if spec.ReplicaRepairDelaySeconds == nil {
+		changed = true
+		r := DefaultSolrCollectionSetReplicaRepairDelaySeconds
+		spec.ReplicaRepairDelaySeconds = &r
+	}
+
+	if spec.SharedChecksums == nil {
+		changed = true
+		r := DefaultSolrCollectionSetSharedChecksums
+		spec.SharedChecksums = &r
+	}
+
+	if spec.VerifyConfigSets == nil {
+		changed = true
+		r := DefaultSolrCollectionSetVerifyConfigSets
+		spec.VerifyConfigSets = &r
+	}
+
+	if spec.ScalingEnabled == nil {
+		changed = true
+		r := DefaultSolrCollectionSetScalingEnabled
+		spec.ScalingEnabled = &r
+	}
+
+	if spec.ScopeClusterStatus == nil {
+		changed = true
+		r := DefaultSolrCollectionSetScopeStatus
+		spec.ScopeClusterStatus = &r
+	}
+
+	if spec.AutoAddReplicas == nil {
+		changed = true
+		r := DefaultSolrCollectionAutoAddReplicas
+		spec.AutoAddReplicas = &r
+	}
+
 	if spec.ReplicationFactor == nil {
 		changed = true
 		r := DefaultSolrCollectionReplicationFactor
@@ -195,6 +878,58 @@ func (spec *SolrCollectionSetSpec) withDefaults() (changed bool) {
 	return changed
 }
 
+// ChecksumCollectionReplicationFactor returns the replication factor of the checksums collection. This isn't filled
+// in by withDefaults() so that it keeps following the replication factor of the set when that changes ...
+func (spec *SolrCollectionSetSpec) ChecksumCollectionReplicationFactor() int32 {
+	if spec.ChecksumReplicationFactor != nil {
+		return *spec.ChecksumReplicationFactor
+	}
+	return *spec.ReplicationFactor
+}
+
+// CollectionReplicationFactor returns the replication factor of the given collection, which is the replication factor
+// of the set unless the collection overrides it ...
+func (spec *SolrCollectionSetSpec) CollectionReplicationFactor(collection SolrCollection) int32 {
+	if collection.ReplicationFactor != nil {
+		return *collection.ReplicationFactor
+	}
+	return *spec.ReplicationFactor
+}
+
+// ReconcilesSetting tests if the given collection setting (e.g. SolrCollectionSettingReplicationFactor) is kept in
+// step with the spec on existing collections ...
+func (spec *SolrCollectionSetSpec) ReconcilesSetting(setting string) bool {
+	return slices.Contains(spec.ReconciledSettings, setting)
+}
+
+// CollectionNumShards returns the number of shards the given collection is created with ...
+func (spec *SolrCollectionSetSpec) CollectionNumShards(collection SolrCollection) int32 {
+	if collection.NumShards != nil {
+		return *collection.NumShards
+	}
+	return 1
+}
+
+// CollectionReplicas returns the number of replicas each shard of the given collection is scaled to ...
+func (spec *SolrCollectionSetSpec) CollectionReplicas(collection SolrCollection) int32 {
+	if collection.IsDisabled() {
+		return 0
+	}
+	if spec.Replicas != nil {
+		return *spec.Replicas
+	}
+	return spec.CollectionReplicationFactor(collection)
+}
+
+// InstanceReplicas returns the number of replicas each shard of an instance (i.e. a color) of the given collection is
+// scaled to, depending on whether the instance is active ...
+func (spec *SolrCollectionSetSpec) InstanceReplicas(collection SolrCollection, active bool) int32 {
+	if !active && spec.InactiveReplicas != nil && !collection.IsDisabled() && !collection.AliasAllColors {
+		return *spec.InactiveReplicas
+	}
+	return spec.CollectionReplicas(collection)
+}
+
 // SetCollectionDefaults sets collection defaults
 func (sc SolrCollectionSet) SetCollectionDefaults(logger logr.Logger) (changed bool) {
 	for i := range sc.Spec.Collections {
@@ -222,6 +957,7 @@ func (sc SolrCollectionSet) SetCollectionDefaults(logger logr.Logger) (changed b
 // +kubebuilder:printcolumn:name="SCALEING",type="string",JSONPath=".status.scaleStatus",description="The overall scaling status of the collection set."
 // +kubebuilder:printcolumn:name="COLS",type="string",JSONPath=".status.readyRatio",description="The ratio of defined vs provisioned collections in the set"
 // +kubebuilder:printcolumn:name="R-FAC",type="integer",JSONPath=".spec.replicationFactor",description="The replication factor of the collection set"
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".spec.replicas",description="The number of replicas per shard if it differs from the replication factor",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 //
 // SolrCollectionSet is the Schema for the solrcollectionsets API
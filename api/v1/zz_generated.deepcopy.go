@@ -25,9 +25,157 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AliasStatus) DeepCopyInto(out *AliasStatus) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DesiredTargets != nil {
+		in, out := &in.DesiredTargets, &out.DesiredTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AliasStatus.
+func (in *AliasStatus) DeepCopy() *AliasStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AliasStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AsyncOperation) DeepCopyInto(out *AsyncOperation) {
+	*out = *in
+	in.SubmittedAt.DeepCopyInto(&out.SubmittedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AsyncOperation.
+func (in *AsyncOperation) DeepCopy() *AsyncOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(AsyncOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChecksumCollectionStatus) DeepCopyInto(out *ChecksumCollectionStatus) {
+	*out = *in
+	if in.RecordCount != nil {
+		in, out := &in.RecordCount, &out.RecordCount
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChecksumCollectionStatus.
+func (in *ChecksumCollectionStatus) DeepCopy() *ChecksumCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChecksumCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSetStatus) DeepCopyInto(out *ConfigSetStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.LastVerified != nil {
+		in, out := &in.LastVerified, &out.LastVerified
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSetStatus.
+func (in *ConfigSetStatus) DeepCopy() *ConfigSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionMark) DeepCopyInto(out *DeletionMark) {
+	*out = *in
+	in.MarkedAt.DeepCopyInto(&out.MarkedAt)
+	in.DeleteAfter.DeepCopyInto(&out.DeleteAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionMark.
+func (in *DeletionMark) DeepCopy() *DeletionMark {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionMark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardStatus) DeepCopyInto(out *ShardStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardStatus.
+func (in *ShardStatus) DeepCopy() *ShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SolrCollection) DeepCopyInto(out *SolrCollection) {
 	*out = *in
+	if in.ReplicationFactor != nil {
+		in, out := &in.ReplicationFactor, &out.ReplicationFactor
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumShards != nil {
+		in, out := &in.NumShards, &out.NumShards
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WaitForFinalState != nil {
+		in, out := &in.WaitForFinalState, &out.WaitForFinalState
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxShardsPerNode != nil {
+		in, out := &in.MaxShardsPerNode, &out.MaxShardsPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CreateNodeSet != nil {
+		in, out := &in.CreateNodeSet, &out.CreateNodeSet
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigOverlay != nil {
+		in, out := &in.ConfigOverlay, &out.ConfigOverlay
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SolrCollection.
@@ -102,6 +250,11 @@ func (in *SolrCollectionSetList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SolrCollectionSetSpec) DeepCopyInto(out *SolrCollectionSetSpec) {
 	*out = *in
+	if in.SolrPort != nil {
+		in, out := &in.SolrPort, &out.SolrPort
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Active != nil {
 		in, out := &in.Active, &out.Active
 		*out = new(bool)
@@ -112,20 +265,124 @@ func (in *SolrCollectionSetSpec) DeepCopyInto(out *SolrCollectionSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ScalingEnabled != nil {
+		in, out := &in.ScalingEnabled, &out.ScalingEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScaleOutBatchSize != nil {
+		in, out := &in.ScaleOutBatchSize, &out.ScaleOutBatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InactiveReplicas != nil {
+		in, out := &in.InactiveReplicas, &out.InactiveReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ChecksumReplicationFactor != nil {
+		in, out := &in.ChecksumReplicationFactor, &out.ChecksumReplicationFactor
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CreateNodeSet != nil {
+		in, out := &in.CreateNodeSet, &out.CreateNodeSet
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedChecksums != nil {
+		in, out := &in.SharedChecksums, &out.SharedChecksums
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifyConfigSets != nil {
+		in, out := &in.VerifyConfigSets, &out.VerifyConfigSets
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScopeClusterStatus != nil {
+		in, out := &in.ScopeClusterStatus, &out.ScopeClusterStatus
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoAddReplicas != nil {
+		in, out := &in.AutoAddReplicas, &out.AutoAddReplicas
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplicaRepairDelaySeconds != nil {
+		in, out := &in.ReplicaRepairDelaySeconds, &out.ReplicaRepairDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReconciledSettings != nil {
+		in, out := &in.ReconciledSettings, &out.ReconciledSettings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.BlueGreenEnabled != nil {
 		in, out := &in.BlueGreenEnabled, &out.BlueGreenEnabled
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CreateAliasesAlways != nil {
+		in, out := &in.CreateAliasesAlways, &out.CreateAliasesAlways
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OptimizeAfterReload != nil {
+		in, out := &in.OptimizeAfterReload, &out.OptimizeAfterReload
+		*out = new(bool)
+		**out = **in
+	}
 	if in.CleanupEnabled != nil {
 		in, out := &in.CleanupEnabled, &out.CleanupEnabled
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MaxCollections != nil {
+		in, out := &in.MaxCollections, &out.MaxCollections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinLiveNodes != nil {
+		in, out := &in.MinLiveNodes, &out.MinLiveNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CleanupGracePeriodSeconds != nil {
+		in, out := &in.CleanupGracePeriodSeconds, &out.CleanupGracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CleanupMaxDeletions != nil {
+		in, out := &in.CleanupMaxDeletions, &out.CleanupMaxDeletions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CleanupMaxPercent != nil {
+		in, out := &in.CleanupMaxPercent, &out.CleanupMaxPercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Collections != nil {
 		in, out := &in.Collections, &out.Collections
 		*out = make([]SolrCollection, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoutedAliases != nil {
+		in, out := &in.RoutedAliases, &out.RoutedAliases
+		*out = make([]SolrRoutedAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -152,8 +409,70 @@ func (in *SolrCollectionSetStatus) DeepCopyInto(out *SolrCollectionSetStatus) {
 	if in.SolrCollections != nil {
 		in, out := &in.SolrCollections, &out.SolrCollections
 		*out = make([]SolrCollectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigSets != nil {
+		in, out := &in.ConfigSets, &out.ConfigSets
+		*out = make([]ConfigSetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedCollections != nil {
+		in, out := &in.ManagedCollections, &out.ManagedCollections
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]AliasStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingOperations != nil {
+		in, out := &in.PendingOperations, &out.PendingOperations
+		*out = make([]AsyncOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeleteFailures != nil {
+		in, out := &in.DeleteFailures, &out.DeleteFailures
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MarkedForDeletion != nil {
+		in, out := &in.MarkedForDeletion, &out.MarkedForDeletion
+		*out = make(map[string]DeletionMark, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MissingNodes != nil {
+		in, out := &in.MissingNodes, &out.MissingNodes
+		*out = make(map[string]metav1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.UnstableSince != nil {
+		in, out := &in.UnstableSince, &out.UnstableSince
+		*out = (*in).DeepCopy()
+	}
+	if in.ChecksumCollection != nil {
+		in, out := &in.ChecksumCollection, &out.ChecksumCollection
+		*out = new(ChecksumCollectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CollectionStatsUpdatedAt != nil {
+		in, out := &in.CollectionStatsUpdatedAt, &out.CollectionStatsUpdatedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SolrCollectionSetStatus.
@@ -169,6 +488,29 @@ func (in *SolrCollectionSetStatus) DeepCopy() *SolrCollectionSetStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SolrCollectionStatus) DeepCopyInto(out *SolrCollectionStatus) {
 	*out = *in
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.DocCount != nil {
+		in, out := &in.DocCount, &out.DocCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SizeBytes != nil {
+		in, out := &in.SizeBytes, &out.SizeBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastPromotionTime != nil {
+		in, out := &in.LastPromotionTime, &out.LastPromotionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]ShardStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SolrCollectionStatus.
@@ -180,3 +522,28 @@ func (in *SolrCollectionStatus) DeepCopy() *SolrCollectionStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SolrRoutedAlias) DeepCopyInto(out *SolrRoutedAlias) {
+	*out = *in
+	if in.MaxFutureMs != nil {
+		in, out := &in.MaxFutureMs, &out.MaxFutureMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxCardinality != nil {
+		in, out := &in.MaxCardinality, &out.MaxCardinality
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SolrRoutedAlias.
+func (in *SolrRoutedAlias) DeepCopy() *SolrRoutedAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(SolrRoutedAlias)
+	in.DeepCopyInto(out)
+	return out
+}
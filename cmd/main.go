@@ -20,15 +20,21 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -37,6 +43,7 @@ import (
 
 	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
 	"github.com/uw-it-sis/solr-collections-operator/internal/controller"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -54,6 +61,11 @@ func init() {
 
 // nolint:gocyclo
 func main() {
+	// The validate command checks SolrCollectionSet manifests offline (e.g. in CI) rather than running the operator ...
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:], os.Stdout))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -61,13 +73,30 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var solrRequestsPerSecond float64
+	var solrRequestBurst int
+	var solrCommitStrategy string
+	var solrCommitWithinMillis int
+	var solrChecksumsFromLeader bool
+	var maxConcurrentReconciles int
+	var configSetCacheSize int
+	var configSetVerifyInterval time.Duration
+	var auditSolrMutations bool
+	var collectionStatsInterval time.Duration
+	var unstableWarningThreshold time.Duration
+	var reconcileTimeout time.Duration
+	var collectionCreateWait time.Duration
+	var defaultSolrClusterUrl, defaultSolrSecretName, defaultSolrSecretNamespace string
+	var solrCredentialsPath string
+	var watchNamespaces string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+			"Enabling this will ensure there is only one active controller manager. "+
+			"It must be enabled when running more than one replica, otherwise the replicas race each other in Solr.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -79,6 +108,50 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.Float64Var(&solrRequestsPerSecond, "solr-requests-per-second", 0,
+		"The maximum number of Solr API requests per second across all reconciles. Use 0 to disable throttling.")
+	flag.IntVar(&solrRequestBurst, "solr-request-burst", 5,
+		"The number of Solr API requests allowed to burst above the per second limit.")
+	flag.StringVar(&solrCommitStrategy, "solr-commit-strategy", solr.CommitStrategyHard,
+		"How records written to Solr are committed. One of commit, softCommit, or commitWithin.")
+	flag.IntVar(&solrCommitWithinMillis, "solr-commit-within-ms", 1000,
+		"The commitWithin time in milliseconds used when --solr-commit-strategy=commitWithin.")
+	flag.BoolVar(&solrChecksumsFromLeader, "solr-checksums-from-leader", false,
+		"If set, config set checksums are read from and written to the checksums collection's leader core directly "+
+			"to avoid reading stale checksums. The operator must be able to reach the Solr nodes' base URLs.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of SolrCollectionSets that can be reconciled at the same time.")
+	flag.IntVar(&configSetCacheSize, "config-set-cache-size", 32,
+		"The number of decoded/zipped config sets kept in memory so that unchanged config sets aren't decoded or "+
+			"zipped on every reconcile. Use 0 to disable the cache.")
+	flag.DurationVar(&configSetVerifyInterval, "config-set-verify-interval", 15*time.Minute,
+		"How often the config sets of SolrCollectionSets with verifyConfigSets set are downloaded from Solr and "+
+			"compared with their configmaps. Use 0 to compare them on every reconcile.")
+	flag.BoolVar(&auditSolrMutations, "audit-solr-mutations", true,
+		"If set, every call that changes Solr is written to stdout as a line of JSON for auditing.")
+	flag.DurationVar(&collectionStatsInterval, "collection-stats-interval", 15*time.Minute,
+		"How often the document counts and index sizes of collections are fetched into the status. Use 0 to disable.")
+	flag.DurationVar(&unstableWarningThreshold, "unstable-warning-threshold", 15*time.Minute,
+		"How long a SolrCollectionSet can be unstable before a warning event is emitted. Use 0 to disable the warning.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 0,
+		"How long a single reconcile can run before it's cut short and retried with backoff. Use 0 (the default) for "+
+			"no timeout.")
+	flag.DurationVar(&collectionCreateWait, "collection-create-wait", time.Minute,
+		"How long a reconcile waits for a new collection to become active with all of its replicas before leaving it "+
+			"to the next reconcile. Use 0 to not wait.")
+	flag.StringVar(&defaultSolrClusterUrl, "default-solr-cluster-url", "",
+		"The Solr cluster URL used by SolrCollectionSets that don't specify a clusterUrl.")
+	flag.StringVar(&defaultSolrSecretName, "default-solr-secret-name", "",
+		"The basic auth secret used by SolrCollectionSets that don't specify a secretName.")
+	flag.StringVar(&defaultSolrSecretNamespace, "default-solr-secret-namespace", "default",
+		"The namespace that Solr basic auth secrets are read from.")
+	flag.StringVar(&solrCredentialsPath, "solr-credentials-path", "",
+		"A directory holding the files username, password and/or token (e.g. a mounted secret), or a file holding a "+
+			"bearer token (e.g. a projected service account token), that Solr credentials are read from on every "+
+			"request. Used by SolrCollectionSets that don't specify a secretName, in place of --default-solr-secret-name.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"A comma separated list of the namespaces whose SolrCollectionSets are reconciled. Use an empty list to "+
+			"reconcile SolrCollectionSets in every namespace.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -161,6 +234,7 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "32dfa4a4.solr.sis.uw.edu",
+		Cache:                  watchNamespacesCacheOptions(watchNamespaces, defaultSolrSecretNamespace),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -177,11 +251,54 @@ func main() {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	if !enableLeaderElection {
+		setupLog.Info("leader election is disabled so only one replica of the operator should be run")
+	}
+
+	switch solrCommitStrategy {
+	case solr.CommitStrategyHard, solr.CommitStrategySoft, solr.CommitStrategyWithin:
+	default:
+		setupLog.Error(nil, "invalid Solr commit strategy", "solr-commit-strategy", solrCommitStrategy)
+		os.Exit(1)
+	}
+
+	// Requests to the Solr API block (rather than fail) when the rate limit is hit ...
+	var solrRateLimiter *rate.Limiter
+	if solrRequestsPerSecond > 0 {
+		setupLog.Info("throttling Solr API requests",
+			"solr-requests-per-second", solrRequestsPerSecond, "solr-request-burst", solrRequestBurst)
+		solrRateLimiter = rate.NewLimiter(rate.Limit(solrRequestsPerSecond), solrRequestBurst)
+	}
+
+	var auditor controller.Auditor
+	if auditSolrMutations {
+		auditor = controller.NewJSONAuditor(os.Stdout)
+	}
 
 	if err := (&controller.SolrCollectionSetReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("solrcollectionset-controller"),
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("solrcollectionset-controller"),
+		SolrRateLimiter: solrRateLimiter,
+
+		SolrCommitStrategy:      solrCommitStrategy,
+		SolrCommitWithinMillis:  solrCommitWithinMillis,
+		SolrChecksumsFromLeader: solrChecksumsFromLeader,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ConfigSetCacheSize:      configSetCacheSize,
+		ConfigSetVerifyInterval: configSetVerifyInterval,
+		Auditor:                 auditor,
+		CollectionStatsInterval: collectionStatsInterval,
+		Elected:                 mgr.Elected(),
+
+		UnstableWarningThreshold: unstableWarningThreshold,
+		ReconcileTimeout:         reconcileTimeout,
+		CollectionCreateWait:     collectionCreateWait,
+
+		DefaultSolrClusterUrl:      defaultSolrClusterUrl,
+		DefaultSolrSecretName:      defaultSolrSecretName,
+		DefaultSolrSecretNamespace: defaultSolrSecretNamespace,
+		SolrCredentialsPath:        solrCredentialsPath,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SolrCollectionSet")
 		os.Exit(1)
@@ -203,3 +320,28 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// watchNamespacesCacheOptions scopes the manager's cache to the given (comma separated) namespaces, so that only the
+// collection sets (and their config maps) in those namespaces are watched and reconciled. The Solr basic auth secrets
+// are read from the secret namespace whether it's watched or not. If no namespaces are given everything is watched ...
+func watchNamespacesCacheOptions(watchNamespaces string, secretNamespace string) cache.Options {
+	namespaces := make(map[string]cache.Config)
+	for _, namespace := range strings.Split(watchNamespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces[namespace] = cache.Config{}
+		}
+	}
+	if len(namespaces) == 0 {
+		return cache.Options{}
+	}
+	if secretNamespace == "" {
+		secretNamespace = "default"
+	}
+	setupLog.Info("only watching SolrCollectionSets in the given namespaces", "watch-namespaces", watchNamespaces)
+	return cache.Options{
+		DefaultNamespaces: namespaces,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {Namespaces: map[string]cache.Config{secretNamespace: {}}},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWatchNamespacesCacheOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		watchNamespaces string
+		secretNamespace string
+		namespaces      []string
+		// The namespace the Solr basic auth secrets are read from, if the cache is scoped at all ...
+		secretsFrom string
+	}{
+		{name: "everything", watchNamespaces: ""},
+		{name: "only separators", watchNamespaces: " , ,"},
+		{name: "one namespace", watchNamespaces: "books", secretNamespace: "solr", namespaces: []string{"books"},
+			secretsFrom: "solr"},
+		{name: "several namespaces", watchNamespaces: "books, authors,,series ", secretNamespace: "solr",
+			namespaces: []string{"authors", "books", "series"}, secretsFrom: "solr"},
+		{name: "default secret namespace", watchNamespaces: "books", namespaces: []string{"books"},
+			secretsFrom: "default"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := watchNamespacesCacheOptions(test.watchNamespaces, test.secretNamespace)
+			if test.namespaces == nil {
+				if options.DefaultNamespaces != nil || options.ByObject != nil {
+					t.Fatalf("expected everything to be watched, got %+v", options)
+				}
+				return
+			}
+			namespaces := slices.Sorted(maps.Keys(options.DefaultNamespaces))
+			if !reflect.DeepEqual(namespaces, test.namespaces) {
+				t.Fatalf("expected namespaces %v, got %v", test.namespaces, namespaces)
+			}
+			// The secrets are read from the secret namespace whether it's watched or not ...
+			var secretsFrom []string
+			for object, byObject := range options.ByObject {
+				if _, isSecret := object.(*corev1.Secret); isSecret {
+					secretsFrom = slices.Sorted(maps.Keys(byObject.Namespaces))
+				}
+			}
+			if expected := []string{test.secretsFrom}; !reflect.DeepEqual(secretsFrom, expected) {
+				t.Fatalf("expected secrets to be read from %v, got %v", expected, secretsFrom)
+			}
+		})
+	}
+}
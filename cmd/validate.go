@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	"github.com/uw-it-sis/solr-collections-operator/internal/controller"
+)
+
+// validationReport is what the validate command writes out ...
+type validationReport struct {
+	Valid  bool                         `json:"valid"`
+	Issues []controller.ValidationIssue `json:"issues"`
+}
+
+// fileList collects a flag which can be given more than once ...
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runValidate checks the SolrCollectionSets (and their config set configmaps) in the given manifests without
+// connecting to Kubernetes or Solr, and writes the problems found as JSON. The exit code is 1 if any problems were
+// found and 2 if the manifests couldn't be read ...
+func runValidate(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	var files fileList
+	flags.Var(&files, "f", "A manifest (YAML or JSON, may hold several documents) with SolrCollectionSets and the "+
+		"configmaps holding their config sets. Can be given more than once. Use - for stdin.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	files = append(files, flags.Args()...)
+	if len(files) == 0 {
+		_, _ = fmt.Fprintln(flags.Output(), "validate: no manifests given")
+		return 2
+	}
+
+	var collectionSets []solrcollectionsv1.SolrCollectionSet
+	var configMaps []corev1.ConfigMap
+	for _, file := range files {
+		err := readManifests(file, &collectionSets, &configMaps)
+		if err != nil {
+			_, _ = fmt.Fprintf(flags.Output(), "validate: %s: %v\n", file, err)
+			return 2
+		}
+	}
+
+	report := validationReport{Issues: []controller.ValidationIssue{}}
+	for _, collectionSet := range collectionSets {
+		report.Issues = append(report.Issues, controller.ValidateCollectionSet(collectionSet, configMaps)...)
+	}
+	report.Valid = len(report.Issues) == 0
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return 2
+	}
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
+
+// readManifests reads the SolrCollectionSets and configmaps from the given file. Other kinds of objects are skipped ...
+func readManifests(file string, collectionSets *[]solrcollectionsv1.SolrCollectionSet,
+	configMaps *[]corev1.ConfigMap) error {
+
+	var reader io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		reader = f
+	}
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		var document runtime.RawExtension
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(document.Raw) == 0 {
+			continue
+		}
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(document.Raw, &typeMeta); err != nil {
+			return err
+		}
+		switch typeMeta.Kind {
+		case "SolrCollectionSet":
+			var collectionSet solrcollectionsv1.SolrCollectionSet
+			if err := json.Unmarshal(document.Raw, &collectionSet); err != nil {
+				return fmt.Errorf("SolrCollectionSet: %w", err)
+			}
+			*collectionSets = append(*collectionSets, collectionSet)
+		case "ConfigMap":
+			var configMap corev1.ConfigMap
+			if err := json.Unmarshal(document.Raw, &configMap); err != nil {
+				return fmt.Errorf("ConfigMap: %w", err)
+			}
+			*configMaps = append(*configMaps, configMap)
+		}
+	}
+}
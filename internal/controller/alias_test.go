@@ -0,0 +1,407 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestManageAliasesOnlyAssignsDifferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		aliases  map[string][]string
+		expected string
+	}{
+		{name: "already correct", aliases: map[string][]string{"books": {"books_blue"}, "authors": {"authors_green", "authors_blue"}}},
+		{name: "drifted", aliases: map[string][]string{"books": {"books_green"}, "authors": {"authors_green", "authors_blue"}}, expected: "books=books_blue"},
+		{name: "missing", aliases: map[string][]string{"authors": {"authors_blue", "authors_green"}}, expected: "books=books_blue"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &fakeSolr{strict: true, responses: map[string]string{"CREATEALIAS": ""}}
+			solrClient := fake.start(t)
+			r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+			blueGreenEnabled := true
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				BlueGreenEnabled: &blueGreenEnabled,
+				Collections: []solrcollectionsv1.SolrCollection{
+					{Name: "books", ActiveColor: "blue"},
+					{Name: "authors", AliasAllColors: true},
+				},
+			})
+
+			clusterStatus := solr.ClusterStatus{
+				Collections: map[string]solr.Collection{
+					"books_blue": {}, "books_green": {}, "authors_blue": {}, "authors_green": {},
+				},
+				Aliases: test.aliases,
+			}
+			changed := r.ManageAliases(context.Background(), solrClient, &collectionSet, clusterStatus, nil)
+
+			assigned := fake.assigned()
+			if test.expected == "" {
+				if changed || len(assigned) > 0 {
+					t.Fatalf("expected no aliases to be assigned, got %v", assigned)
+				}
+				return
+			}
+			if !changed || len(assigned) != 1 || assigned[0] != test.expected {
+				t.Fatalf("expected [%s] to be assigned, got %v", test.expected, assigned)
+			}
+		})
+	}
+}
+
+func TestManageAliasesWithoutBlueGreen(t *testing.T) {
+	fake := &fakeSolr{}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	blueGreenEnabled := false
+	createAliasesAlways := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled:    &blueGreenEnabled,
+		CreateAliasesAlways: &createAliasesAlways,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", Alias: "library"},
+			{Name: "authors"},
+		},
+	})
+	clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{"books": {}, "authors": {}}}
+
+	// By default there are no aliases without blue/green ...
+	if r.ManageAliases(context.Background(), solrClient, &collectionSet, clusterStatus, nil) ||
+		len(fake.assigned()) > 0 {
+		t.Fatalf("expected no aliases to be assigned, got %v", fake.assigned())
+	}
+
+	// ... unless they're always created, and then only for collections with an alias of their own ...
+	createAliasesAlways = true
+	if !r.ManageAliases(context.Background(), solrClient, &collectionSet, clusterStatus, nil) ||
+		!reflect.DeepEqual(fake.assigned(), []string{"library=books"}) {
+		t.Fatalf("expected [library=books] to be assigned, got %v", fake.assigned())
+	}
+}
+
+func TestAliasStatusesOf(t *testing.T) {
+	blueGreenEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ActiveColor: "blue"},
+			{Name: "authors"},
+			{Name: "titles"},
+		},
+	})
+
+	clusterStatus := solr.ClusterStatus{
+		Collections: map[string]solr.Collection{"books_blue": {}, "books_green": {}, "authors_green": {}},
+		Aliases:     map[string][]string{"books": {"books_green"}, "authors": {"authors_green"}, "other": {"x"}},
+	}
+	statuses := aliasStatusesOf(collectionSet, clusterStatus, nil)
+
+	expected := []solrcollectionsv1.AliasStatus{
+		{Name: "authors", Targets: []string{"authors_green"}, DesiredTargets: []string{"authors_green"}, InSync: true},
+		{Name: "books", Targets: []string{"books_green"}, DesiredTargets: []string{"books_blue"}},
+		{Name: "titles"},
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, statuses)
+	}
+}
+
+func TestManageAliasesRecordsPromotions(t *testing.T) {
+	solrClient := (&fakeSolr{}).start(t)
+
+	blueGreenEnabled := true
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books", ActiveColor: "green"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		SolrCollections: []solrcollectionsv1.SolrCollectionStatus{
+			{Name: "books", InstanceName: "books_blue", BlueGreen: true, Active: true},
+			{Name: "books", InstanceName: "books_green", BlueGreen: true},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	recorder := record.NewFakeRecorder(100)
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: recorder,
+	}
+	clusterStatus := solr.ClusterStatus{
+		Collections: map[string]solr.Collection{"books_blue": {}, "books_green": {}},
+		Aliases:     map[string][]string{"books": {"books_blue"}},
+	}
+	ctx := context.Background()
+
+	previouslyActive := activeInstances(collectionSet.Status.SolrCollections)
+	if !r.ManageAliases(ctx, solrClient, collectionSet, clusterStatus, previouslyActive) {
+		t.Fatalf("expected the alias to be repointed")
+	}
+	if event := <-recorder.Events; !strings.Contains(event, eventSolrCollectionSetPromotedColor) ||
+		!strings.Contains(event, "from [blue] to [green]") {
+		t.Fatalf("expected a promotion event, got [%s]", event)
+	}
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+		t.Fatalf("get collection set failed: %v", err)
+	}
+	for _, collectionStatus := range collectionSet.Status.SolrCollections {
+		if collectionStatus.LastPromotedColor != "green" || collectionStatus.LastPromotionTime == nil {
+			t.Fatalf("expected the promotion to green to be recorded, got %+v", collectionStatus)
+		}
+	}
+}
+
+func TestManageAliasesRemovesAliasesNoLongerManaged(t *testing.T) {
+	fake := &fakeSolr{}
+	solrClient := fake.start(t)
+
+	blueGreenEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		// The alias of books was renamed from "books" to "library" and titles hasn't been created yet ...
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books", Alias: "library", ActiveColor: "blue"},
+			{Name: "titles"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		Aliases: []solrcollectionsv1.AliasStatus{{Name: "books"}, {Name: "library"}, {Name: "titles"},
+			{Name: "removed"}},
+	}
+	clusterStatus := solr.ClusterStatus{
+		Collections: map[string]solr.Collection{"books_blue": {}, "books_green": {}},
+		Aliases: map[string][]string{"books": {"books_blue"}, "library": {"books_blue"}, "titles": {"titles_blue"},
+			"other": {"x"}},
+	}
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	// Only the alias which is in Solr, but no longer managed, is removed. Aliases the set never managed are left
+	// alone ...
+	if !r.ManageAliases(context.Background(), solrClient, &collectionSet, clusterStatus, nil) {
+		t.Fatalf("expected the alias to be removed")
+	}
+	if calls := fake.called(); !reflect.DeepEqual(calls, []string{"DELETEALIAS books"}) {
+		t.Fatalf("expected only alias [books] to be removed, got %v", calls)
+	}
+
+	// It's still reported until it's gone ...
+	var names []string
+	for _, aliasStatus := range aliasStatusesOf(collectionSet, clusterStatus, nil) {
+		names = append(names, aliasStatus.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"books", "library", "titles"}) {
+		t.Fatalf("expected aliases [books library titles] to be reported, got %v", names)
+	}
+	delete(clusterStatus.Aliases, "books")
+	names = nil
+	for _, aliasStatus := range aliasStatusesOf(collectionSet, clusterStatus, nil) {
+		names = append(names, aliasStatus.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"library", "titles"}) {
+		t.Fatalf("expected aliases [library titles] to be reported, got %v", names)
+	}
+}
+
+func TestExpectedActiveInstance(t *testing.T) {
+	bothColors := map[string]solr.Collection{"books_blue": {}, "books_green": {}}
+	tests := []struct {
+		name             string
+		activeColor      string
+		aliasTargets     []string
+		collections      map[string]solr.Collection
+		previouslyActive map[string]string
+		expected         string
+	}{
+		{name: "color in the spec", activeColor: "green", aliasTargets: []string{"books_blue"},
+			collections: bothColors, previouslyActive: map[string]string{"books": "books_blue"}, expected: "books_green"},
+		{name: "color in the spec doesn't exist yet", activeColor: "green",
+			collections: map[string]solr.Collection{"books_blue": {}}},
+		// The alias was repointed outside the operator, so it's pointed back ...
+		{name: "previously active", aliasTargets: []string{"books_green"}, collections: bothColors,
+			previouslyActive: map[string]string{"books": "books_blue"}, expected: "books_blue"},
+		{name: "current alias target", aliasTargets: []string{"books_green"}, collections: bothColors,
+			expected: "books_green"},
+		{name: "alias pointing elsewhere", aliasTargets: []string{"authors_green"}, collections: bothColors,
+			expected: "books_blue"},
+		{name: "only one color exists", collections: map[string]solr.Collection{"books_green": {}},
+			expected: "books_green"},
+		{name: "no colors exist", collections: map[string]solr.Collection{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := solrcollectionsv1.SolrCollection{Name: "books", ActiveColor: test.activeColor}
+			expected := expectedActiveInstance(spec, "blue", test.aliasTargets, test.collections,
+				test.previouslyActive)
+			if expected != test.expected {
+				t.Fatalf("expected [%s], got [%s]", test.expected, expected)
+			}
+		})
+	}
+}
+
+func TestActiveInstances(t *testing.T) {
+	statuses := []solrcollectionsv1.SolrCollectionStatus{
+		{Name: "books", InstanceName: "books_blue", BlueGreen: true, Active: true},
+		{Name: "books", InstanceName: "books_green", BlueGreen: true},
+		// Both colors of authors are active so which one is active is ambiguous ...
+		{Name: "authors", InstanceName: "authors_blue", BlueGreen: true, Active: true},
+		{Name: "authors", InstanceName: "authors_green", BlueGreen: true, Active: true},
+		{Name: "titles", InstanceName: "titles", Active: true},
+	}
+	expected := map[string]string{"books": "books_blue"}
+	if active := activeInstances(statuses); !reflect.DeepEqual(active, expected) {
+		t.Fatalf("expected %v, got %v", expected, active)
+	}
+}
+
+func TestManageAliasesCorrectsDrift(t *testing.T) {
+	fake := &fakeSolr{}
+	solrClient := fake.start(t)
+
+	recorder := record.NewFakeRecorder(100)
+	r := &SolrCollectionSetReconciler{Recorder: recorder}
+
+	blueGreenEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+
+	// The alias was pointed at green outside the operator even though blue was active ...
+	clusterStatus := solr.ClusterStatus{
+		Collections: map[string]solr.Collection{"books_blue": {}, "books_green": {}},
+		Aliases:     map[string][]string{"books": {"books_green"}},
+	}
+	changed := r.ManageAliases(context.Background(), solrClient, &collectionSet, clusterStatus,
+		map[string]string{"books": "books_blue"})
+	if assigned := fake.assigned(); !changed || len(assigned) != 1 || assigned[0] != "books=books_blue" {
+		t.Fatalf("expected [books=books_blue] to be assigned, got %v", assigned)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "AliasDriftCorrected") {
+		t.Fatalf("expected an AliasDriftCorrected event, got [%s]", event)
+	}
+}
+
+func TestNewAliasesPointAtTheDefaultColor(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultColor string
+		expected     string
+	}{
+		{name: "default", expected: "books=books_blue"},
+		{name: "green", defaultColor: "green", expected: "books=books_green"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &fakeSolr{}
+			solrClient := fake.start(t)
+			r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+			blueGreenEnabled := true
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				BlueGreenEnabled: &blueGreenEnabled,
+				DefaultColor:     test.defaultColor,
+				Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			})
+
+			clusterStatus := solr.ClusterStatus{
+				Collections: map[string]solr.Collection{"books_blue": {}, "books_green": {}},
+			}
+			r.ManageAliases(context.Background(), solrClient, &collectionSet, clusterStatus, nil)
+			if assigned := fake.assigned(); len(assigned) != 1 || assigned[0] != test.expected {
+				t.Fatalf("expected [%s] to be assigned, got %v", test.expected, assigned)
+			}
+		})
+	}
+}
+
+func TestManageRoutedAliases(t *testing.T) {
+	fake := &fakeSolr{strict: true, responses: map[string]string{"CREATEALIAS": ""}}
+
+	maxFutureMs := int64(3600000)
+	maxCardinality := int32(10)
+	replicationFactor := int32(2)
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		RoutedAliases: []solrcollectionsv1.SolrRoutedAlias{
+			{Name: "events", Router: solr.RouterTime, Field: "timestamp", Start: "NOW/DAY", Interval: "+1DAY",
+				MaxFutureMs: &maxFutureMs, ConfigsetName: "events"},
+			{Name: "loans", Router: solr.RouterCategory, Field: "branch", MaxCardinality: &maxCardinality,
+				ConfigsetName: "loans"},
+			{Name: "holds", Router: solr.RouterCategory, Field: "branch", ConfigsetName: "holds"},
+		},
+	})
+
+	// Aliases which already exist are left to Solr ...
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	aliases := map[string][]string{"holds": {"holds__CRA__main"}}
+	changed := r.ManageRoutedAliases(context.Background(), solrClient, collectionSet, aliases)
+	if created := fake.called(); !changed ||
+		!reflect.DeepEqual(created, []string{"CREATEALIAS events", "CREATEALIAS loans"}) {
+		t.Fatalf("expected [events loans] to be created, got %v (changed [%t])", created, changed)
+	}
+	params := make(map[string]string)
+	for _, query := range fake.queries("CREATEALIAS") {
+		for key := range query {
+			params[query.Get("name")+" "+key] = query.Get(key)
+		}
+	}
+
+	expected := map[string]string{
+		"events router.name":                             "time",
+		"events router.field":                            "timestamp",
+		"events router.start":                            "NOW/DAY",
+		"events router.interval":                         "+1DAY",
+		"events router.maxFutureMs":                      "3600000",
+		"events create-collection.collection.configName": "events",
+		"events create-collection.replicationFactor":     "2",
+		"loans router.name":                              "category",
+		"loans router.field":                             "branch",
+		"loans router.maxCardinality":                    "10",
+		"loans create-collection.collection.configName":  "loans",
+	}
+	for key, value := range expected {
+		if params[key] != value {
+			t.Fatalf("expected [%s] to be [%s], got [%s]", key, value, params[key])
+		}
+	}
+	// Time only parameters aren't sent for category routers ...
+	if _, exists := params["loans router.start"]; exists {
+		t.Fatalf("expected no router.start for a category routed alias")
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+// Audit outcomes ...
+const (
+	auditOutcomeSucceeded = "succeeded"
+	auditOutcomeFailed    = "failed"
+)
+
+// AuditEvent is a record of a single call the operator made to change Solr ...
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+	// The collection set (namespace/name) being reconciled when the call was made
+	CollectionSet string `json:"collectionSet"`
+	// The Solr API (collections, configs, or update) and action (e.g. CREATE) that was called
+	Api    string `json:"api"`
+	Action string `json:"action"`
+	// The collection, alias, or config set the action applies to
+	Target string `json:"target"`
+	// The request URL with credentials and sensitive parameters redacted
+	Url string `json:"url"`
+	// Whether Solr accepted the call (succeeded or failed) along with the HTTP status and error, if any
+	Outcome    string `json:"outcome"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Auditor records the calls the operator makes to change Solr. Read-only calls aren't audited ...
+type Auditor interface {
+	Audit(ctx context.Context, event AuditEvent)
+}
+
+// JSONAuditor writes each audit event as a line of JSON to the given writer ...
+type JSONAuditor struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewJSONAuditor creates an auditor which writes to the given writer (e.g. os.Stdout) ...
+func NewJSONAuditor(writer io.Writer) *JSONAuditor {
+	return &JSONAuditor{writer: writer}
+}
+
+// Audit writes the given event ...
+func (a *JSONAuditor) Audit(ctx context.Context, event AuditEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// Encoding an AuditEvent can't fail and there's nowhere better to report a failed write ...
+	_ = json.NewEncoder(a.writer).Encode(event)
+}
+
+// auditCollectionSetKey is the context key of the collection set being reconciled ...
+type auditCollectionSetKey struct{}
+
+// withAuditCollectionSet records the collection set being reconciled in the given context so that audit events can
+// name it ...
+func withAuditCollectionSet(ctx context.Context, collectionSet string) context.Context {
+	return context.WithValue(ctx, auditCollectionSetKey{}, collectionSet)
+}
+
+// auditSolrMutation passes the given Solr mutation to the auditor, if there is one ...
+func (r *SolrCollectionSetReconciler) auditSolrMutation(ctx context.Context, mutation solr.Mutation) {
+	if r.Auditor == nil {
+		return
+	}
+	collectionSet, _ := ctx.Value(auditCollectionSetKey{}).(string)
+	event := AuditEvent{
+		Time:          time.Now().UTC(),
+		CollectionSet: collectionSet,
+		Api:           mutation.Api,
+		Action:        mutation.Action,
+		Target:        mutation.Target,
+		Url:           mutation.Url,
+		Outcome:       auditOutcomeFailed,
+		StatusCode:    mutation.StatusCode,
+	}
+	if mutation.Succeeded() {
+		event.Outcome = auditOutcomeSucceeded
+	}
+	if mutation.Err != nil {
+		event.Error = mutation.Err.Error()
+	}
+	r.Auditor.Audit(ctx, event)
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestRequeueOnErrorBacksOff(t *testing.T) {
+	collectionSet := &solrcollectionsv1.SolrCollectionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Generation: 1},
+	}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	var delays []time.Duration
+	for i := 0; i < 8; i++ {
+		result, _ := r.RequeueOnError(ctx, req, collectionSet, errors.New("solr is down"))
+		delays = append(delays, result.RequeueAfter)
+	}
+	expected := []time.Duration{5, 10, 20, 40, 80, 160, 300, 300}
+	for i := range expected {
+		if delays[i] != expected[i]*time.Second {
+			t.Fatalf("expected delays %v (seconds), got %v", expected, delays)
+		}
+	}
+	if collectionSet.Status.ConsecutiveErrors != 8 {
+		t.Fatalf("expected 8 consecutive errors, got [%d]", collectionSet.Status.ConsecutiveErrors)
+	}
+
+	// The reconcile queued by the status update waits out the backoff, unless the spec has changed ...
+	if remaining := r.errorBackoffs.remaining(req.NamespacedName.String(), 1); remaining <= 0 {
+		t.Fatalf("expected the collection set to be backing off")
+	}
+	if remaining := r.errorBackoffs.remaining(req.NamespacedName.String(), 2); remaining != 0 {
+		t.Fatalf("expected a spec change to end the backoff, got [%s]", remaining)
+	}
+
+	// A reconcile that gets all the way through resets the count ...
+	if err := r.UpdateObservedGeneration(ctx, req, collectionSet); err != nil {
+		t.Fatalf("update observed generation failed: %v", err)
+	}
+	if collectionSet.Status.ConsecutiveErrors != 0 {
+		t.Fatalf("expected the error count to be reset, got [%d]", collectionSet.Status.ConsecutiveErrors)
+	}
+}
+
+func TestReconcileNowSkipsTheBackoffOnce(t *testing.T) {
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{})
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	// Back off after an error ...
+	_, _ = r.RequeueOnError(ctx, req, collectionSet, errors.New("solr is down"))
+	if remaining := r.errorBackoffs.remaining(req.NamespacedName.String(), 1); remaining <= 0 {
+		t.Fatalf("expected the collection set to be backing off")
+	}
+
+	// Asking for a reconcile skips the backoff, and the request is acknowledged ...
+	collectionSet.Annotations = map[string]string{annotationReconcileNow: "2026-10-16T12:00:00Z"}
+	if err := r.Update(ctx, collectionSet); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	_, _ = r.Reconcile(ctx, req)
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if collectionSet.Status.LastReconcileRequest != "2026-10-16T12:00:00Z" {
+		t.Fatalf("expected the request to be acknowledged, got [%s]", collectionSet.Status.LastReconcileRequest)
+	}
+	if collectionSet.Status.ConsecutiveErrors != 2 {
+		t.Fatalf("expected the reconcile to run (and fail without Solr), got [%d] errors",
+			collectionSet.Status.ConsecutiveErrors)
+	}
+
+	// ... but only once, so the reconcile queued by the status update waits out the backoff again ...
+	result, _ := r.Reconcile(ctx, req)
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if result.RequeueAfter <= 0 || collectionSet.Status.ConsecutiveErrors != 2 {
+		t.Fatalf("expected the reconcile to back off, got [%s] with [%d] errors", result.RequeueAfter,
+			collectionSet.Status.ConsecutiveErrors)
+	}
+}
+
+func TestReconcileNowIsAcknowledgedOnceBySuccessfulReconcile(t *testing.T) {
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"LIST":          `{"collections": []}`,
+		"CLUSTERSTATUS": emptyClusterStatus,
+	}}
+	solrClient := server.start(t)
+
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		SolrClusterUrl: solrClient.Url,
+		SecretRef:      "solr-auth",
+		Mode:           solrcollectionsv1.SolrCollectionSetModeObserve,
+	})
+	collectionSet.Annotations = map[string]string{annotationReconcileNow: "2026-10-16T12:00:00Z"}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "default"}}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet, secret).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	// The reconcile gets all the way through (updating the status from the cluster) and keeps the acknowledgement ...
+	_, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if collectionSet.Status.LastReconcileRequest != "2026-10-16T12:00:00Z" {
+		t.Fatalf("expected the request to stay acknowledged, got [%s]", collectionSet.Status.LastReconcileRequest)
+	}
+	if collectionSet.Status.ObservedGeneration != 1 || len(server.requests) == 0 {
+		t.Fatalf("expected the reconcile to get all the way through, got generation [%d] after [%d] Solr calls",
+			collectionSet.Status.ObservedGeneration, len(server.requests))
+	}
+
+	// So once the collection set is backing off the same request doesn't skip the backoff again ...
+	_, _ = r.RequeueOnError(ctx, req, collectionSet, errors.New("solr is down"))
+	server.requests = nil
+	result, _ := r.Reconcile(ctx, req)
+	if result.RequeueAfter <= 0 || len(server.requests) != 0 {
+		t.Fatalf("expected the reconcile to back off, got [%s] after [%d] Solr calls", result.RequeueAfter,
+			len(server.requests))
+	}
+}
+
+func TestRequeueOnErrorReportsReconcileTimeout(t *testing.T) {
+	collectionSet := &solrcollectionsv1.SolrCollectionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Generation: 1},
+	}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder:         record.NewFakeRecorder(100),
+		ReconcileTimeout: time.Minute,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+
+	// The reconcile's context is past its deadline, but the status still gets saved ...
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	result, _ := r.RequeueOnError(ctx, req, collectionSet, context.DeadlineExceeded)
+	if result.RequeueAfter != 5*time.Second {
+		t.Fatalf("expected a backoff of [5s], got [%s]", result.RequeueAfter)
+	}
+	stable := meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetStable)
+	if stable == nil || stable.Reason != reasonSolrCollectionSetReconcileTimeout {
+		t.Fatalf("expected the stable condition to have reason [%s], got %+v",
+			reasonSolrCollectionSetReconcileTimeout, stable)
+	}
+	if collectionSet.Status.ConsecutiveErrors != 1 {
+		t.Fatalf("expected 1 consecutive error, got [%d]", collectionSet.Status.ConsecutiveErrors)
+	}
+}
+
+func TestRequeueOnErrorReportsAuthFailure(t *testing.T) {
+	collectionSet := &solrcollectionsv1.SolrCollectionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Generation: 1},
+	}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	recorder := record.NewFakeRecorder(100)
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: recorder,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+
+	// A call that runs into revoked credentials part way through the reconcile is reported as an auth failure rather
+	// than a generic error ...
+	err := fmt.Errorf("create collection failed: %w", solr.ErrAuthFailed)
+	_, _ = r.RequeueOnError(context.Background(), req, collectionSet, err)
+	stable := meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetStable)
+	if stable == nil || stable.Status != metav1.ConditionFalse || stable.Reason != reasonSolrCollectionSetAuthFailed {
+		t.Fatalf("expected the stable condition to be false with reason [%s], got %+v",
+			reasonSolrCollectionSetAuthFailed, stable)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, corev1.EventTypeWarning+" "+eventSolrCollectionSetAuthFailed) {
+			t.Fatalf("expected an [%s] warning, got [%s]", eventSolrCollectionSetAuthFailed, event)
+		}
+	default:
+		t.Fatalf("expected an [%s] warning", eventSolrCollectionSetAuthFailed)
+	}
+
+	// Other errors aren't ...
+	_, _ = r.RequeueOnError(context.Background(), req, collectionSet, errors.New("solr is down"))
+	stable = meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetStable)
+	if stable == nil || stable.Reason != reasonSolrCollectionSetReconcileError {
+		t.Fatalf("expected the stable condition to have reason [%s], got %+v",
+			reasonSolrCollectionSetReconcileError, stable)
+	}
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected no more events, got [%s]", <-recorder.Events)
+	}
+}
+
+func TestClusterStatusShrinkIsTreatedAsPartialRead(t *testing.T) {
+	collectionSet := solrcollectionsv1.SolrCollectionSet{
+		Status: solrcollectionsv1.SolrCollectionSetStatus{
+			SolrCollections: []solrcollectionsv1.SolrCollectionStatus{
+				{InstanceName: "books_blue", Exists: true},
+				{InstanceName: "books_green", Exists: true},
+				{InstanceName: "authors_blue", Exists: true},
+				{InstanceName: "authors_green"},
+			},
+		},
+	}
+
+	// Losing up to half of the collections is believed ...
+	if err := checkClusterStatusShrink(collectionSet, map[string]solr.Collection{"books_blue": {}, "books_green": {}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// ... but losing more than that isn't, at least not right away ...
+	err := checkClusterStatusShrink(collectionSet, map[string]solr.Collection{"books_blue": {}})
+	if err == nil {
+		t.Fatalf("expected the cluster status to be treated as a partial read")
+	}
+	if clusterStatusShrinkPersisted(collectionSet.Status) {
+		t.Fatalf("expected the shrink not to have persisted yet")
+	}
+
+	collectionSet.Status.ConsecutiveErrors = clusterStatusShrinkRetries
+	collectionSet.Status.Conditions = []metav1.Condition{{Type: typeSolrCollectionSetStable,
+		Status: metav1.ConditionFalse, Reason: reasonSolrCollectionSetClusterStatusShrank}}
+	if !clusterStatusShrinkPersisted(collectionSet.Status) {
+		t.Fatalf("expected the shrink to be believed after [%d] reconciles", clusterStatusShrinkRetries)
+	}
+
+	// Collections that aren't blue/green have no instance name, so they're looked up by their name ...
+	withoutBlueGreen := solrcollectionsv1.SolrCollectionSet{
+		Status: solrcollectionsv1.SolrCollectionSetStatus{
+			SolrCollections: []solrcollectionsv1.SolrCollectionStatus{
+				{Name: "books", Exists: true},
+				{Name: "authors", Exists: true},
+			},
+		},
+	}
+	if err := checkClusterStatusShrink(withoutBlueGreen, map[string]solr.Collection{"books": {}, "authors": {}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := checkClusterStatusShrink(withoutBlueGreen, map[string]solr.Collection{}); err == nil {
+		t.Fatalf("expected the cluster status to be treated as a partial read")
+	}
+
+	// The checksums collection counts as well, so a node that's only missing it and one other collection isn't
+	// believed ...
+	withoutBlueGreen.Status.ChecksumCollection = &solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums",
+		Exists: true}
+	if err := checkClusterStatusShrink(withoutBlueGreen, map[string]solr.Collection{"books": {}}); err == nil {
+		t.Fatalf("expected the missing checksums collection to count towards the shrink")
+	}
+	collections := map[string]solr.Collection{"books": {}, "_booksChecksums": {}}
+	if err := checkClusterStatusShrink(withoutBlueGreen, collections); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestClusterStatusShrinkIsCheckedBeforeInitializing(t *testing.T) {
+	// A node that's starting up, so none of the collections are there yet ...
+	server := &fakeSolr{responses: map[string]string{
+		"LIST":          `{"collections": []}`,
+		"CLUSTERSTATUS": emptyClusterStatus,
+	}}
+	solrClient := server.start(t)
+
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		SolrClusterUrl: solrClient.Url,
+		SecretRef:      "solr-auth",
+		Collections:    []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		SolrCollections: []solrcollectionsv1.SolrCollectionStatus{{Name: "books", Exists: true}},
+	}
+	collectionSet.Status.ChecksumCollection = &solrcollectionsv1.ChecksumCollectionStatus{
+		Name: checksumsCollectionNameFor(*collectionSet), Exists: true}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "default"}}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet, secret).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	// The partial read is caught before the checksums collection is recreated (or anything else is changed) ...
+	_, _ = r.Reconcile(ctx, req)
+	if !slices.Equal(server.actions, []string{"LIST", "CLUSTERSTATUS"}) {
+		t.Fatalf("expected only the auth check and the cluster status, got %v", server.actions)
+	}
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if meta.IsStatusConditionFalse(collectionSet.Status.Conditions, typeSolrCollectionSetInitialized) {
+		t.Fatalf("expected the collection set not to be marked as initializing")
+	}
+}
+
+func TestRequeueAfterChangeBacksOffWhenTheReconcileStarts(t *testing.T) {
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{})
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	// Changing Solr requeues immediately up to a point ...
+	for i := 0; i < maxConsecutiveImmediateRequeues; i++ {
+		result, _ := r.requeueAfterChange(ctx, collectionSet)
+		if result.RequeueAfter != time.Millisecond {
+			t.Fatalf("expected requeue [%d] to be immediate, got [%s]", i+1, result.RequeueAfter)
+		}
+	}
+	if remaining := r.requeueBackoffs.remaining(req.NamespacedName.String(), 1); remaining != 0 {
+		t.Fatalf("expected no backoff yet, got [%s]", remaining)
+	}
+
+	// ... after which the reconcile queued by saving the count is held off too, rather than just the requeue ...
+	result, _ := r.requeueAfterChange(ctx, collectionSet)
+	if result.RequeueAfter != time.Second*backoffRequeueSeconds {
+		t.Fatalf("expected the requeue to be delayed, got [%s]", result.RequeueAfter)
+	}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil || result.RequeueAfter <= 0 {
+		t.Fatalf("expected the reconcile to back off, got [%s] [%v]", result.RequeueAfter, err)
+	}
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if len(collectionSet.Status.Conditions) != 0 {
+		t.Fatalf("expected the reconcile not to get going, got conditions %+v", collectionSet.Status.Conditions)
+	}
+
+	// A reconcile that gets all the way through ends the backoff ...
+	if err := r.UpdateObservedGeneration(ctx, req, collectionSet); err != nil {
+		t.Fatalf("update observed generation failed: %v", err)
+	}
+	if remaining := r.requeueBackoffs.remaining(req.NamespacedName.String(), 1); remaining != 0 {
+		t.Fatalf("expected the backoff to end, got [%s]", remaining)
+	}
+}
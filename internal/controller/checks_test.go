@@ -0,0 +1,310 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestCheckMaxCollections(t *testing.T) {
+	disabled, two, three, four := int32(0), int32(2), int32(3), int32(4)
+	collections := []solrcollectionsv1.SolrCollection{
+		{Name: "books"}, {Name: "authors"}, {Name: "titles", ReplicationFactor: &disabled},
+	}
+	tests := []struct {
+		name           string
+		blueGreen      bool
+		maxCollections *int32
+		expected       string
+	}{
+		{name: "no limit", blueGreen: true},
+		{name: "within the limit", maxCollections: &two},
+		// Both colors count against the limit, but disabled collections don't ...
+		{name: "within the limit with blue/green", blueGreen: true, maxCollections: &four},
+		{name: "over the limit with blue/green", blueGreen: true, maxCollections: &three,
+			expected: "the spec calls for [4] collections which exceeds maxCollections [3]"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := solrcollectionsv1.SolrCollectionSet{
+				Spec: solrcollectionsv1.SolrCollectionSetSpec{
+					BlueGreenEnabled: &test.blueGreen,
+					MaxCollections:   test.maxCollections,
+					Collections:      collections,
+				},
+			}
+			err := checkMaxCollections(collectionSet)
+			if test.expected == "" && err != nil {
+				t.Fatalf("expected no error, got [%v]", err)
+			}
+			if test.expected != "" && (err == nil || err.Error() != test.expected) {
+				t.Fatalf("expected [%s], got [%v]", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestFindMissingConfigSets(t *testing.T) {
+	solrClient := (&fakeSolr{strict: true, responses: map[string]string{
+		"LIST": `{"configSets": ["books", "events"]}`,
+	}}).start(t)
+
+	disabled := int32(0)
+	tests := []struct {
+		name          string
+		collections   []solrcollectionsv1.SolrCollection
+		routedAliases []solrcollectionsv1.SolrRoutedAlias
+		expected      string
+	}{
+		{name: "none missing", collections: []solrcollectionsv1.SolrCollection{{Name: "books", ConfigsetName: "books"}},
+			routedAliases: []solrcollectionsv1.SolrRoutedAlias{{Name: "events", ConfigsetName: "events"}}},
+		{name: "missing", collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books"}, {Name: "authors", ConfigsetName: "authors"},
+		}, routedAliases: []solrcollectionsv1.SolrRoutedAlias{{Name: "loans", ConfigsetName: "loans"}},
+			expected: "no config set or configmap found for collection [authors] config set [authors], " +
+				"routed alias [loans] config set [loans]"},
+		// Disabled collections aren't created so they don't need a config set ...
+		{name: "disabled", collections: []solrcollectionsv1.SolrCollection{
+			{Name: "authors", ConfigsetName: "authors", ReplicationFactor: &disabled},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := solrcollectionsv1.SolrCollectionSet{
+				Spec: solrcollectionsv1.SolrCollectionSetSpec{
+					Collections:   test.collections,
+					RoutedAliases: test.routedAliases,
+				},
+			}
+			missing, err := findMissingConfigSets(context.Background(), solrClient, collectionSet)
+			if err != nil {
+				t.Fatalf("find missing config sets failed: %v", err)
+			}
+			var message string
+			if missing != nil {
+				message = missing.Error()
+			}
+			if message != test.expected {
+				t.Fatalf("expected [%s], got [%s]", test.expected, message)
+			}
+		})
+	}
+}
+
+func TestCheckNameCollisions(t *testing.T) {
+	tests := []struct {
+		name                string
+		blueGreen           bool
+		createAliasesAlways bool
+		collections         []solrcollectionsv1.SolrCollection
+		routedAliases       []solrcollectionsv1.SolrRoutedAlias
+		expected            string
+	}{
+		{name: "no collisions", blueGreen: true,
+			collections:   []solrcollectionsv1.SolrCollection{{Name: "books", Alias: "books"}},
+			routedAliases: []solrcollectionsv1.SolrRoutedAlias{{Name: "events"}}},
+		{name: "alias named after a blue/green instance", blueGreen: true,
+			collections: []solrcollectionsv1.SolrCollection{
+				{Name: "books", Alias: "books"}, {Name: "authors", Alias: "books_green"},
+			},
+			expected: "aliases collide with other names: alias [books_green] of collection [authors] has the same " +
+				"name as collection [books_green]"},
+		{name: "alias named after a collection", createAliasesAlways: true,
+			collections: []solrcollectionsv1.SolrCollection{
+				{Name: "books", Alias: "books"}, {Name: "authors", Alias: "books"},
+			},
+			expected: "aliases collide with other names: alias [books] of collection [authors] has the same name as " +
+				"collection [books]"},
+		// Without blue/green (or createAliasesAlways) no aliases are created for collections ...
+		{name: "no collection aliases",
+			collections: []solrcollectionsv1.SolrCollection{
+				{Name: "books", Alias: "books"}, {Name: "authors", Alias: "books"},
+			}},
+		{name: "routed alias named after an alias", blueGreen: true,
+			collections:   []solrcollectionsv1.SolrCollection{{Name: "books", Alias: "library"}},
+			routedAliases: []solrcollectionsv1.SolrRoutedAlias{{Name: "library"}},
+			expected: "aliases collide with other names: routed alias [library] has the same name as alias " +
+				"[library] of collection [books]"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := solrcollectionsv1.SolrCollectionSet{
+				Spec: solrcollectionsv1.SolrCollectionSetSpec{
+					BlueGreenEnabled:    &test.blueGreen,
+					CreateAliasesAlways: &test.createAliasesAlways,
+					Collections:         test.collections,
+					RoutedAliases:       test.routedAliases,
+				},
+			}
+			err := checkNameCollisions(collectionSet)
+			var message string
+			if err != nil {
+				message = err.Error()
+			}
+			if message != test.expected {
+				t.Fatalf("expected [%s], got [%s]", test.expected, message)
+			}
+		})
+	}
+}
+
+func TestCheckAliasConflicts(t *testing.T) {
+	blueGreen := true
+	createAliasesAlways := false
+	collectionSet := solrcollectionsv1.SolrCollectionSet{
+		Spec: solrcollectionsv1.SolrCollectionSetSpec{
+			BlueGreenEnabled:    &blueGreen,
+			CreateAliasesAlways: &createAliasesAlways,
+			Collections: []solrcollectionsv1.SolrCollection{
+				{Name: "books", Alias: "library"}, {Name: "authors", Alias: "authors"},
+				{Name: "books_v2", Alias: "library"},
+			},
+		},
+	}
+	err := checkAliasConflicts(collectionSet)
+	expected := "aliases can only belong to one collection: alias [library] is claimed by collections [books, books_v2]"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected [%s], got [%v]", expected, err)
+	}
+	// ... which isn't also reported as a name collision ...
+	if err := checkNameCollisions(collectionSet); err != nil {
+		t.Fatalf("expected no name collisions, got [%v]", err)
+	}
+
+	collectionSet.Spec.Collections[2].Alias = "books_v2"
+	if err := checkAliasConflicts(collectionSet); err != nil {
+		t.Fatalf("expected no conflicts once the spec is fixed, got [%v]", err)
+	}
+}
+
+func TestCheckRouterFields(t *testing.T) {
+	blueGreen := true
+	tests := []struct {
+		name             string
+		specRouterField  string
+		solrRouterFields map[string]string
+		expected         string
+	}{
+		{name: "matching", specRouterField: "author",
+			solrRouterFields: map[string]string{"books_blue": "author", "books_green": "author"}},
+		{name: "mismatched", specRouterField: "author",
+			solrRouterFields: map[string]string{"books_blue": "author", "books_green": "title"},
+			expected: "the router field of existing collections can't be changed: collection [books_green] is " +
+				"routed on [title] rather than [author]"},
+		{name: "routed on the id", specRouterField: "author",
+			solrRouterFields: map[string]string{"books_blue": ""},
+			expected: "the router field of existing collections can't be changed: collection [books_blue] is " +
+				"routed on [id] rather than [author]"},
+		{name: "empty is ignored", solrRouterFields: map[string]string{"books_blue": "author", "books_green": ""}},
+		{name: "collections that don't exist yet", specRouterField: "author"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := solrcollectionsv1.SolrCollectionSet{
+				Spec: solrcollectionsv1.SolrCollectionSetSpec{
+					BlueGreenEnabled: &blueGreen,
+					Collections: []solrcollectionsv1.SolrCollection{{Name: "books",
+						RouterField: test.specRouterField}},
+				},
+			}
+			solrCollections := make(map[string]solr.Collection)
+			for name, routerField := range test.solrRouterFields {
+				solrCollections[name] = solr.Collection{Name: name, RouterField: routerField}
+			}
+			err := checkRouterFields(collectionSet, solrCollections)
+			if test.expected == "" && err != nil {
+				t.Fatalf("expected no error, got [%v]", err)
+			}
+			if test.expected != "" && (err == nil || err.Error() != test.expected) {
+				t.Fatalf("expected [%s], got [%v]", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckLiveNodes(t *testing.T) {
+	minLiveNodes := int32(3)
+	collectionSet := solrcollectionsv1.SolrCollectionSet{
+		Spec: solrcollectionsv1.SolrCollectionSetSpec{MinLiveNodes: &minLiveNodes},
+	}
+	tests := []struct {
+		name      string
+		liveNodes []string
+		degraded  bool
+	}{
+		{name: "enough live nodes", liveNodes: []string{"solr-0", "solr-1", "solr-2"}},
+		{name: "too few live nodes", liveNodes: []string{"solr-0"}, degraded: true},
+		{name: "no live nodes", liveNodes: []string{}, degraded: true},
+		{name: "live nodes unknown", liveNodes: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkLiveNodes(collectionSet, solr.ClusterStatus{LiveNodes: test.liveNodes})
+			if (err != nil) != test.degraded {
+				t.Fatalf("expected degraded [%t], got error [%v]", test.degraded, err)
+			}
+		})
+	}
+}
+
+func TestCheckPlacements(t *testing.T) {
+	blueGreen := false
+	numShards := int32(2)
+	replicationFactor := int32(2)
+	maxShardsPerNode := int32(2)
+	twoNodes := []string{"solr-0", "solr-1"}
+	tests := []struct {
+		name          string
+		createNodeSet []string
+		existing      bool
+		liveNodes     []string
+		infeasible    bool
+	}{
+		{name: "fits on the live nodes", liveNodes: twoNodes},
+		{name: "too few live nodes", liveNodes: []string{"solr-0"}, infeasible: true},
+		{name: "too few nodes in the node set", liveNodes: twoNodes, createNodeSet: []string{"solr-0"},
+			infeasible: true},
+		{name: "node set node isn't live", liveNodes: twoNodes, createNodeSet: []string{"solr-0", "solr-2"},
+			infeasible: true},
+		{name: "already exists", liveNodes: []string{"solr-0"}, existing: true},
+		{name: "live nodes unknown", liveNodes: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := solrcollectionsv1.SolrCollectionSet{
+				Spec: solrcollectionsv1.SolrCollectionSetSpec{
+					BlueGreenEnabled: &blueGreen,
+					Collections: []solrcollectionsv1.SolrCollection{{Name: "books", NumShards: &numShards,
+						ReplicationFactor: &replicationFactor, MaxShardsPerNode: &maxShardsPerNode,
+						CreateNodeSet: test.createNodeSet}},
+				},
+			}
+			clusterStatus := solr.ClusterStatus{LiveNodes: test.liveNodes, Collections: map[string]solr.Collection{}}
+			if test.existing {
+				clusterStatus.Collections["books"] = solr.Collection{Name: "books"}
+			}
+			err := checkPlacements(collectionSet, clusterStatus)
+			if (err != nil) != test.infeasible {
+				t.Fatalf("expected infeasible [%t], got error [%v]", test.infeasible, err)
+			}
+		})
+	}
+}
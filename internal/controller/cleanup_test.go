@@ -0,0 +1,392 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestAliasesOfDeletedCollectionsAreRetargeted(t *testing.T) {
+	server := &fakeSolr{}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		CleanupEnabled:   &cleanupEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "titles"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		ManagedCollections: []string{"books", "titles", "authors"},
+	}
+
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	solrCollections := map[string]solr.Collection{"books": {}, "titles": {}, "authors": {}}
+	aliases := map[string][]string{
+		"library": {"books", "authors"},
+		"writers": {"authors"},
+		"catalog": {"books", "titles"},
+	}
+	r.ManageCollections(context.Background(), solrClient, collectionSet, solrCollections, aliases)
+	// The alias that also targets a collection which is staying is pointed at just that collection, the alias that
+	// only targets the deleted collection is removed, and both happen before the collection is deleted ...
+	expected := []string{"CREATEALIAS library", "DELETEALIAS writers", "DELETE authors"}
+	if actions := server.called("CREATEALIAS", "DELETEALIAS", "DELETE"); !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+	if assigned := server.assigned(); !reflect.DeepEqual(assigned, []string{"library=books"}) {
+		t.Fatalf("expected [library=books] to be assigned, got %v", assigned)
+	}
+}
+
+func TestInFlightDeletionsAreLeftAlone(t *testing.T) {
+	server := &fakeSolr{responses: map[string]string{"CLUSTERSTATUS": emptyClusterStatus}}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		CleanupEnabled:   &cleanupEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{ManagedCollections: []string{"books", "authors"}}
+
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	ctx := context.Background()
+
+	r.ManageCollections(ctx, solrClient, *collectionSet, map[string]solr.Collection{"books": {}, "authors": {}}, nil)
+	// Solr is still removing the collection so it still shows up, but it isn't deleted again ...
+	r.ManageCollections(ctx, solrClient, *collectionSet, map[string]solr.Collection{"books": {}, "authors": {}}, nil)
+	// ... and if it's put back in the spec it isn't recreated until the deletion has had time to finish ...
+	collectionSet.Spec.Collections = append(collectionSet.Spec.Collections, solrcollectionsv1.SolrCollection{
+		Name: "authors", ConfigsetName: "authors"})
+	r.ManageCollections(ctx, solrClient, *collectionSet, map[string]solr.Collection{"books": {}}, nil)
+	actions := server.called("CREATE", "DELETE")
+	if expected := []string{"DELETE authors"}; !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+
+	r.pendingDeletions.deletions["authors"] = time.Now().Add(-time.Second * (pendingDeletionSeconds + 1))
+	r.ManageCollections(ctx, solrClient, *collectionSet, map[string]solr.Collection{"books": {}}, nil)
+	actions = server.called("CREATE", "DELETE")
+	if expected := []string{"DELETE authors", "CREATE authors"}; !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+}
+
+func TestOnlyManagedCollectionsAreCleanedUp(t *testing.T) {
+	server := &fakeSolr{}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		CleanupEnabled:   &cleanupEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "titles"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		ManagedCollections: []string{"books", "titles", "authors"},
+	}
+
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	// [publishers] belongs to another collection set in the same cluster ...
+	solrCollections := map[string]solr.Collection{"books": {}, "titles": {}, "authors": {}, "publishers": {}}
+	r.ManageCollections(context.Background(), solrClient, collectionSet, solrCollections, nil)
+	if expected, actions := []string{"DELETE authors"}, server.called("DELETE"); !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+}
+
+func TestCleanupRespectsCollectionOwners(t *testing.T) {
+	server := &fakeSolr{}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		CleanupEnabled:   &cleanupEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	collectionSet.UID = "1234"
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{ManagedCollections: []string{"books", "authors"}}
+
+	ours := solr.CollectionOwner{Name: "books", Namespace: "default", Uid: "1234"}
+	theirs := solr.CollectionOwner{Name: "authors", Namespace: "default", Uid: "5678"}
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	// [authors] was managed but is stamped as belonging to another set, while [titles] wasn't managed (e.g. the status
+	// was lost) but is stamped as belonging to this set ...
+	solrCollections := map[string]solr.Collection{
+		"books":   {Owner: ours},
+		"authors": {Owner: theirs},
+		"titles":  {Owner: ours},
+	}
+	r.ManageCollections(context.Background(), solrClient, collectionSet, solrCollections, nil)
+	if expected, actions := []string{"DELETE titles"}, server.called("DELETE"); !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+}
+
+func TestManagedCollections(t *testing.T) {
+	solrCollections := map[string]solr.Collection{"books": {}, "titles": {}, "authors": {}, "publishers": {}}
+	tests := []struct {
+		name              string
+		previouslyManaged []string
+		specified         []string
+		expected          []string
+	}{
+		{name: "specified", specified: []string{"titles", "books"}, expected: []string{"books", "titles"}},
+		{name: "no longer specified", previouslyManaged: []string{"books", "authors"}, specified: []string{"books"},
+			expected: []string{"authors", "books"}},
+		{name: "not in the cluster", previouslyManaged: []string{"series"}, specified: []string{"books", "genres"},
+			expected: []string{"books"}},
+		{name: "none"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			managed := managedCollections(test.previouslyManaged, slices.Values(test.specified), solrCollections)
+			if !reflect.DeepEqual(managed, test.expected) {
+				t.Fatalf("expected managed collections %v, got %v", test.expected, managed)
+			}
+		})
+	}
+}
+
+func TestCleanupGracePeriodDelaysDeletion(t *testing.T) {
+	server := &fakeSolr{}
+	solrClient := server.start(t)
+
+	gracePeriod := int32(60)
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled:          &blueGreenEnabled,
+		CleanupEnabled:            &cleanupEnabled,
+		CleanupGracePeriodSeconds: &gracePeriod,
+		Collections:               []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{ManagedCollections: []string{"books", "authors"}}
+
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	solrCollections := map[string]solr.Collection{"books": {}, "authors": {}}
+	ctx := context.Background()
+
+	// The collection that was removed from the spec is marked rather than deleted ...
+	r.ManageCollections(ctx, solrClient, *collectionSet, solrCollections, nil)
+	if indexOf(server.actions, "DELETE") >= 0 {
+		t.Fatalf("expected no deletes during the grace period, got %v", server.actions)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+		t.Fatalf("get collection set failed: %v", err)
+	}
+	mark, marked := collectionSet.Status.MarkedForDeletion["authors"]
+	if !marked || len(collectionSet.Status.MarkedForDeletion) != 1 {
+		t.Fatalf("expected only [authors] to be marked for deletion, got %v", collectionSet.Status.MarkedForDeletion)
+	}
+	if wait, ok := nextDeletionDue(collectionSet.Status.MarkedForDeletion); !ok || wait > 61*time.Second {
+		t.Fatalf("expected a requeue within the grace period, got [%s]", wait)
+	}
+
+	// ... and deleted once the grace period is up ...
+	mark.DeleteAfter = metav1.NewTime(time.Now().Add(-time.Second))
+	collectionSet.Status.MarkedForDeletion["authors"] = mark
+	r.ManageCollections(ctx, solrClient, *collectionSet, solrCollections, nil)
+	if indexOf(server.actions, "DELETE") < 0 {
+		t.Fatalf("expected [authors] to be deleted after the grace period, got %v", server.actions)
+	}
+}
+
+func TestBlueGreenTransitionIsRefused(t *testing.T) {
+	tests := []struct {
+		name             string
+		blueGreenEnabled bool
+		managed          []string
+		solrCollections  []string
+		expectErr        bool
+	}{
+		{name: "blue/green", blueGreenEnabled: true, managed: []string{"books_blue", "books_green"},
+			solrCollections: []string{"books_blue", "books_green"}},
+		{name: "blue/green turned off", managed: []string{"books_blue", "books_green"},
+			solrCollections: []string{"books_blue", "books_green"}, expectErr: true},
+		{name: "blue/green turned on", blueGreenEnabled: true, managed: []string{"books"},
+			solrCollections: []string{"books"}, expectErr: true},
+		{name: "another set's collections", solrCollections: []string{"books_blue"}},
+		{name: "migrated", managed: []string{"books"}, solrCollections: []string{"books"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				BlueGreenEnabled: &test.blueGreenEnabled,
+				Collections:      []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			})
+			collectionSet.Status.ManagedCollections = test.managed
+			solrCollections := make(map[string]solr.Collection)
+			for _, name := range test.solrCollections {
+				solrCollections[name] = solr.Collection{Name: name}
+			}
+			err := checkBlueGreenTransition(collectionSet, solrCollections)
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected an error [%t], got [%v]", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanupGuard(t *testing.T) {
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	specifying := func(specified ...string) solrcollectionsv1.SolrCollectionSet {
+		var collections []solrcollectionsv1.SolrCollection
+		for _, name := range specified {
+			collections = append(collections, solrcollectionsv1.SolrCollection{Name: name})
+		}
+		collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+			BlueGreenEnabled: &blueGreenEnabled,
+			CleanupEnabled:   &cleanupEnabled,
+			Collections:      collections,
+		})
+		collectionSet.Status.ManagedCollections = []string{"books", "authors", "titles", "series", "genres", "publishers"}
+		return collectionSet
+	}
+	solrCollections := map[string]solr.Collection{
+		"books": {}, "authors": {}, "titles": {}, "series": {}, "genres": {}, "publishers": {},
+		"_checksums": {},
+	}
+	var pendingDeletions deletionTracker
+
+	// Removing a collection or two is ordinary ...
+	if err := checkCleanupGuard(specifying("books", "authors", "titles", "series", "genres"), solrCollections,
+		&pendingDeletions); err != nil {
+		t.Fatalf("expected no error removing one collection, got %v", err)
+	}
+	if err := checkCleanupGuard(specifying("books", "authors", "titles", "series"), solrCollections,
+		&pendingDeletions); err != nil {
+		t.Fatalf("expected no error removing two collections, got %v", err)
+	}
+
+	// ... but emptying the collections (or removing most of them) isn't ...
+	if err := checkCleanupGuard(specifying(), solrCollections, &pendingDeletions); err == nil {
+		t.Fatalf("expected an error removing every collection")
+	}
+	if err := checkCleanupGuard(specifying("books", "authors"), solrCollections, &pendingDeletions); err == nil {
+		t.Fatalf("expected an error removing more than half of the collections")
+	}
+
+	// ... unless the limits are lifted ...
+	collectionSet := specifying()
+	noLimit := int32(0)
+	collectionSet.Spec.CleanupMaxDeletions = &noLimit
+	collectionSet.Spec.CleanupMaxPercent = &noLimit
+	if err := checkCleanupGuard(collectionSet, solrCollections, &pendingDeletions); err != nil {
+		t.Fatalf("expected no error without limits, got %v", err)
+	}
+
+	// ... or the collections are still in their grace period (when they can be put back) ...
+	collectionSet = specifying()
+	gracePeriod := int32(60)
+	collectionSet.Spec.CleanupGracePeriodSeconds = &gracePeriod
+	if err := checkCleanupGuard(collectionSet, solrCollections, &pendingDeletions); err != nil {
+		t.Fatalf("expected no error during the grace period, got %v", err)
+	}
+	markedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	collectionSet.Status.MarkedForDeletion = map[string]solrcollectionsv1.DeletionMark{}
+	for _, name := range collectionSet.Status.ManagedCollections {
+		collectionSet.Status.MarkedForDeletion[name] = solrcollectionsv1.DeletionMark{MarkedAt: markedAt,
+			DeleteAfter: markedAt}
+	}
+	if err := checkCleanupGuard(collectionSet, solrCollections, &pendingDeletions); err == nil {
+		t.Fatalf("expected an error once the grace period is up")
+	}
+
+	// Nothing is deleted without cleanup ...
+	collectionSet = specifying()
+	cleanupDisabled := false
+	collectionSet.Spec.CleanupEnabled = &cleanupDisabled
+	if err := checkCleanupGuard(collectionSet, solrCollections, &pendingDeletions); err != nil {
+		t.Fatalf("expected no error without cleanup, got %v", err)
+	}
+}
+
+func TestCleanupGuardCountsBlueGreenCollectionsOnce(t *testing.T) {
+	blueGreenEnabled := true
+	cleanupEnabled := true
+	specifying := func(specified ...string) solrcollectionsv1.SolrCollectionSet {
+		var collections []solrcollectionsv1.SolrCollection
+		for _, name := range specified {
+			collections = append(collections, solrcollectionsv1.SolrCollection{Name: name})
+		}
+		collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+			BlueGreenEnabled: &blueGreenEnabled,
+			CleanupEnabled:   &cleanupEnabled,
+			Collections:      collections,
+		})
+		for _, name := range []string{"books", "authors", "titles", "series"} {
+			collectionSet.Status.ManagedCollections = append(collectionSet.Status.ManagedCollections,
+				name+"_blue", name+"_green")
+		}
+		return collectionSet
+	}
+	solrCollections := make(map[string]solr.Collection)
+	for _, name := range specifying().Status.ManagedCollections {
+		solrCollections[name] = solr.Collection{Name: name}
+	}
+	var pendingDeletions deletionTracker
+
+	// Removing two of the four collections deletes four instances, but only two collections (i.e. half) ...
+	if err := checkCleanupGuard(specifying("books", "authors"), solrCollections, &pendingDeletions); err != nil {
+		t.Fatalf("expected no error removing two blue/green collections, got %v", err)
+	}
+	// ... and a single collection can always be removed ...
+	collectionSet := specifying("books", "authors", "titles")
+	maxDeletions := int32(1)
+	collectionSet.Spec.CleanupMaxDeletions = &maxDeletions
+	if err := checkCleanupGuard(collectionSet, solrCollections, &pendingDeletions); err != nil {
+		t.Fatalf("expected no error removing one blue/green collection, got %v", err)
+	}
+	// ... but removing three of them is too many ...
+	err := checkCleanupGuard(specifying("books"), solrCollections, &pendingDeletions)
+	if err == nil || !strings.Contains(err.Error(), "delete [3] of the [4] managed collections") {
+		t.Fatalf("expected an error removing three blue/green collections, got %v", err)
+	}
+}
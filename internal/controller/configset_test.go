@@ -0,0 +1,826 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+	"github.com/uw-it-sis/solr-collections-operator/internal/controller/utils"
+)
+
+func TestConfigSetStatusesAndEvents(t *testing.T) {
+	authorsConfigSet := "YXV0aG9ycw=="
+	solrClient := (&fakeSolr{strict: true, responses: map[string]string{
+		"select": fmt.Sprintf(`{"response": {"docs": [{"collection": "authors", "checksum": "%s"}, `+
+			`{"collection": "titles", "checksum": "%s"}]}}`, checksum(authorsConfigSet), checksum("dGl0bGVz")),
+		"update":        "",
+		"LIST":          `{"configSets": ["authors", "titles", "publishers"]}`,
+		"CLUSTERSTATUS": `{"cluster": {"collections": {}}}`,
+		"UPLOAD":        "",
+		"DELETE":        "",
+	}}).start(t)
+
+	cleanupEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		CleanupEnabled: &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books"}, {Name: "authors", ConfigsetName: "authors"},
+		},
+	})
+	// The authors config set was uploaded by an earlier reconcile ...
+	lastUpdated := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	collectionSet.Status.ConfigSets = []solrcollectionsv1.ConfigSetStatus{
+		{Name: "authors", Checksum: checksum(authorsConfigSet), LastUpdated: &lastUpdated},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	configMapOf := func(name string, configSet string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{
+				"collectionSet": "books", "collection": name,
+			}},
+			Data: map[string]string{"configset": configSet},
+		}
+	}
+	recorder := record.NewFakeRecorder(100)
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(configMapOf("books", "Ym9va3M="), configMapOf("authors", authorsConfigSet)).Build(),
+		Recorder: recorder,
+	}
+
+	statuses, err := r.ManageConfigSets(context.Background(), solrClient, collectionSet, "_booksChecksums", false)
+	if err != nil {
+		t.Fatalf("manage config sets failed: %v", err)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	if len(statuses) != 2 || statuses[0].Name != "authors" || statuses[1].Name != "books" {
+		t.Fatalf("expected the statuses of config sets [authors books], got %v", statuses)
+	}
+	// The authors config set wasn't uploaded so its last updated time is carried forward ...
+	if statuses[0].LastUpdated == nil || !statuses[0].LastUpdated.Equal(&lastUpdated) {
+		t.Fatalf("expected config set [authors] to keep its last updated time, got %v", statuses[0].LastUpdated)
+	}
+	if statuses[1].Checksum != checksum("Ym9va3M=") || statuses[1].LastUpdated == nil {
+		t.Fatalf("expected config set [books] to have been updated, got %+v", statuses[1])
+	}
+
+	close(recorder.Events)
+	var events []string
+	for event := range recorder.Events {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	// The publishers config set wasn't uploaded by this collection set, so it's left alone ...
+	if len(events) != 2 || !strings.Contains(events[0], "ConfigSetRemoved") || !strings.Contains(events[0], "[titles]") ||
+		!strings.Contains(events[1], "ConfigSetUploaded") || !strings.Contains(events[1], "[books]") {
+		t.Fatalf("expected events for uploading [books] and removing [titles], got %v", events)
+	}
+}
+
+func TestMissingChecksumsConfigSetIsRecreated(t *testing.T) {
+	recreated := []string{"UPLOAD " + perSetChecksumsConfigSet.name, "RELOAD _booksChecksums",
+		"MODIFYCOLLECTION _booksChecksums"}
+	tests := []struct {
+		name       string
+		configSets string
+		expected   []string
+	}{
+		{name: "missing", configSets: `["_default"]`, expected: recreated},
+		{name: "present", configSets: `["_default", "` + perSetChecksumsConfigSet.name + `"]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeSolr{strict: true, responses: map[string]string{
+				"CLUSTERSTATUS": fmt.Sprintf(`{"cluster": {"collections": {
+					"_booksChecksums": {"configName": "%s", "property.%s": "%d", "shards": {}}
+				}, "aliases": {}, "live_nodes": ["node1"]}}`, perSetChecksumsConfigSet.name,
+					checksumsSchemaVersionProperty, checksumsConfigSetVersion),
+				"LIST":             `{"configSets": ` + test.configSets + `}`,
+				"UPLOAD":           "",
+				"RELOAD":           "",
+				"MODIFYCOLLECTION": "",
+			}}
+			solrClient := server.start(t)
+
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			})
+
+			r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+			clusterStatus, err := getClusterStatus(context.Background(), solrClient, collectionSet, "_booksChecksums")
+			if err != nil {
+				t.Fatalf("get cluster status failed: %v", err)
+			}
+			_, _, err = r.InitializeSolrCluster(context.Background(), solrClient, collectionSet, clusterStatus,
+				"_booksChecksums")
+			if err != nil {
+				t.Fatalf("initialize failed: %v", err)
+			}
+
+			// A missing config set is put back and the collection reloaded, keeping the checksum records ...
+			if calls := server.called("UPLOAD", "RELOAD", "MODIFYCOLLECTION"); !reflect.DeepEqual(calls, test.expected) {
+				t.Fatalf("expected calls %v, got %v", test.expected, calls)
+			}
+		})
+	}
+}
+
+func TestChecksumsConfigSetUploadIsStreamed(t *testing.T) {
+	zipped, err := utils.Zip(perSetChecksumsConfigSet.dirs, checksumCollectionSchema)
+	if err != nil {
+		t.Fatalf("zip failed: %v", err)
+	}
+	expected, err := utils.ZipContentChecksum(zipped, nil)
+	if err != nil {
+		t.Fatalf("checksum failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		cacheSize     int
+		contentLength int64
+	}{
+		// Without a cache the zip is streamed straight into the request, so its length isn't known up front ...
+		{name: "streamed", contentLength: -1},
+		{name: "cached", cacheSize: 4, contentLength: int64(len(zipped))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var contentLength int64
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				contentLength = req.ContentLength
+				body, _ = io.ReadAll(req.Body)
+			}))
+			defer server.Close()
+
+			r := &SolrCollectionSetReconciler{ConfigSetCacheSize: test.cacheSize}
+			err := r.uploadChecksumConfigSet(context.Background(), solr.SolrClient{Url: server.URL},
+				perSetChecksumsConfigSet)
+			if err != nil {
+				t.Fatalf("upload failed: %v", err)
+			}
+			if contentLength != test.contentLength {
+				t.Fatalf("expected a content length of [%d], got [%d]", test.contentLength, contentLength)
+			}
+			// Either way the same config set is uploaded ...
+			if checksum, err := utils.ZipContentChecksum(body, nil); err != nil || checksum != expected {
+				t.Fatalf("expected the checksums config set to be uploaded, got checksum [%s] [%v]", checksum, err)
+			}
+		})
+	}
+}
+
+func TestForceConfigSetResync(t *testing.T) {
+	authorsConfigSet := "YXV0aG9ycw=="
+	tests := []struct {
+		name        string
+		forceResync bool
+		expected    []string
+	}{
+		{name: "unchanged"},
+		{name: "forced", forceResync: true, expected: []string{"authors"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeSolr{strict: true, responses: map[string]string{
+				"select": fmt.Sprintf(`{"response": {"docs": [{"collection": "authors", "checksum": "%s"}]}}`,
+					checksum(authorsConfigSet)),
+				"update":        "",
+				"LIST":          `{"configSets": ["authors"]}`,
+				"CLUSTERSTATUS": `{"cluster": {"collections": {}}}`,
+				"UPLOAD":        "",
+			}}
+			solrClient := server.start(t)
+
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				Collections: []solrcollectionsv1.SolrCollection{{Name: "authors", ConfigsetName: "authors"}},
+			})
+
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("add to scheme failed: %v", err)
+			}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "authors", Namespace: "default", Labels: map[string]string{
+					"collectionSet": "books", "collection": "authors",
+				}},
+				Data: map[string]string{"configset": authorsConfigSet},
+			}
+			r := &SolrCollectionSetReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+				Recorder: record.NewFakeRecorder(100),
+			}
+
+			// The checksum of the config set matches, so it's only uploaded if a re-sync is forced ...
+			_, err := r.ManageConfigSets(context.Background(), solrClient, collectionSet, "_booksChecksums", test.forceResync)
+			if err != nil {
+				t.Fatalf("manage config sets failed: %v", err)
+			}
+			var uploads []string
+			for _, query := range server.queries("UPLOAD") {
+				uploads = append(uploads, query.Get("name"))
+			}
+			if !reflect.DeepEqual(uploads, test.expected) {
+				t.Fatalf("expected uploads %v, got %v", test.expected, uploads)
+			}
+		})
+	}
+}
+
+func TestChecksumsConfigSetIsUpgraded(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		upgraded bool
+	}{
+		{name: "never upgraded", upgraded: true},
+		{name: "older", version: strconv.Itoa(checksumsConfigSetVersion - 1), upgraded: true},
+		{name: "current", version: strconv.Itoa(checksumsConfigSetVersion)},
+		// A newer operator has upgraded the collection, so it's left alone rather than downgraded ...
+		{name: "newer", version: strconv.Itoa(checksumsConfigSetVersion + 1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version := ""
+			if test.version != "" {
+				version = fmt.Sprintf(`"property.%s": "%s", `, checksumsSchemaVersionProperty, test.version)
+			}
+			server := &fakeSolr{strict: true, responses: map[string]string{
+				"CLUSTERSTATUS": fmt.Sprintf(`{"cluster": {"collections": {
+					"_booksChecksums": {"configName": "%s", %s"shards": {}}
+				}, "aliases": {}, "live_nodes": ["node1"]}}`, perSetChecksumsConfigSet.name, version),
+				"LIST":             `{"configSets": ["` + perSetChecksumsConfigSet.name + `"]}`,
+				"UPLOAD":           "",
+				"RELOAD":           "",
+				"MODIFYCOLLECTION": "",
+			}}
+			solrClient := server.start(t)
+
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			})
+
+			r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+			checksumsCollectionName := checksumsCollectionNameFor(collectionSet)
+			clusterStatus, err := getClusterStatus(context.Background(), solrClient, collectionSet,
+				checksumsCollectionName)
+			if err != nil {
+				t.Fatalf("get cluster status failed: %v", err)
+			}
+			_, _, err = r.InitializeSolrCluster(context.Background(), solrClient, collectionSet, clusterStatus,
+				checksumsCollectionName)
+			if err != nil {
+				t.Fatalf("initialize failed: %v", err)
+			}
+
+			// An older config set is replaced (keeping the checksum records) and the new version recorded ...
+			calls := server.called("UPLOAD", "RELOAD", "MODIFYCOLLECTION")
+			if !test.upgraded {
+				if len(calls) > 0 {
+					t.Fatalf("expected the config set to be left alone, got calls %v", calls)
+				}
+				return
+			}
+			expected := []string{"UPLOAD " + perSetChecksumsConfigSet.name, "RELOAD _booksChecksums",
+				"MODIFYCOLLECTION _booksChecksums"}
+			if !reflect.DeepEqual(calls, expected) {
+				t.Fatalf("expected calls %v, got %v", expected, calls)
+			}
+			recorded := server.queries("MODIFYCOLLECTION")[0].Get("property." + checksumsSchemaVersionProperty)
+			if recorded != strconv.Itoa(checksumsConfigSetVersion) {
+				t.Fatalf("expected version [%d] to be recorded, got [%s]", checksumsConfigSetVersion, recorded)
+			}
+		})
+	}
+}
+
+func TestConfigSetCache(t *testing.T) {
+	var loads []string
+	loader := func(key string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			loads = append(loads, key)
+			return []byte(key), nil
+		}
+	}
+	var cache configSetCache
+
+	// Nothing is cached without a size ...
+	_, _ = cache.get("books", 0, loader("books"))
+	_, _ = cache.get("books", 0, loader("books"))
+	if expected := []string{"books", "books"}; !reflect.DeepEqual(loads, expected) {
+		t.Fatalf("expected loads %v, got %v", expected, loads)
+	}
+
+	// ... otherwise a cached zip isn't loaded again ...
+	loads = nil
+	_, _ = cache.get("books", 2, loader("books"))
+	_, _ = cache.get("authors", 2, loader("authors"))
+	if data, err := cache.get("books", 2, loader("books")); err != nil || string(data) != "books" {
+		t.Fatalf("expected the cached zip [books], got [%s] [%v]", data, err)
+	}
+	if expected := []string{"books", "authors"}; !reflect.DeepEqual(loads, expected) {
+		t.Fatalf("expected loads %v, got %v", expected, loads)
+	}
+
+	// ... and the least recently used zip is dropped once the cache is full ...
+	cache.entries["books"].lastUsed = time.Now().Add(-2 * time.Minute)
+	cache.entries["authors"].lastUsed = time.Now().Add(-time.Minute)
+	_, _ = cache.get("books", 2, loader("books"))
+	_, _ = cache.get("titles", 2, loader("titles"))
+	if _, exists := cache.entries["authors"]; exists || len(cache.entries) != 2 {
+		t.Fatalf("expected [authors] to be dropped, got %v", slices.Sorted(maps.Keys(cache.entries)))
+	}
+
+	// Failed loads aren't cached ...
+	loads = nil
+	failing := func() ([]byte, error) {
+		loads = append(loads, "series")
+		return nil, errors.New("bad zip")
+	}
+	if _, err := cache.get("series", 2, failing); err == nil {
+		t.Fatalf("expected the load error")
+	}
+	if _, err := cache.get("series", 2, failing); err == nil || len(loads) != 2 {
+		t.Fatalf("expected the failed load to be retried, got loads %v", loads)
+	}
+}
+
+func TestCollectionsAreRepointedWhenTheirConfigSetChanges(t *testing.T) {
+	server := &fakeSolr{responses: map[string]string{"LIST": `{"configSets": ["books_v1", "books_v2", "authors_v1"]}`}}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books_v2"}, {Name: "authors", ConfigsetName: "authors_v2"},
+		},
+	})
+
+	recorder := record.NewFakeRecorder(100)
+	r := &SolrCollectionSetReconciler{Recorder: recorder}
+	solrCollections := map[string]solr.Collection{
+		"books":   {Name: "books", ConfigName: "books_v1"},
+		"authors": {Name: "authors", ConfigName: "authors_v1"},
+	}
+	changed := r.ManageCollections(context.Background(), solrClient, collectionSet, solrCollections, nil)
+
+	// [books] is pointed at its new config set and reloaded, but [authors] is left alone since its new config set
+	// doesn't exist (pointing it at one that doesn't would break it) ...
+	var calls []string
+	for _, requestUrl := range server.requests {
+		query := requestUrl.Query()
+		if configName := query.Get("collection.configName"); configName != "" {
+			calls = append(calls, "MODIFYCOLLECTION "+query.Get("collection")+" "+configName)
+		} else if query.Get("action") == "RELOAD" {
+			calls = append(calls, "RELOAD "+query.Get("name"))
+		}
+	}
+	if expected := []string{"MODIFYCOLLECTION books books_v2", "RELOAD books"}; !changed ||
+		!reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected calls %v, got %v (changed [%t])", expected, calls, changed)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, eventSolrCollectionSetConfigSetChanged) || !strings.Contains(event, "[books_v2]") {
+			t.Fatalf("expected a [%s] event, got [%s]", eventSolrCollectionSetConfigSetChanged, event)
+		}
+	default:
+		t.Fatalf("expected a [%s] event", eventSolrCollectionSetConfigSetChanged)
+	}
+}
+
+func TestConfigSetNameOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected string
+	}{
+		{name: "unversioned", labels: map[string]string{"collection": "books"}, expected: "books"},
+		{name: "explicit version", labels: map[string]string{"collection": "books", "configSetVersion": "v3"}, expected: "books-v3"},
+		{name: "hash version", labels: map[string]string{"collection": "books", "configSetVersion": "hash"},
+			expected: "books-" + checksum("zip")[:configSetVersionHashLength]},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMap := corev1.ConfigMap{Data: map[string]string{"configset": "zip"}}
+			configMap.Labels = test.labels
+			name, err := configSetNameOf(configMap)
+			if err != nil || name != test.expected {
+				t.Fatalf("expected [%s], got [%s] [%v]", test.expected, name, err)
+			}
+		})
+	}
+	if _, err := configSetNameOf(corev1.ConfigMap{}); err == nil {
+		t.Fatalf("expected an error for a configmap without a collection label")
+	}
+}
+
+func TestCleanupKeepsConfigSetsInUse(t *testing.T) {
+	configSet := "emlw"
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"select": fmt.Sprintf(`{"response": {"docs": [{"collection": "books-v1", "checksum": "%s"}, `+
+			`{"collection": "books-v2", "checksum": "%s"}]}}`, checksum("djE="), checksum(configSet)),
+		// The publishers config set was uploaded by another collection set ...
+		"LIST": `{"configSets": ["books-v1", "books-v2", "authors", "publishers"]}`,
+		"CLUSTERSTATUS": `{"cluster": {"collections": {
+			"books_blue": {"configName": "books-v2", "shards": {}},
+			"books_green": {"configName": "books-v1", "shards": {}}
+		}}}`,
+		"DELETE": "",
+	}}
+	solrClient := server.start(t)
+
+	cleanupEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		CleanupEnabled: &cleanupEnabled,
+		Collections:    []solrcollectionsv1.SolrCollection{{Name: "books", ConfigsetName: "books-v2"}},
+	})
+	collectionSet.Status.ConfigSets = []solrcollectionsv1.ConfigSetStatus{{Name: "authors"}}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "books-v2", Namespace: "default", Labels: map[string]string{
+			"collectionSet": "books", "collection": "books", "configSetVersion": "v2",
+		}},
+		Data: map[string]string{"configset": configSet},
+	}
+	r := &SolrCollectionSetReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	statuses, err := r.ManageConfigSets(context.Background(), solrClient, collectionSet, "_booksChecksums", false)
+	if err != nil {
+		t.Fatalf("manage config sets failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "books-v2" {
+		t.Fatalf("expected the status of config set [books-v2], got %v", statuses)
+	}
+	// The old version is still used by the inactive color, and the publishers config set isn't this collection set's,
+	// so only the unused config set is removed ...
+	if deleted := server.called("DELETE"); !reflect.DeepEqual(deleted, []string{"DELETE authors"}) {
+		t.Fatalf("expected only config set [authors] to be removed, got %v", deleted)
+	}
+}
+
+func TestMalformedChecksumRecordsAreSkipped(t *testing.T) {
+	configSet := "emlw"
+	solrClient := (&fakeSolr{strict: true, responses: map[string]string{
+		// Multi-valued fields, missing fields and fields of the wrong type ...
+		"select": fmt.Sprintf(`{"response": {"docs": [
+			{"collection": ["books"], "checksum": ["%s"]},
+			{"collection": ["authors", "titles"], "checksum": "abc"},
+			{"collection": "authors"},
+			{"collection": "titles", "checksum": 42}
+		]}}`, checksum(configSet)),
+		"LIST": `{"configSets": ["books"]}`,
+	}}).start(t)
+
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books", ConfigsetName: "books"}},
+	})
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Labels: map[string]string{
+			"collectionSet": "books", "collection": "books",
+		}},
+		Data: map[string]string{"configset": configSet},
+	}
+	r := &SolrCollectionSetReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	// The single valued list is read as the checksum, so the unchanged config set isn't uploaded ...
+	statuses, err := r.ManageConfigSets(context.Background(), solrClient, collectionSet, "_booksChecksums", false)
+	if err != nil {
+		t.Fatalf("manage config sets failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "books" {
+		t.Fatalf("expected the status of config set [books], got %v", statuses)
+	}
+}
+
+func TestMisconfiguredChecksumsCollectionIsRepointed(t *testing.T) {
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"CLUSTERSTATUS": `{"cluster": {"collections": {
+			"_booksChecksums": {"configName": "_default", "shards": {}}
+		}, "aliases": {}, "live_nodes": ["node1"]}}`,
+		"LIST":             `{"configSets": ["_default"]}`,
+		"UPLOAD":           "",
+		"RELOAD":           "",
+		"MODIFYCOLLECTION": "",
+	}}
+	solrClient := server.start(t)
+
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	checksumsCollectionName := checksumsCollectionNameFor(collectionSet)
+	clusterStatus, err := getClusterStatus(context.Background(), solrClient, collectionSet, checksumsCollectionName)
+	if err != nil {
+		t.Fatalf("get cluster status failed: %v", err)
+	}
+	_, _, err = r.InitializeSolrCluster(context.Background(), solrClient, collectionSet, clusterStatus,
+		checksumsCollectionName)
+	if err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+
+	// The collection (and so the checksum records) is kept and pointed at the checksums config set instead ...
+	expected := []string{"UPLOAD " + perSetChecksumsConfigSet.name, "MODIFYCOLLECTION _booksChecksums",
+		"RELOAD _booksChecksums", "MODIFYCOLLECTION _booksChecksums"}
+	if calls := server.called("UPLOAD", "RELOAD", "MODIFYCOLLECTION"); !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	configName := server.queries("MODIFYCOLLECTION")[0].Get("collection.configName")
+	if configName != perSetChecksumsConfigSet.name {
+		t.Fatalf("expected the collection to be pointed at [%s], got [%s]", perSetChecksumsConfigSet.name, configName)
+	}
+}
+
+func TestBadConfigSetEncodingFailsBeforeUpload(t *testing.T) {
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"select": `{"response": {"docs": []}}`,
+		"LIST":   `{"configSets": []}`,
+		"UPLOAD": "",
+	}}
+	solrClient := server.start(t)
+
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books", ConfigsetName: "books"}},
+	})
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Labels: map[string]string{
+			"collectionSet": "books", "collection": "books",
+		}},
+		// Valid base64 up to the point where it isn't, so a streamed decode would fail part way through ...
+		Data: map[string]string{"configset": "emlwemlwemlw!!!!"},
+	}
+	r := &SolrCollectionSetReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	_, err := r.ManageConfigSets(context.Background(), solrClient, collectionSet, "_booksChecksums", false)
+	if err == nil || !strings.Contains(err.Error(), "could not decode configset books") {
+		t.Fatalf("expected a decode error, got [%v]", err)
+	}
+	if uploads := server.called("UPLOAD"); len(uploads) != 0 {
+		t.Fatalf("expected nothing to be uploaded, got %v", uploads)
+	}
+}
+
+// testZip zips the given name/contents pairs ...
+func testZip(t *testing.T, files ...string) []byte {
+	zipped := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(zipped)
+	for i := 0; i < len(files); i += 2 {
+		w, err := zipWriter.Create(files[i])
+		if err != nil {
+			t.Fatalf("create zip entry failed: %v", err)
+		}
+		_, _ = w.Write([]byte(files[i+1]))
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("close zip failed: %v", err)
+	}
+	return zipped.Bytes()
+}
+
+func TestConfigSetDrifted(t *testing.T) {
+	configMapZip := testZip(t, "schema.xml", "<schema/>", "solrconfig.xml", "<config/>")
+	tests := []struct {
+		name       string
+		downloaded []byte
+		drifted    bool
+	}{
+		{name: "unchanged", downloaded: testZip(t, "solrconfig.xml", "<config/>", "schema.xml", "<schema/>")},
+		{name: "changed", downloaded: testZip(t, "schema.xml", "<schema/>", "solrconfig.xml", "<config></config>"),
+			drifted: true},
+		{name: "added file", downloaded: testZip(t, "schema.xml", "<schema/>", "solrconfig.xml", "<config/>",
+			"stopwords.txt", "a"), drifted: true},
+		// Solr switched the config set to a managed schema ...
+		{name: "managed schema", downloaded: testZip(t, "schema.xml.bak", "<schema/>", "solrconfig.xml", "<config/>",
+			"managed-schema.xml", "<schema version=\"1.6\"/>")},
+		{name: "managed schema changed", downloaded: testZip(t, "schema.xml.bak", "<schema></schema>", "solrconfig.xml",
+			"<config/>", "managed-schema.xml", "<schema version=\"1.6\"/>"), drifted: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeSolr{strict: true, responses: map[string]string{"DOWNLOAD": string(test.downloaded)}}
+			drifted, err := configSetDrifted(context.Background(), server.start(t), "books", configMapZip)
+			if err != nil {
+				t.Fatalf("drift check failed: %v", err)
+			}
+			if calls := server.called(); !reflect.DeepEqual(calls, []string{"DOWNLOAD books"}) {
+				t.Fatalf("expected config set [books] to be downloaded, got %v", calls)
+			}
+			if drifted != test.drifted {
+				t.Fatalf("expected drifted [%t], got [%t]", test.drifted, drifted)
+			}
+		})
+	}
+}
+
+func TestConfigSetsAreOnlyVerifiedEveryInterval(t *testing.T) {
+	configSet := base64.StdEncoding.EncodeToString(testZip(t, "solrconfig.xml", "<config/>"))
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"select": fmt.Sprintf(`{"response": {"docs": [{"collection": "books", "checksum": "%s"}]}}`,
+			checksum(configSet)),
+		"LIST":     `{"configSets": ["books"]}`,
+		"DOWNLOAD": string(testZip(t, "solrconfig.xml", "<config/>")),
+	}}
+	solrClient := server.start(t)
+
+	verifyConfigSets := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		VerifyConfigSets: &verifyConfigSets,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "books", ConfigsetName: "books"}},
+	})
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Labels: map[string]string{
+			"collectionSet": "books", "collection": "books",
+		}},
+		Data: map[string]string{"configset": configSet},
+	}
+	r := &SolrCollectionSetReconciler{
+		Client:                  fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		Recorder:                record.NewFakeRecorder(100),
+		ConfigSetVerifyInterval: time.Hour,
+	}
+
+	manage := func() {
+		statuses, err := r.ManageConfigSets(context.Background(), solrClient, collectionSet, "_booksChecksums", false)
+		if err != nil {
+			t.Fatalf("manage config sets failed: %v", err)
+		}
+		collectionSet.Status.ConfigSets = statuses
+	}
+	downloads := func() int {
+		return len(server.called("DOWNLOAD"))
+	}
+
+	manage()
+	if downloads() != 1 || collectionSet.Status.ConfigSets[0].LastVerified == nil {
+		t.Fatalf("expected the config set to be verified, got %d downloads", downloads())
+	}
+	// Verified recently, so it isn't downloaded again ...
+	manage()
+	if downloads() != 1 {
+		t.Fatalf("expected the config set not to be verified again, got %d downloads", downloads())
+	}
+	// Once the interval is up it's verified again ...
+	lastVerified := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	collectionSet.Status.ConfigSets[0].LastVerified = &lastVerified
+	manage()
+	if downloads() != 2 {
+		t.Fatalf("expected the config set to be verified again, got %d downloads", downloads())
+	}
+}
+
+func TestReloadCollections(t *testing.T) {
+	stamped := func(name string, configSetChecksum string) solr.Collection {
+		return solr.Collection{Name: name, ConfigName: "books",
+			Properties: map[string]string{configSetChecksumProperty: configSetChecksum}}
+	}
+	tests := []struct {
+		name           string
+		blueGreen      bool
+		aliasAllColors bool
+		collections    []solr.Collection
+		aliases        map[string][]string
+		expected       []string
+	}{
+		{name: "changed", collections: []solr.Collection{stamped("books", "old")}, expected: []string{"books"}},
+		{name: "unchanged", collections: []solr.Collection{stamped("books", "new")}},
+		{name: "not stamped yet", collections: []solr.Collection{{Name: "books", ConfigName: "books"}}},
+		{name: "blue/green stages the change on the inactive color", blueGreen: true,
+			collections: []solr.Collection{stamped("books_blue", "old"), stamped("books_green", "old")},
+			aliases:     map[string][]string{"books": {"books_blue"}}, expected: []string{"books_green"}},
+		{name: "alias across all colors", blueGreen: true, aliasAllColors: true,
+			collections: []solr.Collection{stamped("books_blue", "old"), stamped("books_green", "old")},
+			aliases:     map[string][]string{"books": {"books_blue", "books_green"}},
+			expected:    []string{"books_blue", "books_green"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeSolr{strict: true, responses: map[string]string{"RELOAD": "", "MODIFYCOLLECTION": ""}}
+			solrClient := server.start(t)
+
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				BlueGreenEnabled: &test.blueGreen,
+				Collections: []solrcollectionsv1.SolrCollection{{Name: "books", ConfigsetName: "books",
+					AliasAllColors: test.aliasAllColors}},
+			})
+			clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{}, Aliases: test.aliases}
+			for _, collection := range test.collections {
+				clusterStatus.Collections[collection.Name] = collection
+			}
+			r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+			reloaded := r.ReloadCollections(context.Background(), solrClient, collectionSet, clusterStatus,
+				[]solrcollectionsv1.ConfigSetStatus{{Name: "books", Checksum: "new"}})
+			var reloads []string
+			for _, query := range server.queries("RELOAD") {
+				reloads = append(reloads, query.Get("name"))
+			}
+			sort.Strings(reloads)
+			if !reflect.DeepEqual(reloaded, test.expected) || !reflect.DeepEqual(reloads, test.expected) {
+				t.Fatalf("expected %v to be reloaded, got %v (requests %v)", test.expected, reloaded, reloads)
+			}
+		})
+	}
+}
+
+func TestSharedChecksumsConfigSetReusesSolrConfig(t *testing.T) {
+	zipped, err := utils.Zip(sharedChecksumsConfigSet.dirs, checksumCollectionSchema)
+	if err != nil {
+		t.Fatalf("zip failed: %v", err)
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(zipped), int64(len(zipped)))
+	if err != nil {
+		t.Fatalf("read zip failed: %v", err)
+	}
+	var contents = make(map[string]string)
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("open [%s] failed: %v", file.Name, err)
+		}
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		contents[file.Name] = string(data)
+	}
+	solrConfig, _ := checksumCollectionSchema.ReadFile("checksum_collection_configset/solrconfig.xml")
+	if len(contents) != 2 || contents["solrconfig.xml"] != string(solrConfig) {
+		t.Fatalf("expected the per-set solrconfig.xml to be reused, got files %v", slices.Sorted(maps.Keys(contents)))
+	}
+	if !strings.Contains(contents["schema.xml"], "<uniqueKey>id</uniqueKey>") {
+		t.Fatalf("expected the shared schema.xml")
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+)
+
+func TestSolrClientIsRecreatedWhenTheSecretChanges(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "solr"},
+		Data:       map[string][]byte{"username": []byte("solr"), "password": []byte("first")},
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client:                     fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		DefaultSolrSecretNamespace: "solr",
+	}
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		SecretRef:      "solr-auth",
+		SolrClusterUrl: "http://solr:8983/solr",
+	})
+	ctx := context.Background()
+
+	passwordOf := func() string {
+		solrClient, err := r.solrClientFor(ctx, collectionSet)
+		if err != nil {
+			t.Fatalf("get solr client failed: %v", err)
+		}
+		return solrClient.Password
+	}
+	if password := passwordOf(); password != "first" {
+		t.Fatalf("expected password [first], got [%s]", password)
+	}
+
+	// The cached client is reused while the secret is unchanged ...
+	r.solrClients.mu.Lock()
+	for key, entry := range r.solrClients.clients {
+		entry.client.Password = "cached"
+		r.solrClients.clients[key] = entry
+	}
+	r.solrClients.mu.Unlock()
+	if password := passwordOf(); password != "cached" {
+		t.Fatalf("expected the cached client to be reused, got password [%s]", password)
+	}
+
+	// ... and recreated once the credentials are rotated ...
+	secret.Data["password"] = []byte("second")
+	if err := r.Update(ctx, secret); err != nil {
+		t.Fatalf("update secret failed: %v", err)
+	}
+	if password := passwordOf(); password != "second" {
+		t.Fatalf("expected the rotated password [second], got [%s]", password)
+	}
+
+	// A missing secret is reported with the underlying error ...
+	if err := r.Delete(ctx, secret); err != nil {
+		t.Fatalf("delete secret failed: %v", err)
+	}
+	if _, err := r.solrClientFor(ctx, collectionSet); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestOperatorWideSolrDefaults(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	secretOf := func(name string, password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "solr"},
+			Data:       map[string][]byte{"username": []byte("solr"), "password": []byte(password)},
+		}
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(secretOf("shared-auth", "shared"), secretOf("books-auth", "books")).Build(),
+		DefaultSolrClusterUrl:      "http://shared-solr:8983/solr",
+		DefaultSolrSecretName:      "shared-auth",
+		DefaultSolrSecretNamespace: "solr",
+	}
+
+	tests := []struct {
+		name             string
+		spec             solrcollectionsv1.SolrCollectionSetSpec
+		expectedUrl      string
+		expectedPassword string
+	}{
+		{name: "operator defaults", expectedUrl: "http://shared-solr:8983/solr", expectedPassword: "shared"},
+		{name: "collection set overrides",
+			spec: solrcollectionsv1.SolrCollectionSetSpec{
+				SecretRef: "books-auth", SolrClusterUrl: "http://books-solr:8983/solr",
+			},
+			expectedUrl: "http://books-solr:8983/solr", expectedPassword: "books"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := *newCollectionSet(test.spec)
+
+			solrClient, err := r.solrClientFor(context.Background(), collectionSet)
+			if err != nil {
+				t.Fatalf("get solr client failed: %v", err)
+			}
+			if solrClient.Url != test.expectedUrl || solrClient.Password != test.expectedPassword {
+				t.Fatalf("expected url [%s] and password [%s], got [%s] and [%s]", test.expectedUrl,
+					test.expectedPassword, solrClient.Url, solrClient.Password)
+			}
+		})
+	}
+}
+
+func TestDefaultSolrClusterUrl(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "solr"},
+		Data:       map[string][]byte{"username": []byte("solr"), "password": []byte("secret")},
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	// Without an operator-wide default the cluster is named after the collection set ...
+	r := &SolrCollectionSetReconciler{
+		Client:                     fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		DefaultSolrSecretNamespace: "solr",
+	}
+
+	port := int32(8443)
+	tests := []struct {
+		name        string
+		spec        solrcollectionsv1.SolrCollectionSetSpec
+		expectedUrl string
+	}{
+		{name: "defaults", spec: solrcollectionsv1.SolrCollectionSetSpec{SecretRef: "solr-auth"},
+			expectedUrl: "http://books-solrcloud:8983/solr"},
+		{name: "scheme and port",
+			spec:        solrcollectionsv1.SolrCollectionSetSpec{SecretRef: "solr-auth", SolrScheme: "https", SolrPort: &port},
+			expectedUrl: "https://books-solrcloud:8443/solr"},
+		{name: "cluster url", spec: solrcollectionsv1.SolrCollectionSetSpec{SecretRef: "solr-auth",
+			SolrClusterUrl: "http://solr:8983/solr", SolrScheme: "https", SolrPort: &port},
+			expectedUrl: "http://solr:8983/solr"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := *newCollectionSet(test.spec)
+
+			solrClient, err := r.solrClientFor(context.Background(), collectionSet)
+			if err != nil {
+				t.Fatalf("get solr client failed: %v", err)
+			}
+			if solrClient.Url != test.expectedUrl {
+				t.Fatalf("expected url [%s], got [%s]", test.expectedUrl, solrClient.Url)
+			}
+		})
+	}
+}
+
+func TestSolrSecretHeaders(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "solr"},
+		Data: map[string][]byte{"username": []byte("solr"), "password": []byte("secret"),
+			"header.X-Api-Key": []byte("abc123"), "header.X-Tenant": []byte("library"), "header.": []byte("unnamed")},
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client:                     fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		DefaultSolrSecretNamespace: "solr",
+	}
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		SecretRef:      "solr-auth",
+		SolrClusterUrl: "http://solr:8983/solr",
+	})
+
+	solrClient, err := r.solrClientFor(context.Background(), collectionSet)
+	if err != nil {
+		t.Fatalf("get solr client failed: %v", err)
+	}
+	// Only the prefixed keys (with a header name) become headers ...
+	expected := map[string]string{"X-Api-Key": "abc123", "X-Tenant": "library"}
+	if !reflect.DeepEqual(solrClient.Headers, expected) {
+		t.Fatalf("expected headers %v, got %v", expected, solrClient.Headers)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+// newCollectionSet returns collection set books (in namespace default) with the given spec, the rest of which is
+// filled in as the reconcile would ...
+func newCollectionSet(spec solrcollectionsv1.SolrCollectionSetSpec) *solrcollectionsv1.SolrCollectionSet {
+	collectionSet := &solrcollectionsv1.SolrCollectionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default", Generation: 1},
+		Spec:       spec,
+	}
+	collectionSet.WithDefaults(logr.Discard())
+	return collectionSet
+}
+
+// emptyClusterStatus is the cluster status of a Solr without any collections (or aliases or live nodes) ...
+const emptyClusterStatus = `{"cluster": {"collections": {}, "aliases": {}, "live_nodes": []}}`
+
+// fakeSolr is a minimal stand-in for the Solr API. Unless it's failing, a request gets the canned response for its
+// collections (or config sets) API action, or for its path, if there is one. Otherwise, if the fake has a collection, it
+// acts as a Solr with that single single-shard collection. Any other request is acknowledged, unless the fake is
+// strict ...
+type fakeSolr struct {
+	mu                sync.Mutex
+	collection        string
+	replicationFactor int
+	// replicas are the names of the replicas of the shard, the first of which is the leader
+	replicas    []string
+	nextReplica int
+	// deadReplicas are the replicas whose nodes have died. They're reported as down and their nodes aren't live.
+	deadReplicas []string
+	// responses are the canned response bodies, keyed by action (e.g. "LIST") or by path, either in full (e.g.
+	// "/books/config/overlay") or by its last element (e.g. "select")
+	responses map[string]string
+	// strict fails the test on requests without a canned response
+	strict bool
+	// failing fails every request with an internal server error
+	failing bool
+	t       *testing.T
+	// actions records the collections API actions in the order they were called
+	actions []string
+	// requests records the requests in the order they were made, along with their bodies
+	requests []*url.URL
+	bodies   [][]byte
+}
+
+// start serves the fake until the test is done and returns a client for it ...
+func (f *fakeSolr) start(t *testing.T) solr.SolrClient {
+	f.t = t
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return solr.SolrClient{Url: server.URL}
+}
+
+func (f *fakeSolr) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := req.URL.Query()
+	action := query.Get("action")
+	f.actions = append(f.actions, action)
+	f.requests = append(f.requests, req.URL)
+	body, _ := io.ReadAll(req.Body)
+	f.bodies = append(f.bodies, body)
+
+	if f.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, key := range []string{action, req.URL.Path, path.Base(req.URL.Path)} {
+		if response, exists := f.responses[key]; exists && key != "" {
+			_, _ = w.Write([]byte(response))
+			return
+		}
+	}
+	if f.collection == "" {
+		if f.strict {
+			f.t.Errorf("unexpected request [%s]", req.URL)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"responseHeader": {"status": 0}}`))
+		return
+	}
+
+	switch action {
+	case "CLUSTERSTATUS":
+		replicas := make(map[string]interface{})
+		liveNodes := []string{}
+		for i, name := range f.replicas {
+			node, state := fmt.Sprintf("solr-%d:8983_solr", i), "active"
+			if slices.Contains(f.deadReplicas, name) {
+				state = "down"
+			} else {
+				liveNodes = append(liveNodes, node)
+			}
+			replicas[name] = map[string]interface{}{
+				"core":      fmt.Sprintf("%s_shard1_%s", f.collection, name),
+				"node_name": node,
+				"type":      solr.ReplicaTypeNRT,
+				"state":     state,
+				"leader":    strconv.FormatBool(i == 0),
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"live_nodes": liveNodes,
+				"aliases":    map[string]interface{}{},
+				"collections": map[string]interface{}{
+					f.collection: map[string]interface{}{
+						"configName":        f.collection,
+						"replicationFactor": f.replicationFactor,
+						"nrtReplicas":       f.replicationFactor,
+						"shards": map[string]interface{}{
+							"shard1": map[string]interface{}{"state": "active", "replicas": replicas},
+						},
+					},
+				},
+			},
+		})
+	case "MODIFYCOLLECTION":
+		f.replicationFactor, _ = strconv.Atoi(query.Get("replicationFactor"))
+	case "ADDREPLICA":
+		n, _ := strconv.Atoi(query.Get("nrtReplicas"))
+		for i := 0; i < n; i++ {
+			f.nextReplica++
+			f.replicas = append(f.replicas, fmt.Sprintf("core_node%d", f.nextReplica))
+		}
+	case "DELETEREPLICA":
+		f.replicas = slices.DeleteFunc(f.replicas, func(name string) bool {
+			return name == query.Get("replica")
+		})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// called lists the requests for the given actions (or every request if no actions are given) as "<action> <name>",
+// where name is the collection, alias or config set the request was for ...
+func (f *fakeSolr) called(actions ...string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []string
+	for _, requestUrl := range f.requests {
+		query := requestUrl.Query()
+		action := query.Get("action")
+		if len(actions) > 0 && !slices.Contains(actions, action) {
+			continue
+		}
+		name := query.Get("name")
+		if name == "" {
+			name = query.Get("collection")
+		}
+		calls = append(calls, action+" "+name)
+	}
+	return calls
+}
+
+// assigned lists the aliases which were created (or repointed) as "<alias>=<collections>" ...
+func (f *fakeSolr) assigned() []string {
+	var assigned []string
+	for _, query := range f.queries("CREATEALIAS") {
+		assigned = append(assigned, query.Get("name")+"="+query.Get("collections"))
+	}
+	return assigned
+}
+
+// queries returns the parameters of the requests for the given action ...
+func (f *fakeSolr) queries(action string) []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var queries []url.Values
+	for _, requestUrl := range f.requests {
+		if query := requestUrl.Query(); query.Get("action") == action {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
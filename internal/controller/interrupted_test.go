@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestInterruptedOperationIsRecordedUntilFinished(t *testing.T) {
+	expiredCtx, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{name: "still running", ctx: context.Background()},
+		{name: "shutdown", ctx: canceledCtx,
+			expected: "manage collections was interrupted by the operator shutting down: solr call failed"},
+		{name: "timeout", ctx: expiredCtx,
+			expected: "manage collections was interrupted by the reconcile timing out after [1m0s]: solr call failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{})
+
+			scheme := runtime.NewScheme()
+			if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("add to scheme failed: %v", err)
+			}
+			r := &SolrCollectionSetReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+					WithStatusSubresource(collectionSet).Build(),
+				Recorder:         record.NewFakeRecorder(100),
+				ReconcileTimeout: time.Minute,
+			}
+			ctx := context.Background()
+
+			// Only an operation that was cut short by the context is recorded, along with why ...
+			r.RecordInterruptedOperation(test.ctx, collectionSet, "manage collections", errors.New("solr call failed"))
+			if err := r.Get(ctx, client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+				t.Fatalf("get collection set failed: %v", err)
+			}
+			if collectionSet.Status.InterruptedOperation != test.expected {
+				t.Fatalf("expected interrupted operation [%s], got [%s]", test.expected,
+					collectionSet.Status.InterruptedOperation)
+			}
+
+			// ... it survives the status being refreshed ...
+			var status solrcollectionsv1.SolrCollectionSetStatus
+			populateCollectionSetStatus(&status, collectionSet, solr.ClusterStatus{}, logr.Discard())
+			if status.InterruptedOperation != test.expected {
+				t.Fatalf("expected the interrupted operation to be carried forward, got [%s]",
+					status.InterruptedOperation)
+			}
+
+			// ... and it's cleared once a reconcile gets all the way through ...
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+			if err := r.UpdateObservedGeneration(ctx, req, collectionSet); err != nil {
+				t.Fatalf("update observed generation failed: %v", err)
+			}
+			if collectionSet.Status.InterruptedOperation != "" {
+				t.Fatalf("expected the interrupted operation to be cleared, got [%s]",
+					collectionSet.Status.InterruptedOperation)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Phases of a reconcile which are timed ...
+const (
+	phaseInitializeSolrCluster = "InitializeSolrCluster"
+	phaseUpdateStatus          = "UpdateStatus"
+	phaseManageConfigSets      = "ManageConfigSets"
+	phaseManageCollections     = "ManageCollections"
+	phaseAdjustReplicas        = "AdjustReplicas"
+)
+
+// reconcilePhaseDuration records how long each phase of a reconcile takes. It's served with the controller-runtime
+// metrics ...
+var reconcilePhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "solrcollectionset_reconcile_phase_duration_seconds",
+	Help:    "How long each phase of a SolrCollectionSet reconcile takes.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"phase"})
+
+// solrRequestDuration records the round trip time of the requests made to Solr by operation (e.g. CLUSTERSTATUS,
+// CREATE, ADDREPLICA, select) and status code, so that a slow Solr can be told apart from a slow operator. It's served
+// with the controller-runtime metrics ...
+var solrRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "solrcollectionset_solr_request_duration_seconds",
+	Help:    "The round trip time of requests to the Solr API by operation.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"operation", "code"})
+
+func init() {
+	metrics.Registry.MustRegister(reconcilePhaseDuration)
+	metrics.Registry.MustRegister(solrRequestDuration)
+}
+
+// solrRequestTimer is a http.RoundTripper which records the round trip time of Solr requests in the metrics ...
+type solrRequestTimer struct{}
+
+func (t solrRequestTimer) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	solrRequestDuration.WithLabelValues(solrOperation(req), code).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// solrOperation names the Solr operation of the given request. That's the action of admin API calls (e.g.
+// CLUSTERSTATUS), otherwise it's the handler (e.g. select or update) ...
+func solrOperation(req *http.Request) string {
+	if action := req.URL.Query().Get("action"); action != "" {
+		return action
+	}
+	return path.Base(req.URL.Path)
+}
+
+// startPhaseTimer starts timing the given reconcile phase of the given collection set. Call the returned function once
+// the phase is done to log the elapsed time and record it in the metrics ...
+func startPhaseTimer(ctx context.Context, phase string, collectionSetName string) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		reconcilePhaseDuration.WithLabelValues(phase).Observe(elapsed.Seconds())
+		log.FromContext(ctx).V(1).Info("reconcile phase done", "phase", phase, "collectionSet", collectionSetName,
+			"elapsed", elapsed.String())
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestSolrRequestsAreTimedByOperation(t *testing.T) {
+	solrRequestDuration.Reset()
+	solrClient := (&fakeSolr{}).start(t)
+	solrClient.Transport = solrRequestTimer{}
+	ctx := context.Background()
+	if err := solrClient.ReloadCollection(ctx, "books"); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if err := solrClient.OptimizeCollection(ctx, "books"); err != nil {
+		t.Fatalf("optimize failed: %v", err)
+	}
+
+	for _, operation := range []string{"RELOAD", "update"} {
+		var metric dto.Metric
+		if err := solrRequestDuration.WithLabelValues(operation, "200").(prometheus.Histogram).Write(&metric); err != nil {
+			t.Fatalf("read metric failed: %v", err)
+		}
+		if count := metric.GetHistogram().GetSampleCount(); count != 1 {
+			t.Fatalf("expected the [%s] request to be timed once, got [%d]", operation, count)
+		}
+	}
+}
+
+func TestReconcilePhasesAreTimed(t *testing.T) {
+	reconcilePhaseDuration.Reset()
+	ctx := context.Background()
+
+	// A phase is only recorded once it's done ...
+	stopTimer := startPhaseTimer(ctx, phaseManageConfigSets, "books")
+	var metric dto.Metric
+	histogram := reconcilePhaseDuration.WithLabelValues(phaseManageConfigSets).(prometheus.Histogram)
+	if err := histogram.Write(&metric); err != nil {
+		t.Fatalf("read metric failed: %v", err)
+	}
+	if count := metric.GetHistogram().GetSampleCount(); count != 0 {
+		t.Fatalf("expected the [%s] phase not to be recorded yet, got [%d]", phaseManageConfigSets, count)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	stopTimer()
+	if err := histogram.Write(&metric); err != nil {
+		t.Fatalf("read metric failed: %v", err)
+	}
+	if count := metric.GetHistogram().GetSampleCount(); count != 1 {
+		t.Fatalf("expected the [%s] phase to be timed once, got [%d]", phaseManageConfigSets, count)
+	}
+	if sum := metric.GetHistogram().GetSampleSum(); sum < 0.01 {
+		t.Fatalf("expected the [%s] phase to take at least [10ms], got [%fs]", phaseManageConfigSets, sum)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+// waitForOptimizes checks on the optimizes of the given collection set until they've all finished, returning the errors
+// the checks returned ...
+func waitForOptimizes(t *testing.T, r *SolrCollectionSetReconciler,
+	collectionSet *solrcollectionsv1.SolrCollectionSet) error {
+
+	t.Helper()
+	var errs []error
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := r.Get(context.Background(), client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+			t.Fatalf("get collection set failed: %v", err)
+		}
+		optimizing, err := r.CheckOptimizes(context.Background(), collectionSet)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if !optimizing {
+			return errors.Join(errs...)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("gave up waiting on the optimizes to finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestOptimizeAnnotationOptimizesCollections(t *testing.T) {
+	server := &fakeSolr{strict: true, responses: map[string]string{"update": ""}}
+	solrClient := server.start(t)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "all collections", value: "true", expected: "authors_blue,authors_green,books_blue,books_green"},
+		{name: "named collections", value: "books_green, titles_blue", expected: "books_green"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server.requests = nil
+			collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "authors"}},
+			})
+			collectionSet.Annotations = map[string]string{annotationOptimize: test.value}
+
+			scheme := runtime.NewScheme()
+			if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("add to scheme failed: %v", err)
+			}
+			r := &SolrCollectionSetReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+					WithStatusSubresource(collectionSet).Build(),
+				Recorder: record.NewFakeRecorder(100),
+			}
+			solrCollections := map[string]solr.Collection{
+				"books_blue": {}, "books_green": {}, "authors_blue": {}, "authors_green": {},
+			}
+			ctx := context.Background()
+
+			changed, err := r.OptimizeCollections(ctx, solrClient, collectionSet, solrCollections)
+			if err != nil || !changed {
+				t.Fatalf("expected the annotation to be processed, got changed [%t] error [%v]", changed, err)
+			}
+
+			// The optimize is one-shot ...
+			if err := r.Get(ctx, client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+				t.Fatalf("get collection set failed: %v", err)
+			}
+			if _, exists := collectionSet.Annotations[annotationOptimize]; exists {
+				t.Fatalf("expected the optimize annotation to be removed")
+			}
+			// ... and runs in the background, tracked in the status ...
+			if len(collectionSet.Status.PendingOperations) != 1 ||
+				collectionSet.Status.PendingOperations[0].Operation != operationOptimize {
+				t.Fatalf("expected the optimize to be pending, got %v", collectionSet.Status.PendingOperations)
+			}
+
+			if err := waitForOptimizes(t, r, collectionSet); err != nil {
+				t.Fatalf("expected the optimize to succeed, got [%v]", err)
+			}
+			var optimized []string
+			for _, requestUrl := range server.requests {
+				if requestUrl.Query().Get("optimize") != "true" {
+					t.Fatalf("unexpected request [%s]", requestUrl)
+				}
+				optimized = append(optimized, strings.TrimSuffix(strings.TrimPrefix(requestUrl.Path, "/"), "/update"))
+			}
+			if strings.Join(optimized, ",") != test.expected {
+				t.Fatalf("expected [%s] to be optimized, got %v", test.expected, optimized)
+			}
+			if len(collectionSet.Status.PendingOperations) != 0 {
+				t.Fatalf("expected no pending operations, got %v", collectionSet.Status.PendingOperations)
+			}
+		})
+	}
+}
+
+func TestCheckOptimizesReportsFailedOptimizes(t *testing.T) {
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		// An optimize started before the operator restarted ...
+		PendingOperations: []solrcollectionsv1.AsyncOperation{
+			{Operation: operationOptimize, Target: "books_green", RequestId: "optimize-books-1"},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	ctx := context.Background()
+
+	err := r.startOptimize(ctx, (&fakeSolr{failing: true}).start(t), collectionSet, []string{"books_blue"})
+	if err != nil {
+		t.Fatalf("start optimize failed: %v", err)
+	}
+
+	// Both the optimize that failed and the one that was lost are reported, and neither is tracked any longer ...
+	err = waitForOptimizes(t, r, collectionSet)
+	if err == nil || !strings.Contains(err.Error(), "books_blue") || !strings.Contains(err.Error(), "books_green") {
+		t.Fatalf("expected both optimizes to be reported, got [%v]", err)
+	}
+	if len(collectionSet.Status.PendingOperations) != 0 {
+		t.Fatalf("expected no pending operations, got %v", collectionSet.Status.PendingOperations)
+	}
+}
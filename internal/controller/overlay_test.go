@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestConfigOverlaysAreReconciled(t *testing.T) {
+	// The live overlays of the collections, keyed by collection, as Solr nests them ...
+	overlays := map[string]string{
+		"books":   `{"overlay": {"props": {"query": {"maxBooleanClauses": 1024}, "updateHandler": {"autoCommit": {"maxTime": 15000}}}}}`,
+		"authors": `{"overlay": {"props": {"query": {"maxBooleanClauses": 2048}}}}`,
+	}
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"/books/config/overlay":   overlays["books"],
+		"/authors/config/overlay": overlays["authors"],
+		"/books/config":           "",
+		"/authors/config":         "",
+	}}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigOverlay: map[string]string{"query.maxBooleanClauses": "2048"}},
+			{Name: "authors", ConfigOverlay: map[string]string{"query.maxBooleanClauses": "2048"}},
+			{Name: "titles", ConfigOverlay: map[string]string{"query.maxBooleanClauses": "2048"}},
+			{Name: "publishers"},
+		},
+	})
+
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	solrCollections := map[string]solr.Collection{"books": {}, "authors": {}, "publishers": {}}
+	r.ManageConfigOverlays(context.Background(), solrClient, collectionSet, solrCollections)
+	var commands = make(map[string]map[string]interface{})
+	for i, requestUrl := range server.requests {
+		if collection, isCommand := strings.CutSuffix(requestUrl.Path, "/config"); isCommand {
+			var command map[string]interface{}
+			_ = json.Unmarshal(server.bodies[i], &command)
+			commands[strings.TrimPrefix(collection, "/")] = command
+		}
+	}
+
+	// Only the overlay that differs is updated. The collection that doesn't exist yet, and the collection without an
+	// overlay in the spec, are left alone ...
+	expected := map[string]map[string]interface{}{
+		"books": {
+			"set-property":   map[string]interface{}{"query.maxBooleanClauses": "2048"},
+			"unset-property": []interface{}{"updateHandler.autoCommit.maxTime"},
+		},
+	}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Fatalf("expected %v, got %v", expected, commands)
+	}
+}
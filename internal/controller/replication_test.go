@@ -0,0 +1,658 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+// reconcileReplication runs the collection and replica steps of the reconcile until Solr matches the spec ...
+func reconcileReplication(t *testing.T, r *SolrCollectionSetReconciler, solrClient solr.SolrClient,
+	collectionSet solrcollectionsv1.SolrCollectionSet, fake *fakeSolr) {
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		clusterStatus, err := solrClient.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("get cluster status failed: %v", err)
+		}
+		collection := clusterStatus.Collections[fake.collection]
+		if collection.ReplicationFactor == *collectionSet.Spec.ReplicationFactor &&
+			collection.ReplicaCount == *collectionSet.Spec.ReplicationFactor {
+			return
+		}
+		if r.ManageCollections(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases) {
+			continue
+		}
+		if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+			clusterStatus.LiveNodes, "_booksChecksums"); err != nil {
+			t.Fatalf("adjust replicas failed: %v", err)
+		}
+	}
+	t.Fatalf("replication factor [%d] replicas [%d] didn't converge to [%d]",
+		fake.replicationFactor, len(fake.replicas), *collectionSet.Spec.ReplicationFactor)
+}
+
+// indexOf finds the first occurrence of the given action ...
+func indexOf(actions []string, action string) int {
+	for i, a := range actions {
+		if a == action {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestMixedReplicaTypesConverge(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicaType string
+		collection  func(managedReplicas int32) solr.Collection
+	}{
+		{
+			name:        "NRT with PULL replicas",
+			replicaType: solr.ReplicaTypeNRT,
+			collection: func(managedReplicas int32) solr.Collection {
+				return solr.Collection{Name: "books", NrtReplicas: 1, PullReplicas: 2, Shards: map[string]solr.Shard{
+					"shard1": {Name: "shard1", ReplicaCount: managedReplicas + 2, NrtReplicaCount: managedReplicas,
+						PullReplicaCount: 2},
+				}}
+			},
+		},
+		{
+			name:        "TLOG with PULL replicas",
+			replicaType: solr.ReplicaTypeTLOG,
+			collection: func(managedReplicas int32) solr.Collection {
+				return solr.Collection{Name: "books", TlogReplicas: 1, PullReplicas: 2, Shards: map[string]solr.Shard{
+					"shard1": {Name: "shard1", ReplicaCount: managedReplicas + 2, TlogReplicaCount: managedReplicas,
+						PullReplicaCount: 2},
+				}}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The PULL replicas aren't counted, so scaling out adds replicas of the managed type until there are
+			// three of them ...
+			adjustments := make(map[string]solr.ReplicationAdjustment)
+			queueReplicaAdjustment(test.collection(1), 3, nil, adjustments, logr.Discard())
+			expected := solr.ReplicationAdjustment{Collection: "books", Shard: "shard1", CurrentCount: 1,
+				TargetCount: 3, ReplicaType: test.replicaType}
+			if adjustments["books/shard1"] != expected {
+				t.Fatalf("expected adjustment %+v, got %+v", expected, adjustments["books/shard1"])
+			}
+
+			// ... and once there are three of them the PULL replicas don't cause a scale in ...
+			adjustments = make(map[string]solr.ReplicationAdjustment)
+			queueReplicaAdjustment(test.collection(3), 3, nil, adjustments, logr.Discard())
+			if len(adjustments) != 0 {
+				t.Fatalf("expected no adjustments, got %+v", adjustments)
+			}
+		})
+	}
+}
+
+func TestAutoAddReplicasIsReconciled(t *testing.T) {
+	fake := &fakeSolr{responses: map[string]string{"CLUSTERSTATUS": emptyClusterStatus}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(1)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books"},
+			{Name: "authors", ConfigsetName: "authors"},
+			{Name: "titles", ConfigsetName: "titles"},
+			{Name: "series", ConfigsetName: "series"},
+		},
+	})
+
+	enabled, disabled := true, false
+	solrCollections := map[string]solr.Collection{
+		"books":   {Name: "books", ConfigName: "books", ReplicationFactor: 1, AutoAddReplicas: &enabled},
+		"authors": {Name: "authors", ConfigName: "authors", ReplicationFactor: 1, AutoAddReplicas: &disabled},
+		// Newer versions of Solr don't report autoAddReplicas at all ...
+		"titles": {Name: "titles", ConfigName: "titles", ReplicationFactor: 1},
+	}
+
+	if !r.ManageCollections(context.Background(), solrClient, collectionSet, solrCollections, nil) {
+		t.Fatalf("expected collections to be changed")
+	}
+	var actions []string
+	for _, query := range fake.queries("CREATE") {
+		actions = append(actions, "CREATE "+query.Get("name")+" autoAddReplicas="+query.Get("autoAddReplicas"))
+	}
+	for _, query := range fake.queries("MODIFYCOLLECTION") {
+		actions = append(actions, "MODIFYCOLLECTION "+query.Get("collection")+" autoAddReplicas="+
+			query.Get("autoAddReplicas"))
+	}
+	sort.Strings(actions)
+	expected := []string{"CREATE series autoAddReplicas=false", "MODIFYCOLLECTION books autoAddReplicas=false"}
+	if !slices.Equal(actions, expected) {
+		t.Fatalf("expected actions %v, got %v", expected, actions)
+	}
+}
+
+func TestReplicasAreAdjustedPerShard(t *testing.T) {
+	collection := solr.Collection{
+		Name:        "books",
+		NrtReplicas: 2,
+		Shards: map[string]solr.Shard{
+			"shard1": {Name: "shard1", NrtReplicaCount: 2},
+			"shard2": {Name: "shard2", NrtReplicaCount: 1},
+			"shard3": {Name: "shard3", NrtReplicaCount: 3},
+		},
+	}
+	adjustments := make(map[string]solr.ReplicationAdjustment)
+	queueReplicaAdjustment(collection, 2, nil, adjustments, logr.Discard())
+
+	// Only the shards that are off target are adjusted, each by its own difference ...
+	expected := map[string]solr.ReplicationAdjustment{
+		"books/shard2": {Collection: "books", Shard: "shard2", CurrentCount: 1, TargetCount: 2,
+			ReplicaType: solr.ReplicaTypeNRT},
+		"books/shard3": {Collection: "books", Shard: "shard3", CurrentCount: 3, TargetCount: 2,
+			ReplicaType: solr.ReplicaTypeNRT},
+	}
+	if !reflect.DeepEqual(adjustments, expected) {
+		t.Fatalf("expected adjustments %v, got %v", expected, adjustments)
+	}
+}
+
+func TestChecksumsCollectionUsesItsOwnReplicationFactor(t *testing.T) {
+	fake := &fakeSolr{collection: "_booksChecksums", replicationFactor: 1, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(1)
+	checksumReplicationFactor := int32(3)
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor:         &replicationFactor,
+		ChecksumReplicationFactor: &checksumReplicationFactor,
+		BlueGreenEnabled:          &blueGreenEnabled,
+		CleanupEnabled:            &cleanupEnabled,
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 10 && (fake.replicationFactor != 3 || len(fake.replicas) != 3); i++ {
+		clusterStatus, err := solrClient.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("get cluster status failed: %v", err)
+		}
+		if r.ManageCollections(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases) {
+			continue
+		}
+		if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+			clusterStatus.LiveNodes, "_booksChecksums"); err != nil {
+			t.Fatalf("adjust replicas failed: %v", err)
+		}
+	}
+	if fake.replicationFactor != 3 || len(fake.replicas) != 3 {
+		t.Fatalf("expected the checksums collection to have 3 replicas, got replication factor [%d] replicas %v",
+			fake.replicationFactor, fake.replicas)
+	}
+}
+
+func TestReplicationFactorChangeConverges(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 1, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(3)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books"},
+		},
+	})
+
+	// Scale out ...
+	reconcileReplication(t, r, solrClient, collectionSet, fake)
+	if modify, add := indexOf(fake.actions, "MODIFYCOLLECTION"), indexOf(fake.actions, "ADDREPLICA"); modify < 0 || add < modify {
+		t.Fatalf("expected MODIFYCOLLECTION before ADDREPLICA, got %v", fake.actions)
+	}
+
+	// ... and back in again ...
+	fake.actions = nil
+	replicationFactor = 1
+	reconcileReplication(t, r, solrClient, collectionSet, fake)
+	if modify, remove := indexOf(fake.actions, "MODIFYCOLLECTION"), indexOf(fake.actions, "DELETEREPLICA"); modify < 0 || remove < modify {
+		t.Fatalf("expected MODIFYCOLLECTION before DELETEREPLICA, got %v", fake.actions)
+	}
+	// The leader should have been kept ...
+	if len(fake.replicas) != 1 || fake.replicas[0] != "core_node0" {
+		t.Fatalf("expected the leader [core_node0] to be kept, got %v", fake.replicas)
+	}
+}
+
+func TestDisabledCollectionIsLeftAlone(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 2, replicas: []string{"core_node0", "core_node1"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(3)
+	disabled := int32(0)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books", ReplicationFactor: &disabled},
+			{Name: "authors", ConfigsetName: "authors", Alias: "authors", ReplicationFactor: &disabled},
+		},
+	})
+
+	ctx := context.Background()
+	clusterStatus, err := solrClient.GetClusterStatus(ctx)
+	if err != nil {
+		t.Fatalf("get cluster status failed: %v", err)
+	}
+	if r.ManageCollections(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases) {
+		t.Fatalf("expected disabled collections to be left alone, got %v", fake.actions)
+	}
+	if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+		clusterStatus.LiveNodes, "_booksChecksums"); err != nil {
+		t.Fatalf("adjust replicas failed: %v", err)
+	}
+	if slices.ContainsFunc(fake.actions, func(action string) bool { return action != "CLUSTERSTATUS" }) {
+		t.Fatalf("expected no changes to disabled collections, got %v", fake.actions)
+	}
+
+	// A disabled collection doesn't make the set unstable ...
+	status := solrcollectionsv1.SolrCollectionSetStatus{}
+	populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+	if status.ReadyRatio != "0/0" {
+		t.Fatalf("expected disabled collections not to be counted, got ready ratio [%s]", status.ReadyRatio)
+	}
+	for _, condition := range status.Conditions {
+		if condition.Type == typeSolrCollectionSetReplicasReady && condition.Status != "True" {
+			t.Fatalf("expected replicas to be ready, got [%s] [%s]", condition.Status, condition.Reason)
+		}
+	}
+}
+
+func TestReplicasDecoupledFromReplicationFactor(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 1, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(1)
+	replicas := int32(3)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		Replicas:          &replicas,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books"},
+		},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 10 && len(fake.replicas) != int(replicas); i++ {
+		clusterStatus, err := solrClient.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("get cluster status failed: %v", err)
+		}
+		if r.ManageCollections(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases) {
+			continue
+		}
+		if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+			clusterStatus.LiveNodes, "_booksChecksums"); err != nil {
+			t.Fatalf("adjust replicas failed: %v", err)
+		}
+	}
+	if len(fake.replicas) != int(replicas) {
+		t.Fatalf("expected [%d] replicas, got %v", replicas, fake.replicas)
+	}
+	if fake.replicationFactor != int(replicationFactor) || indexOf(fake.actions, "MODIFYCOLLECTION") >= 0 {
+		t.Fatalf("expected the replication factor to be left at [%d], got [%d] %v", replicationFactor,
+			fake.replicationFactor, fake.actions)
+	}
+}
+
+func TestChecksumsCollectionFollowsReplicationFactor(t *testing.T) {
+	fake := &fakeSolr{collection: "_booksChecksums", replicationFactor: 1, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(2)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+	})
+
+	reconcileReplication(t, r, solrClient, collectionSet, fake)
+	if modify, add := indexOf(fake.actions, "MODIFYCOLLECTION"), indexOf(fake.actions, "ADDREPLICA"); modify < 0 || add < modify {
+		t.Fatalf("expected MODIFYCOLLECTION before ADDREPLICA, got %v", fake.actions)
+	}
+}
+
+func TestSharedChecksumsCollectionKeepsItsReplicationFactor(t *testing.T) {
+	fake := &fakeSolr{collection: "_sharedChecksums", replicationFactor: 1, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	ctx := context.Background()
+
+	// Sets sharing the checksums collection which disagree on its replication factor don't change it ...
+	for _, replicationFactor := range []int32{2, 3} {
+		sharedChecksums := true
+		collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+			ReplicationFactor: &replicationFactor,
+			SharedChecksums:   &sharedChecksums,
+		})
+		collectionSet.Name = fmt.Sprintf("set%d", replicationFactor)
+
+		clusterStatus, err := solrClient.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("get cluster status failed: %v", err)
+		}
+		r.ManageCollections(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases)
+		if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+			clusterStatus.LiveNodes, "_sharedChecksums"); err != nil {
+			t.Fatalf("adjust replicas failed: %v", err)
+		}
+	}
+	if slices.ContainsFunc(fake.actions, func(action string) bool { return action != "CLUSTERSTATUS" }) {
+		t.Fatalf("expected the shared checksums collection to be left alone, got %v", fake.actions)
+	}
+}
+
+func TestReplicationFactorLeftAloneIfNotReconciled(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 1, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(3)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor:  &replicationFactor,
+		AutoAddReplicas:    &autoAddReplicas,
+		BlueGreenEnabled:   &blueGreenEnabled,
+		CleanupEnabled:     &cleanupEnabled,
+		ReconciledSettings: []string{solrcollectionsv1.SolrCollectionSettingAutoAddReplicas},
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books"},
+		},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		clusterStatus, err := solrClient.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("get cluster status failed: %v", err)
+		}
+		r.ManageCollections(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases)
+		if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+			clusterStatus.LiveNodes, "_booksChecksums"); err != nil {
+			t.Fatalf("adjust replicas failed: %v", err)
+		}
+	}
+
+	// The replicas are still scaled to the spec, but the recorded replication factor is left alone ...
+	if indexOf(fake.actions, "MODIFYCOLLECTION") >= 0 {
+		t.Fatalf("expected no MODIFYCOLLECTION, got %v", fake.actions)
+	}
+	if fake.replicationFactor != 1 || len(fake.replicas) != 3 {
+		t.Fatalf("expected replication factor [1] with [3] replicas, got [%d] with %v", fake.replicationFactor,
+			fake.replicas)
+	}
+}
+
+func TestReplicasOnDeadNodesAreRepaired(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 3,
+		replicas: []string{"core_node0", "core_node1", "core_node2"}, nextReplica: 2, deadReplicas: []string{"core_node2"}}
+	solrClient := fake.start(t)
+	recorder := record.NewFakeRecorder(100)
+	r := &SolrCollectionSetReconciler{Recorder: recorder}
+
+	replicationFactor := int32(3)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books"},
+		},
+	})
+
+	ctx := context.Background()
+	clusterStatus, err := solrClient.GetClusterStatus(ctx)
+	if err != nil {
+		t.Fatalf("get cluster status failed: %v", err)
+	}
+
+	// The lost replica is a repair, not a scale out ...
+	status := solrcollectionsv1.SolrCollectionSetStatus{}
+	events := populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+	if _, exists := events[eventSolrCollectionSetScaleOut]; exists {
+		t.Fatalf("expected no scale out event, got %v", events)
+	}
+
+	if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+		clusterStatus.LiveNodes, "_booksChecksums"); err != nil {
+		t.Fatalf("adjust replicas failed: %v", err)
+	}
+	if len(fake.replicas) != 4 || fake.replicas[3] != "core_node3" {
+		t.Fatalf("expected a replica to be added to replace [core_node2], got %v", fake.replicas)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, eventSolrCollectionSetReplicaRepair) {
+			t.Fatalf("expected a [%s] event, got [%s]", eventSolrCollectionSetReplicaRepair, event)
+		}
+	default:
+		t.Fatalf("expected a [%s] event", eventSolrCollectionSetReplicaRepair)
+	}
+}
+
+func TestReplicaRepairWaitsForMissingNodes(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 3,
+		replicas: []string{"core_node0", "core_node1", "core_node2"}, nextReplica: 2, deadReplicas: []string{"core_node2"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(3)
+	blueGreenEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books"},
+		},
+	})
+
+	ctx := context.Background()
+	clusterStatus, err := solrClient.GetClusterStatus(ctx)
+	if err != nil {
+		t.Fatalf("get cluster status failed: %v", err)
+	}
+
+	// The node has only just gone missing (e.g. it's restarting), so its replica still counts ...
+	populateCollectionSetStatus(&collectionSet.Status, &collectionSet, clusterStatus, logr.Discard())
+	if _, exists := collectionSet.Status.MissingNodes["solr-2:8983_solr"]; !exists {
+		t.Fatalf("expected the node of [core_node2] to be missing, got %v", collectionSet.Status.MissingNodes)
+	}
+	if status := collectionSet.Status.SolrCollections[0].ReplicationStatus; status != "3/3" {
+		t.Fatalf("expected replication status [3/3], got [%s]", status)
+	}
+	liveNodes := presumedLiveNodes(clusterStatus.LiveNodes, collectionSet.Status.MissingNodes,
+		replicaRepairDelay(collectionSet))
+	if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+		liveNodes, "_booksChecksums"); err != nil {
+		t.Fatalf("adjust replicas failed: %v", err)
+	}
+	if len(fake.replicas) != 3 {
+		t.Fatalf("expected no replica to be added yet, got %v", fake.replicas)
+	}
+	if wait, ok := nextRepairDue(collectionSet.Status.MissingNodes, replicaRepairDelay(collectionSet)); !ok ||
+		wait > replicaRepairDelay(collectionSet)+time.Second {
+		t.Fatalf("expected a reconcile once the repair is due, got [%s] [%t]", wait, ok)
+	}
+
+	// ... but once it's been missing for the delay the replica is replaced ...
+	collectionSet.Status.MissingNodes["solr-2:8983_solr"] = metav1.NewTime(
+		time.Now().Add(-replicaRepairDelay(collectionSet) - time.Second))
+	populateCollectionSetStatus(&collectionSet.Status, &collectionSet, clusterStatus, logr.Discard())
+	if status := collectionSet.Status.SolrCollections[0].ReplicationStatus; status != "2/3" {
+		t.Fatalf("expected replication status [2/3], got [%s]", status)
+	}
+	liveNodes = presumedLiveNodes(clusterStatus.LiveNodes, collectionSet.Status.MissingNodes,
+		replicaRepairDelay(collectionSet))
+	if _, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections, clusterStatus.Aliases,
+		liveNodes, "_booksChecksums"); err != nil {
+		t.Fatalf("adjust replicas failed: %v", err)
+	}
+	if len(fake.replicas) != 4 {
+		t.Fatalf("expected a replica to be added to replace [core_node2], got %v", fake.replicas)
+	}
+}
+
+func TestInactiveColorScaledToInactiveReplicas(t *testing.T) {
+	replicas := int32(3)
+	inactiveReplicas := int32(1)
+	blueGreenEnabled := true
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Replicas:         &replicas,
+		InactiveReplicas: &inactiveReplicas,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books"}, {Name: "authors", ActiveColor: "green"}, {Name: "titles", AliasAllColors: true},
+		},
+	})
+	aliases := map[string][]string{"books": {"books_blue"}, "authors": {"authors_blue"}}
+
+	tests := []struct {
+		spec         solrcollectionsv1.SolrCollection
+		instanceName string
+		expected     int32
+	}{
+		{spec: collectionSet.Spec.Collections[0], instanceName: "books_blue", expected: 3},
+		{spec: collectionSet.Spec.Collections[0], instanceName: "books_green", expected: 1},
+		// The color being promoted to is scaled up along with the color the alias still points at ...
+		{spec: collectionSet.Spec.Collections[1], instanceName: "authors_blue", expected: 3},
+		{spec: collectionSet.Spec.Collections[1], instanceName: "authors_green", expected: 3},
+		{spec: collectionSet.Spec.Collections[2], instanceName: "titles_green", expected: 3},
+	}
+	for _, test := range tests {
+		if replicas := instanceReplicas(collectionSet, test.spec, test.instanceName, aliases); replicas != test.expected {
+			t.Errorf("expected [%s] to be scaled to [%d] replicas, got [%d]", test.instanceName, test.expected, replicas)
+		}
+	}
+}
+
+func TestScaleOutIsSpreadOverReconciles(t *testing.T) {
+	fake := &fakeSolr{collection: "books", replicationFactor: 5, replicas: []string{"core_node0"}}
+	solrClient := fake.start(t)
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	replicationFactor := int32(5)
+	scaleOutBatchSize := int32(2)
+	autoAddReplicas := false
+	blueGreenEnabled := false
+	cleanupEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		ReplicationFactor: &replicationFactor,
+		ScaleOutBatchSize: &scaleOutBatchSize,
+		AutoAddReplicas:   &autoAddReplicas,
+		BlueGreenEnabled:  &blueGreenEnabled,
+		CleanupEnabled:    &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books", Alias: "books"},
+		},
+	})
+
+	// The four missing replicas are added two at a time, and the status shows the progress in between ...
+	ctx := context.Background()
+	for _, expected := range []struct {
+		replicas  int
+		isScaling bool
+		progress  string
+	}{
+		{replicas: 3, isScaling: true, progress: "[1/5]"},
+		{replicas: 5, isScaling: false, progress: "[3/5]"},
+	} {
+		clusterStatus, err := solrClient.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatalf("get cluster status failed: %v", err)
+		}
+		status := solrcollectionsv1.SolrCollectionSetStatus{}
+		populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+		replicasReady := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetReplicasReady)
+		if replicasReady == nil || !strings.Contains(replicasReady.Message, expected.progress) {
+			t.Fatalf("expected the progress %s, got %+v", expected.progress, replicasReady)
+		}
+		isScaling, err := r.AdjustReplicas(ctx, solrClient, collectionSet, clusterStatus.Collections,
+			clusterStatus.Aliases, clusterStatus.LiveNodes, "_booksChecksums")
+		if err != nil {
+			t.Fatalf("adjust replicas failed: %v", err)
+		}
+		if len(fake.replicas) != expected.replicas || isScaling != expected.isScaling {
+			t.Fatalf("expected [%d] replicas with isScaling [%t], got %v with isScaling [%t]", expected.replicas,
+				expected.isScaling, fake.replicas, isScaling)
+		}
+	}
+}
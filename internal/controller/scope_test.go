@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestScopedReconcileLeavesOtherCollectionsAlone(t *testing.T) {
+	server := &fakeSolr{responses: map[string]string{"CLUSTERSTATUS": emptyClusterStatus}}
+	solrClient := server.start(t)
+
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	markedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		CleanupEnabled:   &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books"}, {Name: "books_archive"}, {Name: "authors"}, {Name: "titles"},
+		},
+		RoutedAliases: []solrcollectionsv1.SolrRoutedAlias{
+			{Name: "books_by_year", Router: "time", Field: "published"},
+			{Name: "authors_by_year", Router: "time", Field: "born"},
+		},
+	})
+	collectionSet.Annotations = map[string]string{annotationReconcileOnly: "books*, titles"}
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		ManagedCollections: []string{"books", "publishers"},
+		MarkedForDeletion: map[string]solrcollectionsv1.DeletionMark{
+			"publishers": {MarkedAt: markedAt, DeleteAfter: markedAt},
+		},
+	}
+
+	scoped, err := scopedCollectionSet(collectionSet, collectionSet.Annotations[annotationReconcileOnly])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, spec := range scoped.Spec.Collections {
+		names = append(names, spec.Name)
+	}
+	if !slices.Equal(names, []string{"books", "books_archive", "titles"}) {
+		t.Fatalf("expected collections [books books_archive titles], got %v", names)
+	}
+	if len(collectionSet.Spec.Collections) != 4 || !*collectionSet.Spec.CleanupEnabled {
+		t.Fatalf("expected the collection set itself to be left as it was")
+	}
+
+	// Only the matching collections are created, and the collection that's due for deletion is left alone (along
+	// with its mark) since it's outside of the scope ...
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	solrCollections := map[string]solr.Collection{"books": {}, "publishers": {}}
+	r.ManageCollections(context.Background(), solrClient, scoped, solrCollections, nil)
+	created := server.called("CREATE")
+	slices.Sort(created)
+	if !slices.Equal(created, []string{"CREATE books_archive", "CREATE titles"}) {
+		t.Fatalf("expected only [books_archive titles] to be created, got %v", created)
+	}
+	if deleted := server.called("DELETE"); len(deleted) > 0 {
+		t.Fatalf("expected nothing to be deleted, got %v", deleted)
+	}
+
+	// Likewise only the matching routed aliases are created ...
+	server.requests = nil
+	r.ManageRoutedAliases(context.Background(), solrClient, scoped, map[string][]string{})
+	if calls := server.called(); !slices.Equal(calls, []string{"CREATEALIAS books_by_year"}) ||
+		len(scoped.Spec.RoutedAliases) != 1 || scoped.Spec.RoutedAliases[0].Name != "books_by_year" {
+		t.Fatalf("expected only routed alias [books_by_year] to be created, got %v", calls)
+	}
+
+	// A bad pattern is refused ...
+	if _, err := scopedCollectionSet(collectionSet, "books["); err == nil {
+		t.Fatalf("expected an error for a bad pattern")
+	}
+}
+
+func TestScopedReconcileLeavesOtherAliasesAlone(t *testing.T) {
+	server := &fakeSolr{}
+	solrClient := server.start(t)
+
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "titles"}},
+	})
+	collectionSet.Annotations = map[string]string{annotationReconcileOnly: "books"}
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{
+		Aliases: []solrcollectionsv1.AliasStatus{{Name: "books"}, {Name: "titles"}},
+	}
+	scoped, err := scopedCollectionSet(collectionSet, collectionSet.Annotations[annotationReconcileOnly])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The alias of the collection outside of the scope is still live, so it's left where it points ...
+	r := &SolrCollectionSetReconciler{Recorder: record.NewFakeRecorder(100)}
+	clusterStatus := solr.ClusterStatus{
+		Collections: map[string]solr.Collection{"books_blue": {}, "titles_blue": {}},
+		Aliases:     map[string][]string{"books": {"books_blue"}, "titles": {"titles_blue"}},
+	}
+	if changed := r.ManageAliases(context.Background(), solrClient, &scoped, clusterStatus, nil); changed {
+		t.Fatalf("expected no aliases to change, got %v", server.called())
+	}
+	if calls := server.called(); indexOf(calls, "DELETEALIAS titles") >= 0 {
+		t.Fatalf("expected alias [titles] not to be removed, got %v", calls)
+	}
+}
+
+func TestOnlyTheLeaderTalksToSolr(t *testing.T) {
+	server := &fakeSolr{strict: true, responses: map[string]string{
+		"LIST":          `{"configSets": []}`,
+		"CLUSTERSTATUS": emptyClusterStatus,
+	}}
+	solrClient := server.start(t)
+
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		SolrClusterUrl: solrClient.Url,
+		SecretRef:      "solr-auth",
+		Mode:           solrcollectionsv1.SolrCollectionSetModeObserve,
+	})
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "default"}}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	elected := make(chan struct{})
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet, secret).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+		Elected:  elected,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	// Until this instance is elected it leaves Solr alone ...
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if len(server.requests) != 0 {
+		t.Fatalf("expected no Solr calls before being elected, got %v", server.requests)
+	}
+
+	// ... and once it's the leader it gets going ...
+	close(elected)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if len(server.requests) == 0 {
+		t.Fatalf("expected Solr calls once elected")
+	}
+}
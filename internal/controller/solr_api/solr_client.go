@@ -3,47 +3,160 @@ package solr_api
 import (
 	"bytes"
 	"context"
+	"errors"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// SolrClient is a basic auth client for the Solr API.
+// SolrClient is a client for the Solr API. It authenticates with basic auth or a bearer token.
 type SolrClient struct {
 	Username string
 	Password string
-	Url      string
+	// BearerToken is sent rather than the username and password if it's given (unless AuthMode is AuthModeBasic)
+	BearerToken string
+	// TokenSource is called for the bearer token on every request, so that a token that expires (e.g. a JWT) can be
+	// refreshed. It takes precedence over BearerToken.
+	TokenSource func(ctx context.Context) (string, error)
+	// AuthMode is AuthModeBasic or AuthModeBearer. If it's empty then a bearer token is sent if there is one and basic
+	// auth is used otherwise.
+	AuthMode string
+	// CredentialsPath is where the credentials are read from (on every request, so that they can be rotated on disk)
+	// rather than Username, Password and BearerToken. It's either a directory holding the files username, password
+	// and/or token (e.g. a mounted secret), or a file holding a bearer token (e.g. a projected service account token).
+	CredentialsPath string
+	Url             string
+	// RateLimiter throttles calls to the Solr API. It's shared across reconciles (and collection sets) so that the
+	// operator as a whole can't overwhelm the Solr admin API. If nil then calls aren't throttled.
+	RateLimiter *rate.Limiter
+	// CommitStrategy determines how records written by WriteRecord are committed. One of CommitStrategyHard (the
+	// default if empty), CommitStrategySoft, or CommitStrategyWithin.
+	CommitStrategy string
+	// CommitWithinMillis is the commitWithin time used by CommitStrategyWithin
+	CommitWithinMillis int
+	// Headers are added to every request (e.g. an API key required by a gateway in front of Solr)
+	Headers map[string]string
+	// OnMutation is called after every request which changes Solr (but not after read-only requests). If nil then
+	// nothing is called.
+	OnMutation func(ctx context.Context, mutation Mutation)
+	// Transport sends the requests to Solr (e.g. wrapping http.DefaultTransport to time them). If nil then
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+}
+
+// IsConfigured returns true once the client has been pointed at a Solr cluster ...
+func (r *SolrClient) IsConfigured() bool {
+	return r.Url != ""
+}
+
+// CheckAuth makes a cheap authenticated admin call (listing the collections) to make sure the client's credentials
+// are accepted. Like every other call, ErrAuthFailed is returned if Solr rejects the credentials (401) and
+// ErrAuthInsufficient if they're accepted but aren't allowed to use the Collections API (403) ...
+func (r *SolrClient) CheckAuth(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/admin/collections?action=LIST&wt=json", r.Url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	msg, _ := parseError(resp.Body)
+	return fmt.Errorf("could not check the Solr credentials [%s] [%s]", resp.Status, msg)
 }
 
 type ReplicationAdjustment struct {
-	CurrentCount int32 // The current number of replicas
-	TargetCount  int32 // The desired number of replicas
+	Collection   string // The name of the collection being adjusted
+	Shard        string // The name of the shard being adjusted
+	CurrentCount int32  // The current number of replicas
+	TargetCount  int32  // The desired number of replicas
+	ReplicaType  string // The type of replica being adjusted
+	Repair       bool   // True if replicas are being added to replace replicas on nodes that aren't live
 }
 
+// GetClusterStatus gets the status of every collection in the cluster ...
 func (r *SolrClient) GetClusterStatus(ctx context.Context) (ClusterStatus, error) {
+	clusterStatus, _, err := r.getClusterStatus(ctx, "")
+	return clusterStatus, err
+}
+
+// GetClusterStatusOf gets the status of only the given collections, which keeps the response small on a cluster with
+// many collections. Solr only scopes the cluster status to a single collection, so each collection is asked for
+// separately. Collections which don't exist are left out of the status ...
+func (r *SolrClient) GetClusterStatusOf(ctx context.Context, collectionNames []string) (ClusterStatus, error) {
+	clusterStatus := ClusterStatus{
+		Aliases:     make(map[string][]string),
+		Collections: make(map[string]Collection),
+	}
+	for _, collectionName := range collectionNames {
+		collectionStatus, found, err := r.getClusterStatus(ctx, collectionName)
+		if err != nil {
+			return ClusterStatus{}, err
+		}
+		if !found {
+			continue
+		}
+		maps.Copy(clusterStatus.Aliases, collectionStatus.Aliases)
+		maps.Copy(clusterStatus.Collections, collectionStatus.Collections)
+		clusterStatus.LiveNodes = collectionStatus.LiveNodes
+	}
+	return clusterStatus, nil
+}
+
+// getClusterStatus gets the cluster status, scoped to the given collection unless it's empty. found is false if the
+// collection doesn't exist ...
+func (r *SolrClient) getClusterStatus(ctx context.Context, collectionName string) (clusterStatus ClusterStatus,
+	found bool, err error) {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
 
 	url := fmt.Sprintf("%s/admin/collections?action=CLUSTERSTATUS", r.Url)
+	if collectionName != "" {
+		url = fmt.Sprintf("%s&collection=%s", url, neturl.QueryEscape(collectionName))
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return ClusterStatus{}, err
+		return ClusterStatus{}, false, err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
-		return ClusterStatus{}, err
+		return ClusterStatus{}, false, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -54,60 +167,101 @@ func (r *SolrClient) GetClusterStatus(ctx context.Context) (ClusterStatus, error
 
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return ClusterStatus{}, fmt.Errorf("could not get cluster status [%s] [%s]", resp.Status, msg)
+		// Solr responds with a bad request if the collection doesn't exist ...
+		if collectionName != "" && (resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound) &&
+			strings.Contains(msg, "not found") {
+			return ClusterStatus{}, false, nil
+		}
+		return ClusterStatus{}, false, fmt.Errorf("could not get cluster status [%s] [%s]", resp.Status, msg)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return ClusterStatus{}, err
+		return ClusterStatus{}, false, err
 	}
 
 	// Read the response string into a map data structure ....
 	var jsonResponse map[string]interface{}
 	e := json.Unmarshal(body, &jsonResponse)
 	if e != nil {
-		return ClusterStatus{}, e
+		return ClusterStatus{}, false, e
 	}
 
 	var jsonCluster = jsonResponse["cluster"]
 	var jsonAliases = jsonCluster.(map[string]interface{})["aliases"]
 	var jsonCollections = jsonCluster.(map[string]interface{})["collections"]
+	var jsonLiveNodes, _ = jsonCluster.(map[string]interface{})["live_nodes"].([]interface{})
 
-	aliases := make(map[string]string)
+	aliases := make(map[string][]string)
 	collections := make(map[string]Collection)
 
 	// Map the aliases ...
 	if jsonAliases != nil {
 		for key, value := range jsonAliases.(map[string]interface{}) {
-			aliases[key] = value.(string)
+			aliases[key] = parseAliasCollections(value)
 		}
 	}
 	// Map the collections ...
 	if jsonCollections != nil {
 		for collection, value := range jsonCollections.(map[string]interface{}) {
 
-			var rawReplicationFactor = value.(map[string]interface{})["replicationFactor"]
+			var jsonCollection = value.(map[string]interface{})
 			var replicaCount int32
 			var replicationFactor int32
 
-			replicaCount = countReplicas(value)
-			replicationFactor = interfaceToInt32(rawReplicationFactor)
+			replicaCount, shards := countReplicas(value)
+			var replicas []Replica
+			for _, shardName := range slices.Sorted(maps.Keys(shards)) {
+				replicas = append(replicas, shards[shardName].Replicas...)
+			}
+			replicaTypeCounts := leastReplicatedCounts(shards)
+			nrtReplicas := interfaceToInt32(jsonCollection["nrtReplicas"])
+			// Newer versions of Solr treat replicationFactor as an alias for nrtReplicas and may omit it ...
+			replicationFactor = interfaceToInt32(jsonCollection["replicationFactor"])
+			if replicationFactor == 0 {
+				replicationFactor = nrtReplicas
+			}
+			router, _ := jsonCollection["router"].(map[string]interface{})
 
 			collections[collection] = Collection{
-				Name:              collection,
-				ConfigName:        value.(map[string]interface{})["configName"].(string),
-				ReplicationFactor: replicationFactor,
-				ReplicaCount:      replicaCount,
+				Name:               collection,
+				ConfigName:         jsonCollection["configName"].(string),
+				RouterName:         interfaceToString(router["name"]),
+				RouterField:        interfaceToString(router["field"]),
+				ReplicationFactor:  replicationFactor,
+				ReplicaCount:       replicaCount,
+				AutoAddReplicas:    interfaceToBoolPtr(jsonCollection["autoAddReplicas"]),
+				CreationTimeMillis: interfaceToInt64(jsonCollection["creationTimeMillis"]),
+				NrtReplicas:        nrtReplicas,
+				TlogReplicas:       interfaceToInt32(jsonCollection["tlogReplicas"]),
+				PullReplicas:       interfaceToInt32(jsonCollection["pullReplicas"]),
+				NrtReplicaCount:    replicaTypeCounts[ReplicaTypeNRT],
+				TlogReplicaCount:   replicaTypeCounts[ReplicaTypeTLOG],
+				PullReplicaCount:   replicaTypeCounts[ReplicaTypePULL],
+				Shards:             shards,
+				Replicas:           replicas,
+				Owner: CollectionOwner{
+					Name:      interfaceToString(jsonCollection[ownerNameProperty]),
+					Namespace: interfaceToString(jsonCollection[ownerNamespaceProperty]),
+					Uid:       interfaceToString(jsonCollection[ownerUidProperty]),
+				},
+				Properties: collectionProperties(jsonCollection),
 			}
 		}
 	}
 
-	clusterStatus := ClusterStatus{
+	liveNodes := make([]string, 0, len(jsonLiveNodes))
+	for _, liveNode := range jsonLiveNodes {
+		liveNodes = append(liveNodes, interfaceToString(liveNode))
+	}
+
+	clusterStatus = ClusterStatus{
 		Aliases:     aliases,
 		Collections: collections,
+		LiveNodes:   liveNodes,
 	}
 
-	return clusterStatus, nil
+	return clusterStatus, true, nil
 }
 
 // Gets the config sets that are present in Solr.
@@ -123,9 +277,7 @@ func (r *SolrClient) GetConfigSets(ctx context.Context) ([]string, error) {
 		return nil, err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -163,8 +315,10 @@ func (r *SolrClient) GetConfigSets(ctx context.Context) ([]string, error) {
 	return configSets, nil
 }
 
-// UploadConfigSet creates a configset
-func (r *SolrClient) UploadConfigSet(ctx context.Context, configSetName string, body []byte) error {
+// UploadConfigSet creates a configset. The zipped config set is streamed from the given reader rather than being
+// buffered. contentLength is the size of the zip, or -1 if it isn't known (in which case the body is sent chunked)
+func (r *SolrClient) UploadConfigSet(ctx context.Context, configSetName string, body io.Reader,
+	contentLength int64) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
@@ -172,17 +326,15 @@ func (r *SolrClient) UploadConfigSet(ctx context.Context, configSetName string,
 	// https://solr.apache.org/guide/solr/latest/configuration-guide/configsets-api.html
 	url := fmt.Sprintf("%s/admin/configs?action=UPLOAD&name=%s&overwrite=true&cleanup=true&wt=json", r.Url, configSetName)
 
-	bodyReader := bytes.NewBuffer(body)
-	req, err := http.NewRequest("POST", url, bodyReader)
+	req, err := http.NewRequest("POST", url, body)
 	if err != nil {
 		return err
 	}
-
-	r.addBasicAuth(req)
+	req.ContentLength = contentLength
 
 	req.Header.Set("Content-Type", "application/octet-stream")
 
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -195,13 +347,68 @@ func (r *SolrClient) UploadConfigSet(ctx context.Context, configSetName string,
 	}(resp.Body)
 
 	if resp.StatusCode != 200 {
-		msg, _ := parseError(resp.Body)
+		// Include the details so that it's clear exactly why the config set was rejected ...
+		msg, details, _ := parseErrorDetail(resp.Body)
+		if details != "" {
+			return fmt.Errorf("create config set %s failed with [%s] [%s] details [%s]",
+				configSetName, resp.Status, msg, details)
+		}
 		return fmt.Errorf("create config set %s failed with [%s] [%s]", configSetName, resp.Status, msg)
 	}
 
 	return nil
 }
 
+// DownloadConfigSet downloads the given config set from Solr. The config set is returned as a zip ...
+func (r *SolrClient) DownloadConfigSet(ctx context.Context, configSetName string) ([]byte, error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/admin/configs?action=DOWNLOAD&name=%s", r.Url, configSetName)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return nil, fmt.Errorf("download config set %s failed with [%s] [%s]", configSetName, resp.Status, msg)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("download config set %s failed reading the zip: %w", configSetName, err)
+	}
+	// Make sure a zip came back rather than e.g. a JSON response from a Solr that doesn't support DOWNLOAD ...
+	if !bytes.HasPrefix(body, zipLocalFileHeader) && !bytes.HasPrefix(body, zipEndOfCentralDirectory) {
+		msg, _ := parseError(bytes.NewReader(body))
+		return nil, fmt.Errorf("download config set %s didn't return a zip [%s] [%s]", configSetName,
+			resp.Header.Get("Content-Type"), msg)
+	}
+
+	return body, nil
+}
+
+// The signatures a zip starts with. An empty zip has only the end of central directory record ...
+var (
+	zipLocalFileHeader       = []byte("PK\x03\x04")
+	zipEndOfCentralDirectory = []byte("PK\x05\x06")
+)
+
 // DeleteConfigSet deletes the given config set from Solr ...
 func (r *SolrClient) DeleteConfigSet(ctx context.Context, configSetName string) error {
 	logger := log.FromContext(ctx)
@@ -215,9 +422,7 @@ func (r *SolrClient) DeleteConfigSet(ctx context.Context, configSetName string)
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -250,9 +455,7 @@ func (r *SolrClient) SetReplicationFactor(ctx context.Context, collectionName st
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -272,25 +475,100 @@ func (r *SolrClient) SetReplicationFactor(ctx context.Context, collectionName st
 	return nil
 }
 
-// AddReplicas adds the given number of replicas
-func (r *SolrClient) AddReplicas(ctx context.Context, collectionName string, increaseCount int32) (isScaling bool, error error) {
+// SetConfigSet points the given collection at the given config set. The collection has to be reloaded before it uses
+// the config set ...
+func (r *SolrClient) SetConfigSet(ctx context.Context, collectionName string, configSetName string) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
+	url := fmt.Sprintf("%s/admin/collections?action=MODIFYCOLLECTION&collection=%s&collection.configName=%s&wt=json",
+		r.Url, collectionName, neturl.QueryEscape(configSetName))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("set config set [%s] failed on collection [%s] with [%s] [%s]",
+			configSetName, collectionName, resp.Status, msg)
+	}
+
+	return nil
+}
 
-	url := fmt.Sprintf("%s/admin/collections?action=ADDREPLICA&collection=%s&shard=shard1&nrtReplicas=%d&wt=json",
-		r.Url, collectionName, increaseCount)
+// SetAutoAddReplicas turns autoAddReplicas on or off for the given collection ...
+func (r *SolrClient) SetAutoAddReplicas(ctx context.Context, collectionName string, autoAddReplicas bool) error {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+	url := fmt.Sprintf("%s/admin/collections?action=MODIFYCOLLECTION&collection=%s&autoAddReplicas=%t&wt=json",
+		r.Url, collectionName, autoAddReplicas)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return false, err
+		return err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("set autoAddReplicas failed on collection [%s] with [%s] [%s]",
+			collectionName, resp.Status, msg)
 	}
 
-	r.addBasicAuth(req)
+	return nil
+}
+
+// AddReplicas adds the given number of replicas of the given type (NRT, TLOG, or PULL) to the given shard. If nodes
+// are given the replicas are placed on them (node if there's one, createNodeSet otherwise), otherwise Solr places them
+func (r *SolrClient) AddReplicas(ctx context.Context, collectionName string, shardName string, replicaType string,
+	increaseCount int32, nodes []string, policy string) (isScaling bool, error error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/admin/collections?action=ADDREPLICA&collection=%s&shard=%s&%sReplicas=%d&wt=json",
+		r.Url, collectionName, shardName, strings.ToLower(replicaType), increaseCount)
+	if len(nodes) == 1 {
+		url += "&node=" + neturl.QueryEscape(nodes[0])
+	} else if len(nodes) > 1 {
+		url += "&createNodeSet=" + neturl.QueryEscape(strings.Join(nodes, ","))
+	}
+	if policy != "" {
+		url += "&policy=" + neturl.QueryEscape(policy)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, err
+	}
 
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
-		return false, fmt.Errorf("request failed")
+		return false, fmt.Errorf("request failed: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -322,28 +600,33 @@ func (r *SolrClient) AddReplicas(ctx context.Context, collectionName string, inc
 	return isScaling, nil
 }
 
-// RemoveReplicas removes the given number of replicas
-func (r *SolrClient) RemoveReplicas(ctx context.Context, collectionName string, decreaseCount int32) error {
+// RemoveReplica removes the given replica (e.g. core_node3) from the given shard
+func (r *SolrClient) RemoveReplica(ctx context.Context, collectionName string, shardName string, replicaName string) error {
+	return r.removeReplica(ctx, collectionName, shardName, replicaName, false)
+}
+
+// removeReplica removes the given replica from the given shard. If deleteFiles is true then the replica's index, data
+// and instance directories are removed even if the replica is down ...
+func (r *SolrClient) removeReplica(ctx context.Context, collectionName string, shardName string, replicaName string,
+	deleteFiles bool) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
-	// Multiple replicas can be deleted from a specific shard if the associated collection and shard names are provided,
-	// along with a count of the replicas to delete.
-	url := fmt.Sprintf("%s/admin/collections?action=DELETEREPLICA&collection=%s&shard=shard1&count=%d&wt=json",
-		r.Url, collectionName, decreaseCount)
+	url := fmt.Sprintf("%s/admin/collections?action=DELETEREPLICA&collection=%s&shard=%s&replica=%s&wt=json",
+		r.Url, collectionName, shardName, replicaName)
+	if deleteFiles {
+		url += "&deleteIndex=true&deleteDataDir=true&deleteInstanceDir=true&onlyIfDown=false"
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
-
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
@@ -353,30 +636,46 @@ func (r *SolrClient) RemoveReplicas(ctx context.Context, collectionName string,
 
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return fmt.Errorf("remove replicas failed on collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
+		return fmt.Errorf("remove replica [%s] failed on collection [%s] with [%s] [%s]",
+			replicaName, collectionName, resp.Status, msg)
 	}
 
 	return nil
 }
 
-// CreateCollection creates a collection ...
-func (r *SolrClient) CreateCollection(ctx context.Context, collectionName string, configSetName string, replicationFactor int32) error {
+// CreateCollection creates a collection and stamps it with the given owner (unless the owner is empty) ...
+func (r *SolrClient) CreateCollection(ctx context.Context, collectionName string, configSetName string,
+	numShards int32, routerField string, replicationFactor int32, autoAddReplicas bool, placement CollectionPlacement,
+	owner CollectionOwner) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
 
 	// http://localhost:8983/solr/admin/collections?action=CREATE&name=techproducts_v2&collection.configName=techproducts&numShards=1
-	url := fmt.Sprintf("%s/admin/collections?action=CREATE&name=%s&collection.configName=%s&numShards=1&replicationFactor=%d&autoAddReplicas=true&wt=json",
-		r.Url, collectionName, configSetName, replicationFactor)
+	url := fmt.Sprintf("%s/admin/collections?action=CREATE&name=%s&collection.configName=%s&numShards=%d&replicationFactor=%d&autoAddReplicas=%t&wt=json",
+		r.Url, collectionName, configSetName, numShards, replicationFactor, autoAddReplicas)
+	if routerField != "" {
+		url += "&router.field=" + neturl.QueryEscape(routerField)
+	}
+	if placement.WaitForFinalState {
+		url += "&waitForFinalState=true"
+	}
+	if placement.MaxShardsPerNode > 0 {
+		url += fmt.Sprintf("&maxShardsPerNode=%d", placement.MaxShardsPerNode)
+	}
+	if len(placement.CreateNodeSet) > 0 {
+		url += "&createNodeSet=" + neturl.QueryEscape(strings.Join(placement.CreateNodeSet, ","))
+	}
+	if placement.Policy != "" {
+		url += "&policy=" + neturl.QueryEscape(placement.Policy)
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -392,27 +691,89 @@ func (r *SolrClient) CreateCollection(ctx context.Context, collectionName string
 		return fmt.Errorf("create collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
 	}
 
-	return nil
+	if owner.Uid == "" {
+		return nil
+	}
+	return r.SetCollectionOwner(ctx, collectionName, owner)
+}
+
+// CreateCollectionIfMissing creates the collection (unless it already exists) and then waits, for at most the given
+// time, until it has the given number of shards with the given number of replicas each and all of its replicas are
+// active. That way a new collection can be used in the same reconcile instead of waiting for the next one. The
+// verified status of the collection is returned with ready set. If the wait is zero then the collection isn't waited
+// on (i.e. it isn't ready), and if the wait runs out (or the context is done) an error is returned ...
+func (r *SolrClient) CreateCollectionIfMissing(ctx context.Context, collectionName string, configSetName string,
+	numShards int32, routerField string, replicationFactor int32, autoAddReplicas bool, placement CollectionPlacement,
+	owner CollectionOwner, wait time.Duration) (collection Collection, ready bool, err error) {
+
+	clusterStatus, err := r.GetClusterStatusOf(ctx, []string{collectionName})
+	if err != nil {
+		return Collection{}, false, err
+	}
+	if _, exists := clusterStatus.Collections[collectionName]; !exists {
+		err = r.CreateCollection(ctx, collectionName, configSetName, numShards, routerField, replicationFactor,
+			autoAddReplicas, placement, owner)
+		if err != nil {
+			return Collection{}, false, err
+		}
+	}
+	if wait <= 0 {
+		return Collection{}, false, nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	ticker := time.NewTicker(collectionReadyPollInterval)
+	defer ticker.Stop()
+	for {
+		clusterStatus, err = r.GetClusterStatusOf(waitCtx, []string{collectionName})
+		if err == nil {
+			collection, exists := clusterStatus.Collections[collectionName]
+			if exists && collectionIsReady(collection, numShards, replicationFactor) {
+				return collection, true, nil
+			}
+		}
+		select {
+		case <-waitCtx.Done():
+			return Collection{}, false, fmt.Errorf("gave up waiting on collection [%s] to become active with [%d] "+
+				"replicas: %w", collectionName, numShards*replicationFactor, waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectionIsReady checks whether the collection has the given number of shards, each with at least the given number
+// of replicas, and whether all of its replicas are active ...
+func collectionIsReady(collection Collection, numShards int32, replicationFactor int32) bool {
+	if int32(len(collection.Shards)) < numShards || collection.NotActiveReplicaCount() > 0 {
+		return false
+	}
+	replicaType := collection.ManagedReplicaType()
+	for _, shard := range collection.Shards {
+		if shard.ReplicaCountOfType(replicaType) < replicationFactor {
+			return false
+		}
+	}
+	return true
 }
 
-// AssignAlias creates an alias for the given collection ...
-func (r *SolrClient) AssignAlias(ctx context.Context, alias string, collectionName string) error {
+// SetCollectionOwner records the collection set which owns the given collection as collection properties ...
+func (r *SolrClient) SetCollectionOwner(ctx context.Context, collectionName string, owner CollectionOwner) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
-
-	// /admin/collections?action=CREATEALIAS&name=name&collections=collectionlist
-	url := fmt.Sprintf("%s/admin/collections?action=CREATEALIAS&name=%s&collections=%s",
-		r.Url, alias, collectionName)
+	url := fmt.Sprintf("%s/admin/collections?action=MODIFYCOLLECTION&collection=%s&%s=%s&%s=%s&%s=%s&wt=json",
+		r.Url, collectionName,
+		ownerNameProperty, neturl.QueryEscape(owner.Name),
+		ownerNamespaceProperty, neturl.QueryEscape(owner.Namespace),
+		ownerUidProperty, neturl.QueryEscape(owner.Uid))
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -425,34 +786,30 @@ func (r *SolrClient) AssignAlias(ctx context.Context, alias string, collectionNa
 
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return fmt.Errorf("create alias [%s] for collection [%s] failed with [%s] [%s]",
-			alias, collectionName, resp.Status, msg)
+		return fmt.Errorf("set owner failed on collection [%s] with [%s] [%s]", collectionName, resp.Status, msg)
 	}
 
 	return nil
 }
 
-// DeleteAlias removes the given alias ...
-func (r *SolrClient) DeleteAlias(ctx context.Context, alias string) error {
+// SetCollectionProperty sets a property of the given collection. The property is reported back in
+// Collection.Properties by GetClusterStatus() ...
+func (r *SolrClient) SetCollectionProperty(ctx context.Context, collectionName string, name string, value string) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
-
-	// http://localhost:8983/solr/admin/collections?action=DELETEALIAS&name=testalias
-	url := fmt.Sprintf("%s/admin/collections?action=DELETEALIAS&name=%s", r.Url, alias)
+	url := fmt.Sprintf("%s/admin/collections?action=MODIFYCOLLECTION&collection=%s&%s%s=%s&wt=json",
+		r.Url, collectionName, collectionPropertyPrefix, neturl.QueryEscape(name), neturl.QueryEscape(value))
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
-
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
@@ -462,28 +819,32 @@ func (r *SolrClient) DeleteAlias(ctx context.Context, alias string) error {
 
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return fmt.Errorf("remove alias [%s] failed [%s] [%s]", alias, resp.Status, msg)
+		return fmt.Errorf("set property [%s] failed on collection [%s] with [%s] [%s]", name, collectionName,
+			resp.Status, msg)
 	}
 
 	return nil
 }
 
-// ReloadCollection causes a Solr collection to be reloaded
-func (r *SolrClient) ReloadCollection(ctx context.Context, collectionName string) error {
+// AssignAlias creates an alias for the given collections. If the alias already exists it's pointed at the given
+// collections instead ...
+func (r *SolrClient) AssignAlias(ctx context.Context, alias string, collectionNames []string) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
 
-	url := fmt.Sprintf("%s/admin/collections?action=RELOAD&name=%s", r.Url, collectionName)
+	collectionList := strings.Join(collectionNames, ",")
+
+	// /admin/collections?action=CREATEALIAS&name=name&collections=collectionlist
+	url := fmt.Sprintf("%s/admin/collections?action=CREATEALIAS&name=%s&collections=%s",
+		r.Url, alias, collectionList)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -493,30 +854,52 @@ func (r *SolrClient) ReloadCollection(ctx context.Context, collectionName string
 			logger.Error(err, "Solr call failed")
 		}
 	}(resp.Body)
+
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return fmt.Errorf("relocal collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
+		return fmt.Errorf("create alias [%s] for collections [%s] failed with [%s] [%s]",
+			alias, collectionList, resp.Status, msg)
 	}
 
 	return nil
 }
 
-// DeleteCollection deletes the given collection from Solr ...
-func (r *SolrClient) DeleteCollection(ctx context.Context, collectionName string) error {
+// CreateRoutedAlias creates a time or category routed alias. Solr creates the collections behind the alias ...
+func (r *SolrClient) CreateRoutedAlias(ctx context.Context, alias RoutedAlias) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
 
-	url := fmt.Sprintf("%s/admin/collections?action=DELETE&name=%s", r.Url, collectionName)
+	params := neturl.Values{}
+	params.Set("action", "CREATEALIAS")
+	params.Set("name", alias.Name)
+	params.Set("router.name", alias.Router)
+	params.Set("router.field", alias.Field)
+	if alias.Start != "" {
+		params.Set("router.start", alias.Start)
+	}
+	if alias.Interval != "" {
+		params.Set("router.interval", alias.Interval)
+	}
+	if alias.MaxFutureMs != nil {
+		params.Set("router.maxFutureMs", strconv.FormatInt(*alias.MaxFutureMs, 10))
+	}
+	if alias.MaxCardinality != nil {
+		params.Set("router.maxCardinality", strconv.Itoa(int(*alias.MaxCardinality)))
+	}
+	params.Set("create-collection.collection.configName", alias.ConfigSetName)
+	params.Set("create-collection.numShards", "1")
+	params.Set("create-collection.replicationFactor", strconv.Itoa(int(alias.ReplicationFactor)))
+	params.Set("wt", "json")
+
+	url := fmt.Sprintf("%s/admin/collections?%s", r.Url, params.Encode())
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -526,31 +909,32 @@ func (r *SolrClient) DeleteCollection(ctx context.Context, collectionName string
 			logger.Error(err, "Solr call failed")
 		}
 	}(resp.Body)
+
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return fmt.Errorf("delete collection [%s] failed with [%s] [%s]", collectionName, resp.Status, msg)
+		return fmt.Errorf("create %s routed alias [%s] failed with [%s] [%s]", alias.Router, alias.Name, resp.Status, msg)
 	}
 
 	return nil
 }
 
-// Query performs a query against the given collection and returns the results in a list of map[string]interface{}
-func (r *SolrClient) Query(ctx context.Context, collectionName string, query string) ([]map[string]interface{}, error) {
+// DeleteAlias removes the given alias ...
+func (r *SolrClient) DeleteAlias(ctx context.Context, alias string) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
 
-	url := fmt.Sprintf("%s/%s/select?q.op=OR&rows=1000&q=%s", r.Url, collectionName, query)
+	// http://localhost:8983/solr/admin/collections?action=DELETEALIAS&name=testalias
+	url := fmt.Sprintf("%s/admin/collections?action=DELETEALIAS&name=%s", r.Url, alias)
+
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	r.addBasicAuth(req)
-
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer func(Body io.ReadCloser) {
@@ -560,57 +944,29 @@ func (r *SolrClient) Query(ctx context.Context, collectionName string, query str
 		}
 	}(resp.Body)
 
-	// If the response wasn't a 200 then fish out the error ...
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return nil, fmt.Errorf("query to collection [%s] failed with [%s] [%s]", collectionName, resp.Status, msg)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	// Read the response string into a map data structure ....
-	var jsonResponse map[string]interface{}
-	e := json.Unmarshal(body, &jsonResponse)
-	if e != nil {
-		return nil, e
-	}
-
-	var response = jsonResponse["response"]
-	var docs = response.(map[string]interface{})["docs"]
-
-	var docsOut []map[string]interface{} //nolint:prealloc
-	for _, doc := range docs.([]interface{}) {
-		var rec = make(map[string]interface{})
-		for key, value := range doc.(map[string]interface{}) {
-			rec[key] = value
-		}
-		docsOut = append(docsOut, rec)
+		return fmt.Errorf("remove alias [%s] failed [%s] [%s]", alias, resp.Status, msg)
 	}
 
-	return docsOut, nil
+	return nil
 }
 
-// WriteRecord writes a single solr record to the given collection ...
-func (r *SolrClient) WriteRecord(ctx context.Context, collectionName string, record string) error {
+// OptimizeCollection merges the index segments of a Solr collection down to one segment. This rewrites the whole index,
+// so it's slow and expensive for a large collection ...
+func (r *SolrClient) OptimizeCollection(ctx context.Context, collectionName string) error {
 	logger := log.FromContext(ctx)
 
 	client := &http.Client{}
 
-	url := fmt.Sprintf("%s/%s/update?commit=true", r.Url, collectionName)
+	url := fmt.Sprintf("%s/%s/update?optimize=true&waitSearcher=true&wt=json", r.Url, collectionName)
 
-	bodyReader := bytes.NewBuffer([]byte(fmt.Sprintf("[%s]", record)))
-	req, err := http.NewRequest("POST", url, bodyReader)
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	r.addBasicAuth(req)
-
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
+	resp, err := r.doRequest(ctx, client, req)
 	if err != nil {
 		return err
 	}
@@ -620,54 +976,935 @@ func (r *SolrClient) WriteRecord(ctx context.Context, collectionName string, rec
 			logger.Error(err, "Solr call failed")
 		}
 	}(resp.Body)
-
-	// If the response isn't 200 then parse the response for the error message ...
 	if resp.StatusCode != 200 {
 		msg, _ := parseError(resp.Body)
-		return fmt.Errorf("write to collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
+		return fmt.Errorf("optimize of collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
 	}
 
 	return nil
 }
 
-// countReplicas counts replicas in a collection json object ...
-func countReplicas(collection interface{}) (count int32) {
-	var shards = collection.(map[string]interface{})["shards"]
-	var shard1 = shards.(map[string]interface{})["shard1"]
-	var replicas = shard1.(map[string]interface{})["replicas"]
-	for range replicas.(map[string]interface{}) {
-		count++
+// GetConfigOverlay gets the properties in the config overlay of a collection (i.e. the properties set via the Config
+// API), keyed by their dotted names (e.g. query.maxBooleanClauses) ...
+func (r *SolrClient) GetConfigOverlay(ctx context.Context, collectionName string) (map[string]string, error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/%s/config/overlay?wt=json", r.Url, collectionName)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
-	return count
-}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return nil, fmt.Errorf("could not get the config overlay of collection %s [%s] [%s]", collectionName,
+			resp.Status, msg)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var jsonResponse struct {
+		Overlay struct {
+			Props map[string]interface{} `json:"props"`
+		} `json:"overlay"`
+	}
+	err = json.Unmarshal(body, &jsonResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	// The properties are nested by the parts of their names, so flatten them ...
+	properties := make(map[string]string)
+	flattenProperties("", jsonResponse.Overlay.Props, properties)
+	return properties, nil
+}
+
+// SetConfigProperties sets (and unsets) properties in the config overlay of a collection via the Config API. Solr
+// reloads the collection once the overlay has changed ...
+func (r *SolrClient) SetConfigProperties(ctx context.Context, collectionName string, set map[string]string,
+	unset []string) error {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/%s/config?wt=json", r.Url, collectionName)
+
+	commands := make(map[string]interface{})
+	if len(set) > 0 {
+		commands["set-property"] = set
+	}
+	if len(unset) > 0 {
+		commands["unset-property"] = unset
+	}
+	body, err := json.Marshal(commands)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("could not set the config overlay of collection %s [%s] [%s]", collectionName,
+			resp.Status, msg)
+	}
+
+	return nil
+}
+
+// ReloadCollection causes a Solr collection to be reloaded
+func (r *SolrClient) ReloadCollection(ctx context.Context, collectionName string) error {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/admin/collections?action=RELOAD&name=%s", r.Url, collectionName)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("relocal collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes the given collection from Solr. Solr doesn't have a forced collection delete, so if force is
+// true then the replicas of the collection (and their files) are removed one at a time before the collection is
+// deleted. That gets rid of leftover cores which can otherwise make the delete fail ...
+func (r *SolrClient) DeleteCollection(ctx context.Context, collectionName string, force bool) error {
+	logger := log.FromContext(ctx)
+
+	if force {
+		clusterStatus, found, err := r.getClusterStatus(ctx, collectionName)
+		if err != nil {
+			return err
+		}
+		if found {
+			for _, replica := range clusterStatus.Collections[collectionName].Replicas {
+				// Keep going regardless since the collection delete is what matters ...
+				err := r.removeReplica(ctx, collectionName, replica.Shard, replica.Name, true)
+				if err != nil {
+					logger.Error(err, fmt.Sprintf("could not remove replica [%s] of collection [%s] before force delete",
+						replica.Name, collectionName))
+				}
+			}
+		}
+	}
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/admin/collections?action=DELETE&name=%s", r.Url, collectionName)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("delete collection [%s] failed with [%s] [%s]", collectionName, resp.Status, msg)
+	}
+
+	return nil
+}
+
+// Query performs a query against the given collection and returns the results in a list of map[string]interface{}.
+// If leaderOnly is true the query goes (non-distributed) to the leader core of the collection's shard, which avoids
+// reading stale data right after a write. That only makes sense for single shard collections.
+func (r *SolrClient) Query(ctx context.Context, collectionName string, query string,
+	leaderOnly bool) ([]map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/%s/select?q.op=OR&rows=1000&q=%s", r.Url, collectionName, neturl.QueryEscape(query))
+	if leaderOnly {
+		leaderUrl, err := r.leaderCoreUrl(ctx, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		url = fmt.Sprintf("%s/select?q.op=OR&rows=1000&distrib=false&q=%s", leaderUrl, neturl.QueryEscape(query))
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	// If the response wasn't a 200 then fish out the error ...
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return nil, fmt.Errorf("query to collection [%s] failed with [%s] [%s]", collectionName, resp.Status, msg)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the response string into a map data structure ....
+	var jsonResponse map[string]interface{}
+	e := json.Unmarshal(body, &jsonResponse)
+	if e != nil {
+		return nil, e
+	}
+
+	response, ok := jsonResponse["response"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("query to collection [%s] returned no response", collectionName)
+	}
+	docs, ok := response["docs"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("query to collection [%s] returned no docs", collectionName)
+	}
+
+	var docsOut []map[string]interface{} //nolint:prealloc
+	for _, doc := range docs {
+		fields, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("query to collection [%s] returned a malformed doc", collectionName)
+		}
+		var rec = make(map[string]interface{})
+		for key, value := range fields {
+			rec[key] = value
+		}
+		docsOut = append(docsOut, rec)
+	}
+
+	return docsOut, nil
+}
+
+// Count counts the records in the given collection which match the given query ...
+func (r *SolrClient) Count(ctx context.Context, collectionName string, query string) (int64, error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/%s/select?rows=0&q=%s", r.Url, collectionName, neturl.QueryEscape(query))
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return 0, fmt.Errorf("count of collection [%s] failed with [%s] [%s]", collectionName, resp.Status, msg)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var jsonResponse map[string]interface{}
+	err = json.Unmarshal(body, &jsonResponse)
+	if err != nil {
+		return 0, err
+	}
+	response, ok := jsonResponse["response"].(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("count of collection [%s] returned no response", collectionName)
+	}
+
+	return interfaceToInt64(response["numFound"]), nil
+}
+
+// GetCollectionStatus fetches index statistics (document count and size) of each shard of the given collection using
+// COLSTATUS. The response is large and varies between Solr versions, so anything missing is reported as unknown (-1)
+// rather than failing ...
+func (r *SolrClient) GetCollectionStatus(ctx context.Context, collectionName string) (CollectionStatus, error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	// Segment info is what has the document counts and sizes. Field info would make the response much larger ...
+	url := fmt.Sprintf("%s/admin/collections?action=COLSTATUS&collection=%s&coreInfo=true&segments=true&wt=json",
+		r.Url, collectionName)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return CollectionStatus{}, err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return CollectionStatus{}, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return CollectionStatus{}, fmt.Errorf("collection status of [%s] failed with [%s] [%s]", collectionName,
+			resp.Status, msg)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CollectionStatus{}, err
+	}
+
+	var jsonResponse map[string]interface{}
+	err = json.Unmarshal(body, &jsonResponse)
+	if err != nil {
+		return CollectionStatus{}, err
+	}
+	jsonCollection, ok := jsonResponse[collectionName].(map[string]interface{})
+	if !ok {
+		return CollectionStatus{}, fmt.Errorf("collection status of [%s] returned no collection", collectionName)
+	}
+
+	collectionStatus := CollectionStatus{Name: collectionName, Shards: make(map[string]ShardStatus)}
+	jsonShards, _ := jsonCollection["shards"].(map[string]interface{})
+	for shardName, value := range jsonShards {
+		jsonShard, _ := value.(map[string]interface{})
+		// Shards that have been split are inactive and aren't counted ...
+		if state, _ := jsonShard["state"].(string); state != "" && state != "active" {
+			continue
+		}
+		collectionStatus.Shards[shardName] = shardStatus(shardName, jsonShard)
+	}
+	return collectionStatus, nil
+}
+
+// shardStatus reads the document count and index size of the leader of a shard from a COLSTATUS shard json object.
+// The counts are summed from the segments if they're there, otherwise they fall back to the (less exact) totals ...
+func shardStatus(shardName string, jsonShard map[string]interface{}) ShardStatus {
+	status := ShardStatus{Name: shardName, DocCount: -1, SizeBytes: -1}
+	leader, _ := jsonShard["leader"].(map[string]interface{})
+	segInfos, _ := leader["segInfos"].(map[string]interface{})
+	if segInfos == nil {
+		return status
+	}
+	if segments, ok := segInfos["segments"].(map[string]interface{}); ok {
+		var docCount, sizeBytes int64
+		hasSizes := true
+		for _, value := range segments {
+			segment, _ := value.(map[string]interface{})
+			docCount += interfaceToInt64(segment["size"]) - interfaceToInt64(segment["delCount"])
+			if _, exists := segment["sizeInBytes"]; !exists {
+				hasSizes = false
+			}
+			sizeBytes += interfaceToInt64(segment["sizeInBytes"])
+		}
+		status.DocCount = docCount
+		if hasSizes {
+			status.SizeBytes = sizeBytes
+		}
+	} else if info, ok := segInfos["info"].(map[string]interface{}); ok {
+		// totalMaxDoc includes deleted documents, but it's better than nothing ...
+		if _, exists := info["totalMaxDoc"]; exists {
+			status.DocCount = interfaceToInt64(info["totalMaxDoc"])
+		}
+	}
+	if status.SizeBytes < 0 {
+		if core, ok := segInfos["core"].(map[string]interface{}); ok {
+			if sizeInGB, ok := core["sizeInGB"].(float64); ok {
+				status.SizeBytes = int64(sizeInGB * (1 << 30))
+			}
+		}
+	}
+	return status
+}
+
+// WriteRecord writes a single solr record to the given collection. If leaderOnly is true the record is sent straight
+// to the leader core of the collection's shard rather than to any replica ...
+func (r *SolrClient) WriteRecord(ctx context.Context, collectionName string, record string, leaderOnly bool) error {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/%s/update?%s", r.Url, collectionName, r.commitParam())
+	if leaderOnly {
+		leaderUrl, err := r.leaderCoreUrl(ctx, collectionName)
+		if err != nil {
+			return err
+		}
+		url = fmt.Sprintf("%s/update?%s", leaderUrl, r.commitParam())
+	}
+
+	bodyReader := bytes.NewBuffer([]byte(fmt.Sprintf("[%s]", record)))
+	req, err := http.NewRequest("POST", url, bodyReader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	// If the response isn't 200 then parse the response for the error message ...
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("write to collection %s failed with [%s] [%s]", collectionName, resp.Status, msg)
+	}
+
+	return nil
+}
+
+// leaderCoreUrl finds the URL of the core of the leader replica of the given collection. If the collection has more
+// than one shard then the leader of the first shard (by name) is used ...
+func (r *SolrClient) leaderCoreUrl(ctx context.Context, collectionName string) (string, error) {
+	clusterStatus, err := r.GetClusterStatusOf(ctx, []string{collectionName})
+	if err != nil {
+		return "", err
+	}
+	collection, exists := clusterStatus.Collections[collectionName]
+	if !exists {
+		return "", fmt.Errorf("could not find collection [%s] to look up its leader", collectionName)
+	}
+	for _, replica := range collection.Replicas {
+		if replica.Leader && replica.BaseUrl != "" && replica.Core != "" {
+			return fmt.Sprintf("%s/%s", strings.TrimSuffix(replica.BaseUrl, "/"), replica.Core), nil
+		}
+	}
+	return "", fmt.Errorf("could not find the leader of collection [%s]", collectionName)
+}
+
+// SplitShard submits a split of the given shard of the given collection into two new shards. The split is submitted as
+// an async request with the given request id, which its progress can be checked with (see GetRequestStatus()) ...
+func (r *SolrClient) SplitShard(ctx context.Context, collectionName string, shardName string, requestId string) error {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	// /admin/collections?action=SPLITSHARD&collection=name&shard=shardID&async=requestId
+	url := fmt.Sprintf("%s/admin/collections?action=SPLITSHARD&collection=%s&shard=%s&async=%s&wt=json",
+		r.Url, collectionName, shardName, requestId)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return fmt.Errorf("split shard [%s] of collection [%s] failed with [%s] [%s]",
+			shardName, collectionName, resp.Status, msg)
+	}
+	return nil
+}
+
+// GetRequestStatus gets the state (e.g. running, completed, failed) of the async request with the given id ...
+func (r *SolrClient) GetRequestStatus(ctx context.Context, requestId string) (string, error) {
+	logger := log.FromContext(ctx)
+
+	client := &http.Client{}
+
+	url := fmt.Sprintf("%s/admin/collections?action=REQUESTSTATUS&requestid=%s&wt=json", r.Url, requestId)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.doRequest(ctx, client, req)
+	if err != nil {
+		return "", err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error(err, "Solr call failed")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != 200 {
+		msg, _ := parseError(resp.Body)
+		return "", fmt.Errorf("get status of request [%s] failed with [%s] [%s]", requestId, resp.Status, msg)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var jsonResponse map[string]interface{}
+	err = json.Unmarshal(body, &jsonResponse)
+	if err != nil {
+		return "", err
+	}
+
+	status, _ := jsonResponse["status"].(map[string]interface{})
+	state, _ := status["state"].(string)
+	if state == "" {
+		return "", fmt.Errorf("no state found in the status of request [%s]", requestId)
+	}
+	return state, nil
+}
+
+// commitParam is the update query parameter that implements the client's commit strategy ...
+func (r *SolrClient) commitParam() string {
+	switch r.CommitStrategy {
+	case CommitStrategySoft:
+		return "softCommit=true"
+	case CommitStrategyWithin:
+		return fmt.Sprintf("commitWithin=%d", r.CommitWithinMillis)
+	}
+	return "commit=true"
+}
+
+// countReplicas counts replicas in the active shards of a collection json object. The replica counts (and replicas) of
+// each active shard are also returned. Shards that have been split are inactive and aren't counted ...
+func countReplicas(collection interface{}) (count int32, shards map[string]Shard) {
+	shards = make(map[string]Shard)
+	var jsonShards = collection.(map[string]interface{})["shards"]
+	for shardName, value := range jsonShards.(map[string]interface{}) {
+		var jsonShard = value.(map[string]interface{})
+		// Shards without a state are treated as active ...
+		state, _ := jsonShard["state"].(string)
+		if state != "" && state != "active" {
+			continue
+		}
+		shard := Shard{Name: shardName, State: state}
+		replicas, _ := jsonShard["replicas"].(map[string]interface{})
+		for replicaName, value := range replicas {
+			shard.ReplicaCount++
+			var jsonReplica = value.(map[string]interface{})
+			// Replicas without a type are NRT replicas ...
+			replicaType, _ := jsonReplica["type"].(string)
+			switch replicaType {
+			case ReplicaTypeTLOG:
+				shard.TlogReplicaCount++
+			case ReplicaTypePULL:
+				shard.PullReplicaCount++
+			default:
+				replicaType = ReplicaTypeNRT
+				shard.NrtReplicaCount++
+			}
+			isLeader := interfaceToBoolPtr(jsonReplica["leader"])
+			shard.Replicas = append(shard.Replicas, Replica{
+				Name:    replicaName,
+				Core:    interfaceToString(jsonReplica["core"]),
+				Node:    interfaceToString(jsonReplica["node_name"]),
+				BaseUrl: interfaceToString(jsonReplica["base_url"]),
+				Shard:   shardName,
+				Type:    replicaType,
+				State:   interfaceToString(jsonReplica["state"]),
+				Leader:  isLeader != nil && *isLeader,
+			})
+		}
+		// Sort the replicas so that the order is stable from one call to the next ...
+		sort.Slice(shard.Replicas, func(i, j int) bool {
+			return shard.Replicas[i].Name < shard.Replicas[j].Name
+		})
+		count += shard.ReplicaCount
+		shards[shardName] = shard
+	}
+	return count, shards
+}
+
+// leastReplicatedCounts finds the replica count of each type in the least replicated of the given shards ...
+func leastReplicatedCounts(shards map[string]Shard) map[string]int32 {
+	typeCounts := make(map[string]int32)
+	first := true
+	for _, shard := range shards {
+		for _, replicaType := range []string{ReplicaTypeNRT, ReplicaTypeTLOG, ReplicaTypePULL} {
+			count := shard.ReplicaCountOfType(replicaType)
+			if first || count < typeCounts[replicaType] {
+				typeCounts[replicaType] = count
+			}
+		}
+		first = false
+	}
+	return typeCounts
+}
+
+// parseAliasCollections turns the collections an alias targets into a list. Solr returns multi-collection aliases as
+// a comma-separated string, but a JSON list is handled as well ...
+func parseAliasCollections(value interface{}) []string {
+	var collections []string
+	switch v := value.(type) {
+	case string:
+		for _, collection := range strings.Split(v, ",") {
+			collection = strings.TrimSpace(collection)
+			if collection != "" {
+				collections = append(collections, collection)
+			}
+		}
+	case []interface{}:
+		for _, collection := range v {
+			if name, ok := collection.(string); ok {
+				collections = append(collections, name)
+			}
+		}
+	}
+	return collections
+}
 
 // parseError fishes the error message out of an error response ...
 func parseError(reader io.Reader) (string, error) {
+	msg, _, err := parseErrorDetail(reader)
+	return msg, err
+}
+
+// maxRawErrorLength is how much of a response body that can't be parsed is included in an error message ...
+const maxRawErrorLength = 1000
+
+// parseErrorDetail fishes the error message and any error details (e.g. which file of a config set was rejected) out
+// of a Solr error response. If the response isn't shaped like a Solr error then the (trimmed) raw body is used as the
+// message instead ...
+func parseErrorDetail(reader io.Reader) (msg string, details string, err error) {
 	body, err := io.ReadAll(reader)
 	if err != nil {
-		return "failed to read", err
+		return "failed to read", "", err
+	}
+	raw := strings.TrimSpace(string(body))
+	if len(raw) > maxRawErrorLength {
+		raw = raw[:maxRawErrorLength] + "..."
 	}
+
 	var jsonResponse map[string]interface{}
 	err = json.Unmarshal(body, &jsonResponse)
 	if err != nil {
-		return "couldn't unmarshall the response body", err
+		return raw, "", err
+	}
+	jsonError, ok := jsonResponse["error"].(map[string]interface{})
+	if !ok {
+		return raw, "", fmt.Errorf("response has no error")
+	}
+	msg, ok = jsonError["msg"].(string)
+	if !ok {
+		msg = raw
+	}
+	if jsonDetails, exists := jsonError["details"]; exists {
+		if encoded, err := json.Marshal(jsonDetails); err == nil {
+			details = string(encoded)
+		}
+	}
+	return msg, details, nil
+}
+
+// doRequest performs the given request once the rate limiter allows it. Waiting on the rate limiter blocks until a
+// token is available or the context is cancelled ...
+func (r *SolrClient) doRequest(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	logger := log.FromContext(ctx)
+
+	if r.RateLimiter != nil {
+		if err := r.RateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("waiting on the Solr API rate limiter failed: %w", err)
+		}
+	}
+
+	if err := r.addAuth(ctx, req); err != nil {
+		return nil, err
+	}
+	r.addHeaders(req)
+	if client.Transport == nil {
+		client.Transport = r.Transport
 	}
 
-	e := jsonResponse["error"]
-	msg := e.(map[string]interface{})["msg"]
-	return msg.(string), nil
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		logger.V(1).Info("solr request failed", "method", req.Method, "url", sanitizeUrl(req.URL),
+			"headers", sanitizeHeaders(r.Headers), "error", err.Error())
+		// Callers only defer closing the body once the error has been checked, so make sure there's never a response
+		// to close when there's an error (e.g. a failed redirect) ...
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		r.reportMutation(ctx, req, 0, err)
+		return nil, err
+	}
+	logger.V(1).Info("solr request", "method", req.Method, "url", sanitizeUrl(req.URL),
+		"headers", sanitizeHeaders(r.Headers), "status", resp.Status)
+	r.reportMutation(ctx, req, resp.StatusCode, nil)
+
+	// Credentials can expire or be revoked part way through a reconcile, so auth failures are turned into typed errors
+	// here rather than left to each caller to report as a generic failure ...
+	if err := authError(req, resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
 }
 
-// addBasicAuth Add basic auth to the given request ...
-func (r *SolrClient) addBasicAuth(req *http.Request) {
-	username := r.Username
-	password := r.Password
-	req.SetBasicAuth(username, password)
+// authError returns ErrAuthFailed or ErrAuthInsufficient (wrapped with the details of the request) if the response is
+// a 401 or a 403, and otherwise nil ...
+func authError(req *http.Request, resp *http.Response) error {
+	var err error
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		err = ErrAuthFailed
+	case http.StatusForbidden:
+		err = ErrAuthInsufficient
+	default:
+		return nil
+	}
+	msg, _ := parseError(resp.Body)
+	return fmt.Errorf("%w: %s %s responded with [%s] [%s]", err, req.Method, req.URL.Path, resp.Status, msg)
+}
+
+// readOnlyActions are the Solr API actions which don't change anything ...
+var readOnlyActions = []string{"CLUSTERSTATUS", "COLSTATUS", "LIST", "DOWNLOAD", "REQUESTSTATUS"}
+
+// reportMutation calls OnMutation if the given request changes Solr ...
+func (r *SolrClient) reportMutation(ctx context.Context, req *http.Request, statusCode int, err error) {
+	if r.OnMutation == nil {
+		return
+	}
+	query := req.URL.Query()
+	mutation := Mutation{
+		Action:     query.Get("action"),
+		Url:        sanitizeUrl(req.URL),
+		StatusCode: statusCode,
+		Err:        err,
+	}
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/update"):
+		// Records are written to the collection in the path i.e. <url>/<collection>/update ...
+		mutation.Api = "update"
+		mutation.Action = "UPDATE"
+		mutation.Target = path.Base(path.Dir(req.URL.Path))
+	case strings.HasSuffix(req.URL.Path, "/config") && req.Method == http.MethodPost:
+		// Config API edits are made to the collection in the path i.e. <url>/<collection>/config ...
+		mutation.Api = "config"
+		mutation.Action = "CONFIG"
+		mutation.Target = path.Base(path.Dir(req.URL.Path))
+	case strings.HasSuffix(req.URL.Path, "/admin/configs"):
+		mutation.Api = "configs"
+		mutation.Target = query.Get("name")
+	case strings.HasSuffix(req.URL.Path, "/admin/collections"):
+		mutation.Api = "collections"
+		mutation.Target = query.Get("collection")
+		if mutation.Target == "" {
+			mutation.Target = query.Get("name")
+		}
+	default:
+		// Queries and anything else are read-only ...
+		return
+	}
+	if mutation.Action == "" || slices.Contains(readOnlyActions, mutation.Action) {
+		return
+	}
+	r.OnMutation(ctx, mutation)
+}
+
+// sensitiveQueryParams are query parameters whose values are redacted when a URL is logged ...
+var sensitiveQueryParams = []string{"password", "passwd", "token", "access_token", "secret", "key"}
+
+// sanitizeUrl makes a URL safe to log by stripping credentials and redacting sensitive query parameters ...
+func sanitizeUrl(u *neturl.URL) string {
+	sanitized := *u
+	sanitized.User = nil
+	query := sanitized.Query()
+	redacted := false
+	for param := range query {
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(param, sensitive) {
+				query.Set(param, "REDACTED")
+				redacted = true
+			}
+		}
+	}
+	if redacted {
+		sanitized.RawQuery = query.Encode()
+	}
+	return sanitized.String()
+}
+
+// sensitiveHeaderWords are words which, when found in a header name, cause the header value to be redacted when logged
+var sensitiveHeaderWords = []string{"auth", "key", "token", "secret", "password", "cookie"}
+
+// sanitizeHeaders makes the configured headers safe to log by redacting the values of sensitive headers ...
+func sanitizeHeaders(headers map[string]string) map[string]string {
+	sanitized := make(map[string]string, len(headers))
+	for name, value := range headers {
+		sanitized[name] = value
+		for _, sensitive := range sensitiveHeaderWords {
+			if strings.Contains(strings.ToLower(name), sensitive) {
+				sanitized[name] = "REDACTED"
+				break
+			}
+		}
+	}
+	return sanitized
+}
+
+// addHeaders Add the configured headers to the given request ...
+func (r *SolrClient) addHeaders(req *http.Request) {
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// addAuth authenticates the given request according to the auth mode ...
+func (r *SolrClient) addAuth(ctx context.Context, req *http.Request) error {
+	username, password, token, err := r.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case r.AuthMode == AuthModeBearer && token == "":
+		return fmt.Errorf("no bearer token to authenticate with")
+	case r.AuthMode != AuthModeBasic && token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		req.SetBasicAuth(username, password)
+	}
+	return nil
+}
+
+// credentials returns the username, password and bearer token to authenticate with, reading them from the credentials
+// path if there is one ...
+func (r *SolrClient) credentials(ctx context.Context) (username string, password string, token string, err error) {
+	if r.CredentialsPath == "" {
+		token = r.BearerToken
+		if r.TokenSource != nil && r.AuthMode != AuthModeBasic {
+			token, err = r.TokenSource(ctx)
+			if err != nil {
+				return "", "", "", fmt.Errorf("could not get a bearer token: %w", err)
+			}
+		}
+		return r.Username, r.Password, token, nil
+	}
+	info, err := os.Stat(r.CredentialsPath)
+	if err != nil {
+		return "", "", "", fmt.Errorf("could not read the Solr credentials: %w", err)
+	}
+	if !info.IsDir() {
+		token, err = readCredential(r.CredentialsPath)
+		return "", "", token, err
+	}
+	for name, credential := range map[string]*string{"username": &username, "password": &password, "token": &token} {
+		*credential, err = readCredential(filepath.Join(r.CredentialsPath, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", "", "", err
+		}
+	}
+	return username, password, token, nil
+}
+
+// readCredential reads a credential from the given file, ignoring leading and trailing whitespace ...
+func readCredential(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read the Solr credentials: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// flattenProperties flattens the given nested properties into the given map, keyed by their dotted names ...
+func flattenProperties(prefix string, nested map[string]interface{}, properties map[string]string) {
+	for key, value := range nested {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if children, isNested := value.(map[string]interface{}); isNested {
+			flattenProperties(key, children, properties)
+			continue
+		}
+		properties[key] = fmt.Sprint(value)
+	}
 }
 
 // interfaceToInt32 Deals with turning JSON numbers into int32s ...
 func interfaceToInt32(i interface{}) int32 {
 	var result int32 = 0
+	if i == nil {
+		return result
+	}
 	switch reflect.TypeOf(i).Kind().String() {
 	case "int32":
 		result = i.(int32)
@@ -680,3 +1917,54 @@ func interfaceToInt32(i interface{}) int32 {
 	}
 	return result
 }
+
+// interfaceToInt64 Deals with turning JSON numbers into int64s. Returns 0 if the value is missing or unparseable ...
+func interfaceToInt64(i interface{}) int64 {
+	switch v := i.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case string:
+		result, _ := strconv.ParseInt(v, 10, 64)
+		return result
+	}
+	return 0
+}
+
+// collectionProperties picks the collection properties out of the given collection JSON ...
+func collectionProperties(jsonCollection map[string]interface{}) map[string]string {
+	properties := make(map[string]string)
+	for key, value := range jsonCollection {
+		if name, found := strings.CutPrefix(key, collectionPropertyPrefix); found {
+			properties[name] = interfaceToString(value)
+		}
+	}
+	return properties
+}
+
+// interfaceToString Deals with turning optional JSON strings into strings. Returns "" if the value is missing ...
+func interfaceToString(i interface{}) string {
+	if v, ok := i.(string); ok {
+		return v
+	}
+	return ""
+}
+
+// interfaceToBoolPtr Deals with turning JSON booleans (which Solr sometimes returns as strings) into bools. Returns
+// nil if the value is missing ...
+func interfaceToBoolPtr(i interface{}) *bool {
+	switch v := i.(type) {
+	case bool:
+		return &v
+	case string:
+		result, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil
+		}
+		return &result
+	}
+	return nil
+}
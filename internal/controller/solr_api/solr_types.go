@@ -1,9 +1,91 @@
 package solr_api
 
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Solr replica types ...
+const (
+	ReplicaTypeNRT  = "NRT"
+	ReplicaTypeTLOG = "TLOG"
+	ReplicaTypePULL = "PULL"
+)
+
+// ReplicaStateActive is the state of a replica which is up and serving requests. Other states include down,
+// recovering, and recovery_failed ...
+const ReplicaStateActive = "active"
+
+// Commit strategies for writing records ...
+const (
+	// CommitStrategyHard performs a hard commit on every write
+	CommitStrategyHard = "commit"
+	// CommitStrategySoft performs a soft commit on every write
+	CommitStrategySoft = "softCommit"
+	// CommitStrategyWithin lets Solr commit within a given number of milliseconds of the write
+	CommitStrategyWithin = "commitWithin"
+)
+
+// Authentication modes ...
+const (
+	// AuthModeBasic always authenticates with basic auth
+	AuthModeBasic = "basic"
+	// AuthModeBearer always authenticates with a bearer token (e.g. a JWT for Solr's JWTAuthPlugin)
+	AuthModeBearer = "bearer"
+)
+
+// CollectionPlacement controls where the replicas of a new collection go (see SolrClient.CreateCollection()) ...
+type CollectionPlacement struct {
+	// WaitForFinalState makes the create wait until all of the replicas are active
+	WaitForFinalState bool
+	// MaxShardsPerNode is the most replicas of the collection that can go on one node. Zero means no limit.
+	MaxShardsPerNode int32
+	// CreateNodeSet is the nodes the replicas are placed on. If empty any node can be used.
+	CreateNodeSet []string
+	// Policy is the name of the placement policy the replicas follow. If empty the cluster's default placement is used.
+	Policy string
+}
+
+// Errors returned by every SolrClient method (wrapped, so check them with errors.Is()) when Solr responds with a 401 or
+// a 403 ...
+var (
+	// ErrAuthFailed means Solr didn't accept the credentials
+	ErrAuthFailed = errors.New("solr rejected the credentials")
+	// ErrAuthInsufficient means Solr accepted the credentials but they don't have permission for the API called
+	ErrAuthInsufficient = errors.New("the credentials don't have permission for the solr api")
+)
+
+// States of Solr async requests ...
+const (
+	AsyncStateSubmitted = "submitted"
+	AsyncStateRunning   = "running"
+	AsyncStateCompleted = "completed"
+	AsyncStateFailed    = "failed"
+	AsyncStateNotFound  = "notfound"
+)
+
+// Collection properties used to record the collection set which owns a collection ...
+const (
+	ownerNameProperty      = "property.collectionSetName"
+	ownerNamespaceProperty = "property.collectionSetNamespace"
+	ownerUidProperty       = "property.collectionSetUid"
+)
+
+// collectionPropertyPrefix is the prefix of the collection properties set with MODIFYCOLLECTION ...
+const collectionPropertyPrefix = "property."
+
+// collectionReadyPollInterval is how often a new collection is checked while waiting for it to become active ...
+const collectionReadyPollInterval = time.Second
+
 // ClusterStatus is a data structure for holding the status of a Solr cluster
 type ClusterStatus struct {
 	Collections map[string]Collection
-	Aliases     map[string]string
+	// Aliases maps each alias to the collections it targets
+	Aliases map[string][]string
+	// LiveNodes are the names of the nodes that are live in the cluster. It's nil if it isn't known (e.g. none of the
+	// collections the cluster status was scoped to exist)
+	LiveNodes []string
 }
 
 // Collection is a data structure for holding the status of a particular collection.
@@ -16,4 +98,225 @@ type Collection struct {
 	ReplicaCount int32
 	// The name of the configuration used to create the collection
 	ConfigName string
+	// The document router of the collection (e.g. compositeId) and the field it routes documents on (empty if it routes
+	// on the document id)
+	RouterName  string
+	RouterField string
+	// When the collection was created in milliseconds since the epoch (0 if Solr didn't report it)
+	CreationTimeMillis int64
+	// Whether Solr automatically adds replicas to replace lost replicas (nil if Solr didn't report it)
+	AutoAddReplicas *bool
+	// The target number of replicas of each type
+	NrtReplicas  int32
+	TlogReplicas int32
+	PullReplicas int32
+	// The number of replicas of each type currently instantiated in the least replicated active shard
+	NrtReplicaCount  int32
+	TlogReplicaCount int32
+	PullReplicaCount int32
+	// The active shards of the collection mapped by shard name (e.g. shard1 or shard1_0 after a split)
+	Shards map[string]Shard
+	// The replicas in the active shards of the collection
+	Replicas []Replica
+	// The collection set which owns the collection (empty if the collection wasn't stamped with an owner)
+	Owner CollectionOwner
+	// The collection properties (set with SetCollectionProperty()) mapped by name, without the "property." prefix
+	Properties map[string]string
+}
+
+// Routers of routed aliases ...
+const (
+	RouterTime     = "time"
+	RouterCategory = "category"
+)
+
+// RoutedAlias holds the parameters used to create a routed alias.
+type RoutedAlias struct {
+	// The name of the alias
+	Name string
+	// The type of router (RouterTime or RouterCategory)
+	Router string
+	// The field used to route documents
+	Field string
+	// The start of the first collection (time routers only)
+	Start string
+	// The interval covered by each collection (time routers only)
+	Interval string
+	// How far into the future documents are accepted (time routers only, ignored if nil)
+	MaxFutureMs *int64
+	// The maximum number of categories (category routers only, ignored if nil)
+	MaxCardinality *int32
+	// The config set of the collections created by the alias
+	ConfigSetName string
+	// The replication factor of the collections created by the alias
+	ReplicationFactor int32
+}
+
+// CollectionOwner identifies the collection set which owns (i.e. created) a collection.
+type CollectionOwner struct {
+	Name      string
+	Namespace string
+	Uid       string
+}
+
+// HasOwner returns true if the collection has been stamped with an owner ...
+func (c Collection) HasOwner() bool {
+	return c.Owner.Uid != ""
+}
+
+// Shard is a data structure for holding the status of a shard of a collection.
+type Shard struct {
+	// The name of the shard
+	Name string
+	// The state of the shard (e.g. active, or inactive once it has been split)
+	State string
+	// The number of replicas currently instantiated in the shard
+	ReplicaCount int32
+	// The number of replicas of each type currently instantiated in the shard
+	NrtReplicaCount  int32
+	TlogReplicaCount int32
+	PullReplicaCount int32
+	// The replicas of the shard
+	Replicas []Replica
+}
+
+// Replica is a data structure for holding the status of a replica of a shard.
+type Replica struct {
+	// The name of the replica (e.g. core_node3)
+	Name string
+	// The name of the core backing the replica
+	Core string
+	// The name of the Solr node the replica lives on
+	Node string
+	// The base URL of the Solr node the replica lives on (e.g. http://solr-0:8983/solr)
+	BaseUrl string
+	// The name of the shard the replica belongs to
+	Shard string
+	// The type of the replica (NRT, TLOG, or PULL)
+	Type string
+	// The state of the replica (e.g. active, down, recovering)
+	State string
+	// Whether the replica is the leader of its shard
+	Leader bool
+}
+
+// ReplicaCountOfType is the number of instantiated replicas of the given type in the shard ...
+func (s Shard) ReplicaCountOfType(replicaType string) int32 {
+	switch replicaType {
+	case ReplicaTypeTLOG:
+		return s.TlogReplicaCount
+	case ReplicaTypePULL:
+		return s.PullReplicaCount
+	}
+	return s.NrtReplicaCount
+}
+
+// LiveReplicaCountOfType returns the number of replicas of the given type that are on one of the given live nodes.
+// Replicas on a node that has died stay in the cluster state (as down) until the node comes back, so they can't be
+// relied on. If the live nodes aren't known (i.e. nil) then every replica of the type is counted ...
+func (s Shard) LiveReplicaCountOfType(replicaType string, liveNodes []string) int32 {
+	if liveNodes == nil {
+		return s.ReplicaCountOfType(replicaType)
+	}
+	var count int32
+	for _, replica := range s.Replicas {
+		if replica.Type == replicaType && slices.Contains(liveNodes, replica.Node) {
+			count++
+		}
+	}
+	return count
+}
+
+// ManagedReplicaType is the type of replica that the replication factor applies to. That's NRT unless the collection
+// was created with TLOG replicas only. PULL replicas are never counted against the replication factor.
+func (c Collection) ManagedReplicaType() string {
+	if c.NrtReplicas == 0 && c.TlogReplicas > 0 {
+		return ReplicaTypeTLOG
+	}
+	return ReplicaTypeNRT
+}
+
+// NotActiveReplicaCount is the number of replicas in the active shards which aren't active (e.g. down or recovering).
+// Replicas without a state are treated as active ...
+func (c Collection) NotActiveReplicaCount() int32 {
+	var count int32
+	for _, replica := range c.Replicas {
+		if replica.State != "" && replica.State != ReplicaStateActive {
+			count++
+		}
+	}
+	return count
+}
+
+// ManagedReplicaCount is the number of instantiated replicas of the managed replica type ...
+func (c Collection) ManagedReplicaCount() int32 {
+	if c.ManagedReplicaType() == ReplicaTypeTLOG {
+		return c.TlogReplicaCount
+	}
+	return c.NrtReplicaCount
+}
+
+// CollectionStatus holds index statistics of a collection as reported by COLSTATUS ...
+type CollectionStatus struct {
+	// The name of the collection
+	Name string
+	// The statistics of the active shards mapped by shard name
+	Shards map[string]ShardStatus
+}
+
+// ShardStatus holds index statistics of the leader of a shard. Either value is -1 if Solr didn't report it ...
+type ShardStatus struct {
+	// The name of the shard
+	Name string
+	// The number of documents in the shard, not counting deleted documents
+	DocCount int64
+	// The size of the index of the shard in bytes
+	SizeBytes int64
+}
+
+// DocCount is the number of documents in the collection, or -1 if it isn't known for every shard ...
+func (c CollectionStatus) DocCount() int64 {
+	return c.sum(func(shard ShardStatus) int64 { return shard.DocCount })
+}
+
+// SizeBytes is the size of the index of the collection (not counting replicas), or -1 if it isn't known for every
+// shard ...
+func (c CollectionStatus) SizeBytes() int64 {
+	return c.sum(func(shard ShardStatus) int64 { return shard.SizeBytes })
+}
+
+func (c CollectionStatus) sum(value func(shard ShardStatus) int64) int64 {
+	if len(c.Shards) == 0 {
+		return -1
+	}
+	var total int64
+	for _, shard := range c.Shards {
+		v := value(shard)
+		if v < 0 {
+			return -1
+		}
+		total += v
+	}
+	return total
+}
+
+// Mutation describes a request which changed (or tried to change) Solr ...
+type Mutation struct {
+	// The API that was called (collections, configs, or update)
+	Api string
+	// The action that was requested (e.g. CREATE, DELETEREPLICA, or UPLOAD)
+	Action string
+	// The collection, alias, or config set the action applies to
+	Target string
+	// The request URL with credentials and sensitive parameters redacted
+	Url string
+	// The HTTP status of the response (0 if no response was received)
+	StatusCode int
+	// The error if the request couldn't be made
+	Err error
+}
+
+// Succeeded tests if Solr accepted the mutation ...
+func (m Mutation) Succeeded() bool {
+	return m.Err == nil && m.StatusCode == 200
 }
@@ -1,20 +1,28 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"embed"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"maps"
+	"path"
 	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -25,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -42,17 +51,45 @@ const (
 	// typeSolrCollectionSetStable indicates the specified state and the cluster state are aligned and no errors have
 	// been encountered during the reconcile
 	typeSolrCollectionSetStable = "Stable"
+	// typeSolrCollectionSetCollectionsReady indicates all the specified collections exist in the cluster (and no
+	// unspecified collections remain)
+	typeSolrCollectionSetCollectionsReady = "CollectionsReady"
+	// typeSolrCollectionSetConfigSetsSynced indicates the config sets in the cluster match the config set configmaps
+	typeSolrCollectionSetConfigSetsSynced = "ConfigSetsSynced"
+	// typeSolrCollectionSetReplicasReady indicates every shard of every collection has the specified number of replicas
+	typeSolrCollectionSetReplicasReady = "ReplicasReady"
+	// typeSolrCollectionSetInitialized indicates the checksums collection (and its config set) has been set up in the
+	// cluster. It isn't set on collection sets that are only being observed.
+	typeSolrCollectionSetInitialized = "Initialized"
 
 	// Condition reasons ...
 
 	// reasonSolrCollectionSetStable is used when the collection set is stable
 	reasonSolrCollectionSetStable = "stable"
+	// reasonSolrCollectionSetNoCollectionsSpecified is used when the collection set is stable, but doesn't specify any
+	// collections
+	reasonSolrCollectionSetNoCollectionsSpecified = "noCollectionsSpecified"
+	// reasonSolrCollectionSetInitialized is used when the checksums collection has been set up
+	reasonSolrCollectionSetInitialized = "initialized"
+	// reasonSolrCollectionSetCollectionsReady is used when the collections in the cluster match the spec
+	reasonSolrCollectionSetCollectionsReady = "collectionsReady"
+	// reasonSolrCollectionSetConfigSetsSynced is used when the config sets in the cluster match the spec
+	reasonSolrCollectionSetConfigSetsSynced = "configSetsSynced"
+	// reasonSolrCollectionSetConfigSetSyncFailed means the config sets could not be synced with the cluster
+	reasonSolrCollectionSetConfigSetSyncFailed = "configSetSyncFailed"
+	// reasonSolrCollectionSetReplicasReady is used when the replicas in the cluster match the spec
+	reasonSolrCollectionSetReplicasReady = "replicasReady"
 	// reasonSolrCollectionSetInitializing means the collection set is being initialized
 	reasonSolrCollectionSetInitializing = "initializing"
 	// reasonSolrCollectionSetScalingIn means collection replicas are being reduced
 	reasonSolrCollectionSetScalingIn = "scalingIn"
 	// reasonSolrCollectionSetScalingOut means collection replicas are being increased
 	reasonSolrCollectionSetScalingOut = "scalingOut"
+	// reasonSolrCollectionSetScalingPaused means the replicas don't match the spec, but scaling has been paused
+	reasonSolrCollectionSetScalingPaused = "scalingPaused"
+	// reasonSolrCollectionSetReplicasRecovering is used when replicas of a collection aren't active (e.g. down or
+	// recovering)
+	reasonSolrCollectionSetReplicasRecovering = "replicasRecovering"
 	// reasonSolrCollectionAddingCollections means collections are being added
 	reasonSolrCollectionAddingCollections = "addingCollections"
 	// reasonSolrCollectionRemovingCollections means collection are being removed
@@ -63,6 +100,43 @@ const (
 
 	// reasonSolrCollectionSetReconcileError means an error has been encountered during the reconcile process
 	reasonSolrCollectionSetReconcileError = "errorEncountered"
+	// reasonSolrCollectionSetMaxCollectionsExceeded means the spec calls for more collections than the set allows
+	reasonSolrCollectionSetMaxCollectionsExceeded = "maxCollectionsExceeded"
+	// reasonSolrCollectionSetMissingConfigSet means a collection references a config set that's neither in Solr nor
+	// available as a configmap
+	reasonSolrCollectionSetMissingConfigSet = "missingConfigSet"
+	// reasonSolrCollectionSetShardCountImmutable means the spec calls for a different number of shards than a collection
+	// has in Solr, which Solr can't change
+	reasonSolrCollectionSetShardCountImmutable = "shardCountImmutable"
+	// reasonSolrCollectionSetRouterFieldImmutable means the spec calls for a different router field than a collection
+	// has in Solr, which Solr can't change
+	reasonSolrCollectionSetRouterFieldImmutable = "routerFieldImmutable"
+	// reasonSolrCollectionSetNameCollision means an alias in the spec has the same name as a collection or another alias
+	reasonSolrCollectionSetNameCollision = "nameCollision"
+	// reasonSolrCollectionSetAliasConflict means more than one collection in the spec claims the same alias
+	reasonSolrCollectionSetAliasConflict = "aliasConflict"
+	// reasonSolrCollectionSetCleanupGuardTripped means cleanup would delete more of the collections than the spec allows
+	// in one go (see annotationAllowCleanup)
+	reasonSolrCollectionSetCleanupGuardTripped = "cleanupGuardTripped"
+	// reasonSolrCollectionSetBlueGreenTransition means blue/green was turned on or off while the set still has collections
+	// from the other mode, which would be deleted (with cleanup) and recreated empty
+	reasonSolrCollectionSetBlueGreenTransition = "blueGreenTransition"
+	// reasonSolrCollectionSetClusterStatusShrank means most of the collections that existed when the status was last
+	// updated are missing from the cluster status, which is more likely a partial read than the collections being gone
+	reasonSolrCollectionSetClusterStatusShrank = "clusterStatusShrank"
+	// reasonSolrCollectionSetClusterDegraded means fewer Solr nodes are live than the spec requires before the cluster
+	// is changed
+	reasonSolrCollectionSetClusterDegraded = "clusterDegraded"
+	// reasonSolrCollectionSetReconcileTimeout means the reconcile took longer than the reconcile timeout and was cut short
+	reasonSolrCollectionSetReconcileTimeout = "reconcileTimeout"
+	// reasonSolrCollectionSetPlacementInfeasible means the live Solr nodes can't hold the replicas of a collection that
+	// needs to be created the way the spec places them
+	reasonSolrCollectionSetPlacementInfeasible = "placementInfeasible"
+	// reasonSolrCollectionSetAuthFailed means Solr rejected the credentials
+	reasonSolrCollectionSetAuthFailed = "authFailed"
+	// reasonSolrCollectionSetAuthInsufficient means Solr accepted the credentials but they don't have permission for
+	// the API called
+	reasonSolrCollectionSetAuthInsufficient = "authInsufficient"
 
 	// Events ...
 
@@ -72,36 +146,471 @@ const (
 	eventSolrCollectionSetScaleOut = "ScaleOut"
 	// eventSolrCollectionSetScaleIn  is an event which indicates that a scale in operation has started
 	eventSolrCollectionSetScaleIn = "ScaleIn"
+	// eventSolrCollectionSetReplicaRepair is an event which indicates that replicas are being added to replace replicas
+	// on Solr nodes that aren't live (as opposed to a scale out called for by the spec)
+	eventSolrCollectionSetReplicaRepair = "ReplicaRepair"
 	// eventSolrCollectionSetAddingCollection  is an event which indicates collections are being added
 	eventSolrCollectionSetAddingCollection = "AddingCollection"
 	// eventSolrCollectionSetRemovingCollection is an event which indicates collections are being removed
 	eventSolrCollectionSetRemovingCollection = "RemovingCollection"
+	// eventSolrCollectionSetConfigSetUploaded is an event which indicates a config set was uploaded to Solr
+	eventSolrCollectionSetConfigSetUploaded = "ConfigSetUploaded"
+	// eventSolrCollectionSetConfigSetRemoved is an event which indicates a config set was removed from Solr
+	eventSolrCollectionSetConfigSetRemoved = "ConfigSetRemoved"
+	// eventSolrCollectionSetConfigSetChanged is an event which indicates a collection was pointed at a different config
+	// set
+	eventSolrCollectionSetConfigSetChanged = "ConfigSetChanged"
+	// eventSolrCollectionSetCollectionReloaded is an event which indicates a collection was reloaded to pick up a
+	// change to its config set
+	eventSolrCollectionSetCollectionReloaded = "CollectionReloaded"
+	// eventSolrCollectionSetCollectionOptimized is an event which indicates a collection was optimized
+	eventSolrCollectionSetCollectionOptimized = "CollectionOptimized"
+	// eventSolrCollectionSetShardSplit is an event which indicates a shard was split
+	eventSolrCollectionSetShardSplit = "ShardSplit"
+	// eventSolrCollectionSetAliasDriftCorrected is an event which indicates an alias was found pointing at the wrong
+	// collection (or missing) and was repointed
+	eventSolrCollectionSetAliasDriftCorrected = "AliasDriftCorrected"
+	// eventSolrCollectionSetAliasRemoved is an event which indicates an alias the collection set no longer manages (e.g.
+	// the alias of a collection was renamed) was removed
+	eventSolrCollectionSetAliasRemoved = "AliasRemoved"
+	// eventSolrCollectionSetPromotedColor is an event which indicates the alias of a blue/green collection was switched
+	// to the other color because activeColor changed
+	eventSolrCollectionSetPromotedColor = "PromotedColor"
+	// eventSolrCollectionSetUnstableTooLong is a warning event which indicates the collection set has been unstable for
+	// longer than the unstable warning threshold
+	eventSolrCollectionSetUnstableTooLong = "UnstableTooLong"
+	// eventSolrCollectionSetNoCollectionsSpecified is an event which indicates the collection set doesn't specify any
+	// collections
+	eventSolrCollectionSetNoCollectionsSpecified = "NoCollectionsSpecified"
+	// eventSolrCollectionSetCollectionMarkedForDeletion is a warning event which indicates a collection was removed from
+	// the spec and will be deleted once the cleanup grace period is up
+	eventSolrCollectionSetCollectionMarkedForDeletion = "CollectionMarkedForDeletion"
+	// eventSolrCollectionSetAuthFailed is a warning event which indicates Solr rejected the credentials (or their
+	// permissions) so the reconcile couldn't go on
+	eventSolrCollectionSetAuthFailed = "AuthFailed"
 )
 
+// Annotations ...
+const (
+	// annotationSplitShard triggers a one-shot split of a shard. The value is <collection>/<shard> where collection
+	// is the instance name (i.e. including the blue/green suffix). The split runs asynchronously in Solr and is tracked
+	// in status.pendingOperations. The annotation is removed once the split finishes. Shards of collections which
+	// specify numShards can't be split, since the shard count of those collections is checked against the spec.
+	annotationSplitShard = "solrcollections.solr.sis.uw.edu/split-shard"
+	// annotationForceConfigSetResync triggers a one-shot re-upload of every config set (and a refresh of the checksum
+	// records) regardless of whether the checksums match. The value must be "true". The annotation is removed once the
+	// config sets have been uploaded.
+	annotationForceConfigSetResync = "solrcollections.solr.sis.uw.edu/force-configset-resync"
+	// annotationOptimize triggers a one-shot optimize of collections. The value is "true" to optimize every collection
+	// of the set, or a comma separated list of instance names (i.e. including the blue/green suffix). The collections are
+	// optimized in the background and tracked in status.pendingOperations. The annotation is removed once the optimize
+	// has been started.
+	annotationOptimize = "solrcollections.solr.sis.uw.edu/optimize"
+	// annotationReconcileOnly scopes reconciles to the collections whose names match one of a comma separated list of
+	// patterns (e.g. "books*,authors"), leaving the other collections alone, for staged rollouts. Unlike the other
+	// annotations it isn't removed, the reconciles stay scoped until it's removed by hand.
+	annotationReconcileOnly = "solrcollections.solr.sis.uw.edu/reconcile-only"
+	// annotationAllowCleanup lets a one-shot cleanup go ahead even though it deletes more collections than the spec's
+	// cleanupMaxDeletions or cleanupMaxPercent allow. The value must be "true". The annotation is removed once the
+	// collections have been cleaned up.
+	annotationAllowCleanup = "solrcollections.solr.sis.uw.edu/allow-cleanup"
+	// annotationReconcileNow forces a reconcile right away (skipping any error backoff) whenever its value changes. The
+	// value is anything that changes from one request to the next (e.g. a timestamp). Rather than being removed, the
+	// value is acknowledged in status.lastReconcileRequest.
+	annotationReconcileNow = "solrcollections.solr.sis.uw.edu/reconcile-now"
+)
+
+// Kinds of asynchronous operations tracked in status.pendingOperations ...
+const (
+	operationSplitShard = "SplitShard"
+	operationOptimize   = "Optimize"
+)
+
+// Config set configmap labels ...
+const (
+	// configMapLabelCollectionSet names the collection set a config set configmap belongs to
+	configMapLabelCollectionSet = "collectionSet"
+	// configMapLabelCollection is the name of the config set held by a configmap
+	configMapLabelCollection = "collection"
+	// configMapLabelConfigSetVersion (optional) is the version of the config set held by a configmap. The config set is
+	// named <collection>-<version> in Solr, or <collection>-<start of checksum> if the version is "hash"
+	configMapLabelConfigSetVersion = "configSetVersion"
+	// configSetVersionHash is the config set version that's replaced by the start of the config set's checksum
+	configSetVersionHash = "hash"
+	// configSetVersionHashLength is how many characters of the checksum a hash version uses
+	configSetVersionHashLength = 10
+)
+
+// defaultSolrSecretNamespace is the namespace basic auth secrets are read from if no namespace is configured ...
+const defaultSolrSecretNamespace = "default"
+
+// defaultSolrClusterUrlTemplate is the URL of the Solr cluster of a collection set that doesn't specify one (and when
+// there's no operator-wide default). It's filled in with the scheme, the name of the collection set, and the port ...
+const defaultSolrClusterUrlTemplate = "%s://%s-solrcloud:%d/solr"
+
 const (
 	// this has a placeholder for the collection set name ...
 	configChecksumsCollectionNameTemplate = "_%sChecksums"
 	configChecksumsConfigSetName          = "_checksums"
+	// the checksums collection (and its config set) used by collection sets which share one ...
+	configSharedChecksumsCollectionName = "_sharedChecksums"
+	configSharedChecksumsConfigSetName  = "_sharedChecksums"
+)
+
+// checksumsConfigSet is a config set used by checksums collections ...
+type checksumsConfigSet struct {
+	// name is the name of the config set in Solr
+	name string
+	// dirs are the embedded directories the config set is read from. Files in later directories replace files of the
+	// same name in earlier ones, so a config set only holds the files that differ from the config set it builds on
+	dirs []string
+}
+
+// checksumsConfigSetVersion is the version of the embedded checksums config sets. Bump it whenever they change so
+// that existing checksums collections are upgraded. The version a checksums collection was last upgraded to is
+// recorded in its checksumsSchemaVersionProperty property ...
+const (
+	checksumsConfigSetVersion      = 1
+	checksumsSchemaVersionProperty = "checksumsSchemaVersion"
+)
+
+// configSetChecksumProperty is the collection property which records the checksum of the config set the collection
+// was last (re)loaded with ...
+const configSetChecksumProperty = "configSetChecksum"
+
+var (
+	perSetChecksumsConfigSet = checksumsConfigSet{name: configChecksumsConfigSetName,
+		dirs: []string{"checksum_collection_configset"}}
+	// The shared config set only differs in its schema, so it reuses the rest of the per-set config set ...
+	sharedChecksumsConfigSet = checksumsConfigSet{name: configSharedChecksumsConfigSetName,
+		dirs: []string{"checksum_collection_configset", "shared_checksum_collection_configset"}}
 )
 
 const (
 	errorRequeueSeconds   = 60
 	backoffRequeueSeconds = 20
+	// pendingDeletionSeconds is how long after a collection is deleted that it's left alone (i.e. not deleted again or
+	// recreated) while Solr finishes removing it ...
+	pendingDeletionSeconds = 120
+	// splitShardTimeoutMinutes is how long to wait on a shard split before giving up ...
+	splitShardTimeoutMinutes = 30
+	// pendingOperationPollSeconds is how often a pending asynchronous operation (e.g. a shard split) is checked on ...
+	pendingOperationPollSeconds = 30
+	// optimizeTimeoutMinutes is how long to wait on the optimize of a collection before giving up ...
+	optimizeTimeoutMinutes = 60
+	// maxConsecutiveImmediateRequeues is how many reconciles in a row can change Solr and requeue immediately before
+	// the reconciles are delayed ...
+	maxConsecutiveImmediateRequeues = 10
+	// errorBackoffBaseSeconds is how long the reconcile is delayed after the first of a run of errors. The delay doubles
+	// with each error after that up to maxErrorBackoffSeconds ...
+	errorBackoffBaseSeconds = 5
+	maxErrorBackoffSeconds  = 300
+	// forceDeleteAfterFailures is how many times in a row deleting a collection can fail before it's force deleted ...
+	forceDeleteAfterFailures = 3
+	// clusterStatusShrinkRetries is how many reconciles in a row have to see the collections missing from the cluster
+	// status before it's believed ...
+	clusterStatusShrinkRetries = 3
+	// interruptedOperationTimeoutSeconds is how long recording an operation interrupted by shutdown can take ...
+	interruptedOperationTimeoutSeconds = 5
+	// solrSecretHeaderPrefix is the prefix of basic auth secret keys which are added to Solr requests as headers ...
+	solrSecretHeaderPrefix = "header."
 )
 
 // This annotation is what causes the files to become embedded ...
 // vvvvvvv
 //
-//go:embed checksum_collection_configset
+//go:embed checksum_collection_configset shared_checksum_collection_configset
 var checksumCollectionSchema embed.FS
 
-var solrClient solr.SolrClient
-
 // SolrCollectionSetReconciler reconciles a SolrCollectionSet object
 type SolrCollectionSetReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// SolrRateLimiter caps the rate of calls to the Solr API across all reconciles. If nil calls aren't throttled.
+	SolrRateLimiter *rate.Limiter
+	// SolrCommitStrategy determines how records written to Solr (e.g. checksums) are committed
+	SolrCommitStrategy string
+	// SolrCommitWithinMillis is the commitWithin time used by the commitWithin commit strategy
+	SolrCommitWithinMillis int
+	// SolrChecksumsFromLeader sends checksum reads and writes straight to the checksums collection's leader core
+	SolrChecksumsFromLeader bool
+	// UnstableWarningThreshold is how long a collection set can be unstable before a warning event is emitted. If zero
+	// no warning is emitted.
+	UnstableWarningThreshold time.Duration
+	// ReconcileTimeout is how long a single reconcile can run before its context is cancelled, so that a hung call to
+	// Solr can't hold up the collection set forever. If zero (the default) there's no timeout. It's opt-in because it
+	// has to allow for the longest calls a reconcile makes (e.g. uploading large config sets to a busy cluster).
+	ReconcileTimeout time.Duration
+	// CollectionCreateWait is how long a reconcile waits for a new collection to become active with all of its
+	// replicas before leaving it to the next reconcile. If zero new collections aren't waited on.
+	CollectionCreateWait time.Duration
+
+	// DefaultSolrClusterUrl is the Solr cluster URL used by collection sets that don't specify one
+	DefaultSolrClusterUrl string
+	// DefaultSolrSecretName is the basic auth secret used by collection sets that don't specify one
+	DefaultSolrSecretName string
+	// DefaultSolrSecretNamespace is the namespace the basic auth secrets are read from
+	DefaultSolrSecretNamespace string
+	// SolrCredentialsPath is where the Solr credentials are read from (see solr.SolrClient.CredentialsPath) by collection
+	// sets that don't specify a secret. It takes precedence over DefaultSolrSecretName.
+	SolrCredentialsPath string
+
+	// Elected is closed once this operator instance is the leader (see ctrl.Manager.Elected()). Solr is only changed
+	// by the leader so that several operator replicas don't race each other. If nil the instance is always the leader.
+	Elected <-chan struct{}
+
+	// Auditor records every call that changes Solr. If nil nothing is audited.
+	Auditor Auditor
+
+	// CollectionStatsInterval is how often the document counts and index sizes of the collections are fetched (with
+	// COLSTATUS) into the status. If zero they aren't fetched.
+	CollectionStatsInterval time.Duration
+
+	// MaxConcurrentReconciles is the number of collection sets that can be reconciled at the same time. If it's zero
+	// then the controller-runtime default (one) is used.
+	MaxConcurrentReconciles int
+
+	// ConfigSetVerifyInterval is how often the config sets of collection sets with VerifyConfigSets set are downloaded
+	// from Solr and compared with their configmaps. If zero they're compared on every reconcile.
+	ConfigSetVerifyInterval time.Duration
+
+	// ConfigSetCacheSize is the number of decoded/zipped config sets kept in memory so that unchanged config sets
+	// aren't decoded or zipped on every reconcile. If zero nothing is cached.
+	ConfigSetCacheSize int
+
+	// pendingDeletions tracks collections that have been deleted, but may still show up in the cluster status
+	pendingDeletions deletionTracker
+
+	// errorBackoffs holds off reconciling collection sets whose last reconcile failed until their backoff is up
+	errorBackoffs errorBackoffTracker
+	// requeueBackoffs holds off reconciling collection sets which have changed Solr in too many reconciles in a row (see
+	// requeueAfterChange())
+	requeueBackoffs errorBackoffTracker
+	// solrClients holds a Solr client per cluster so that reconciles of collection sets in different clusters don't
+	// share a client
+	solrClients solrClientCache
+	// configSetZips holds decoded/zipped config sets keyed by checksum (see ConfigSetCacheSize)
+	configSetZips configSetCache
+	// optimizes tracks the optimizes running in the background (see startOptimize())
+	optimizes optimizeTracker
+}
+
+// solrClientCache holds the Solr clients created so far, keyed by cluster URL and secret. The zero value is ready to
+// use.
+type solrClientCache struct {
+	mu      sync.Mutex
+	clients map[string]solrClientCacheEntry
+}
+
+type solrClientCacheEntry struct {
+	client solr.SolrClient
+	// secretVersion is the resource version of the secret the client's credentials were read from
+	secretVersion string
+}
+
+// get returns the client for the given cluster URL and secret, creating it with the given function if it doesn't
+// exist yet. The client is also created again if the secret has changed since (i.e. its resource version differs), so
+// that rotated credentials are picked up ...
+func (c *solrClientCache) get(clusterUrl string, secretRef string, secretVersion string,
+	create func() (solr.SolrClient, error)) (solr.SolrClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := clusterUrl + "|" + secretRef
+	if entry, exists := c.clients[key]; exists && entry.secretVersion == secretVersion {
+		return entry.client, nil
+	}
+	solrClient, err := create()
+	if err != nil {
+		return solr.SolrClient{}, err
+	}
+	if c.clients == nil {
+		c.clients = make(map[string]solrClientCacheEntry)
+	}
+	c.clients[key] = solrClientCacheEntry{client: solrClient, secretVersion: secretVersion}
+	return solrClient, nil
+}
+
+// configSetCache holds config set zips keyed by checksum so that a config set which hasn't changed doesn't have to be
+// decoded or zipped again. The least recently used zips are dropped once there are more than the given number of them.
+// The zero value is ready to use.
+type configSetCache struct {
+	mu      sync.Mutex
+	entries map[string]*configSetCacheEntry
+}
+
+type configSetCacheEntry struct {
+	data     []byte
+	lastUsed time.Time
+}
+
+// get returns the zip for the given key, loading it with the given function if it isn't cached. If maxEntries is zero
+// then nothing is cached ...
+func (c *configSetCache) get(key string, maxEntries int, load func() ([]byte, error)) ([]byte, error) {
+	if maxEntries <= 0 {
+		return load()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, exists := c.entries[key]; exists {
+		entry.lastUsed = time.Now()
+		return entry.data, nil
+	}
+	data, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]*configSetCacheEntry)
+	}
+	c.entries[key] = &configSetCacheEntry{data: data, lastUsed: time.Now()}
+	for len(c.entries) > maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.entries {
+			if oldestKey == "" || entry.lastUsed.Before(oldest) {
+				oldestKey, oldest = k, entry.lastUsed
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	return data, nil
+}
+
+// deletionTracker tracks collection deletions that are in-flight so that the reconcile doesn't flip-flop between
+// deleting and recreating a collection while Solr is still removing it. The zero value is ready to use.
+type deletionTracker struct {
+	mu        sync.Mutex
+	deletions map[string]time.Time
+}
+
+// add records that a deletion of the given collection was requested ...
+func (d *deletionTracker) add(collectionName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.deletions == nil {
+		d.deletions = make(map[string]time.Time)
+	}
+	d.deletions[collectionName] = time.Now()
+}
+
+// isPending tests if a deletion of the given collection was requested within the deletion window ...
+func (d *deletionTracker) isPending(collectionName string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	requested, exists := d.deletions[collectionName]
+	if !exists {
+		return false
+	}
+	if time.Since(requested) > time.Second*pendingDeletionSeconds {
+		delete(d.deletions, collectionName)
+		return false
+	}
+	return true
+}
+
+// errorBackoffTracker keeps track of when collection sets whose reconcile failed can be reconciled again. Recording an
+// error updates the status, which queues another reconcile right away, so the backoff has to be enforced when the
+// reconcile starts rather than relying on the requeue delay. A change to the spec ends the backoff. The zero value is
+// ready to use.
+type errorBackoffTracker struct {
+	mu       sync.Mutex
+	backoffs map[string]errorBackoff
+}
+
+type errorBackoff struct {
+	generation int64
+	retryAt    time.Time
+}
+
+// add holds off reconciling the given collection set (at the given generation) for the given delay ...
+func (e *errorBackoffTracker) add(collectionSet string, generation int64, delay time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.backoffs == nil {
+		e.backoffs = make(map[string]errorBackoff)
+	}
+	e.backoffs[collectionSet] = errorBackoff{generation: generation, retryAt: time.Now().Add(delay)}
+}
+
+// remaining determines how long the backoff of the given collection set has left. Zero is returned if it isn't backing
+// off (or the spec has changed since) ...
+func (e *errorBackoffTracker) remaining(collectionSet string, generation int64) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	backoff, exists := e.backoffs[collectionSet]
+	if !exists {
+		return 0
+	}
+	remaining := time.Until(backoff.retryAt)
+	if remaining <= 0 || backoff.generation != generation {
+		delete(e.backoffs, collectionSet)
+		return 0
+	}
+	return remaining
+}
+
+// reset ends the backoff of the given collection set ...
+func (e *errorBackoffTracker) reset(collectionSet string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.backoffs, collectionSet)
+}
+
+// optimizeTracker keeps track of the optimizes running in the background, keyed by request id. Solr can't run an
+// optimize asynchronously, so the operator calls it from a goroutine of its own and later reconciles check on it (see
+// CheckOptimizes()). The zero value is ready to use.
+type optimizeTracker struct {
+	mu      sync.Mutex
+	results map[string]error
+	running map[string]bool
+}
+
+// start runs the given optimize in the background under the given request id ...
+func (o *optimizeTracker) start(requestId string, optimize func() error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.running == nil {
+		o.running = make(map[string]bool)
+		o.results = make(map[string]error)
+	}
+	o.running[requestId] = true
+	go func() {
+		err := optimize()
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		delete(o.running, requestId)
+		o.results[requestId] = err
+	}()
+}
+
+// state returns the state of the optimize with the given request id, using the same states as Solr async requests. If
+// the optimize is finished, the error it finished with (if any) is returned too and it's forgotten. An optimize that
+// isn't known (e.g. because the operator restarted since it was started) is solr.AsyncStateNotFound ...
+func (o *optimizeTracker) state(requestId string) (state string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.running[requestId] {
+		return solr.AsyncStateRunning, nil
+	}
+	err, exists := o.results[requestId]
+	if !exists {
+		return solr.AsyncStateNotFound, nil
+	}
+	delete(o.results, requestId)
+	if err != nil {
+		return solr.AsyncStateFailed, err
+	}
+	return solr.AsyncStateCompleted, nil
+}
+
+// errorBackoffDelay works out how long to delay the reconcile after the given number of errors in a row ...
+func errorBackoffDelay(consecutiveErrors int32) time.Duration {
+	delay := time.Second * errorBackoffBaseSeconds
+	for i := int32(1); i < consecutiveErrors && delay < time.Second*maxErrorBackoffSeconds; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Second*maxErrorBackoffSeconds)
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to move the current state of the cluster
@@ -130,8 +639,17 @@ type SolrCollectionSetReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 func (r *SolrCollectionSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = withAuditCollectionSet(ctx, req.NamespacedName.String())
 	logger := log.FromContext(ctx)
 
+	// Give up on the reconcile if it runs too long. Whatever is in flight fails with the context's error and ends up in
+	// RequeueOnError(), which reports the timeout and backs off ...
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
 	// Get the collection set (aka the collection set spec) via the Kubernetes API ...
 	collectionSetSpec := &solrCollectionSet.SolrCollectionSet{}
 	err := r.Get(ctx, req.NamespacedName, collectionSetSpec)
@@ -150,6 +668,32 @@ func (r *SolrCollectionSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return requeue()
 	}
 
+	// A reconcile that's been asked for explicitly (see annotationReconcileNow) doesn't wait out the error backoff. The
+	// request is acknowledged before anything else so that a reconcile that fails doesn't keep skipping the backoff ...
+	if value, exists := collectionSetSpec.Annotations[annotationReconcileNow]; exists &&
+		value != collectionSetSpec.Status.LastReconcileRequest {
+		logger.Info(fmt.Sprintf("reconcile requested with [%s]", value))
+		r.errorBackoffs.reset(req.NamespacedName.String())
+		r.requeueBackoffs.reset(req.NamespacedName.String())
+		if err := r.acknowledgeReconcileRequest(ctx, collectionSetSpec, value); err != nil {
+			logger.Error(err, "failed to acknowledge the reconcile request")
+			return requeue()
+		}
+	}
+
+	// Hold off while the collection set is backing off after errors ...
+	if remaining := r.errorBackoffs.remaining(req.NamespacedName.String(), collectionSetSpec.Generation); remaining > 0 {
+		logger.Info(fmt.Sprintf("backing off after [%d] errors in a row, retrying in [%s]",
+			collectionSetSpec.Status.ConsecutiveErrors, remaining.Round(time.Second)))
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+	// ... or after changing Solr in too many reconciles in a row ...
+	if remaining := r.requeueBackoffs.remaining(req.NamespacedName.String(), collectionSetSpec.Generation); remaining > 0 {
+		logger.Info(fmt.Sprintf("backing off after changing Solr in [%d] reconciles in a row, retrying in [%s]",
+			collectionSetSpec.Status.ConsecutiveImmediateRequeues, remaining.Round(time.Second)))
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
 	// Initialize status Conditions if not yet present ...
 	if len(collectionSetSpec.Status.Conditions) == 0 {
 		meta.SetStatusCondition(&collectionSetSpec.Status.Conditions, metav1.Condition{
@@ -189,13 +733,69 @@ func (r *SolrCollectionSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return requeue()
 	}
 
+	// Only the leader talks to Solr. Controllers normally only run on the leader anyway, but this makes sure a
+	// replica that isn't (or is no longer) the leader can't race the leader ...
+	if !r.isLeader() {
+		logger.Info("not the leader so leaving Solr alone")
+		return requeue()
+	}
+
 	//
 	// Initialize Solr cluster. This method returns a solr.ClusterStatus object representing the current state of the
 	// Solr cluster.
-	var checksumsCollectionName = fmt.Sprintf(configChecksumsCollectionNameTemplate, collectionSetSpec.Name)
-	clusterStatus, isIntializing, err := r.InitializeSolrCluster(ctx, *collectionSetSpec, checksumsCollectionName)
+	solrClient, err := r.solrClientFor(ctx, *collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "failed to create a Solr client")
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+	}
+	// Make sure the credentials work before doing anything else, since a 401 or 403 part way through the reconcile is
+	// much harder to make sense of ...
+	err = solrClient.CheckAuth(ctx)
+	if err != nil {
+		logger.Error(err, "Solr credentials check failed")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, authFailureReason(err), err)
+	}
+	var checksumsCollectionName = checksumsCollectionNameFor(*collectionSetSpec)
+	stopTimer := startPhaseTimer(ctx, phaseInitializeSolrCluster, collectionSetSpec.Name)
+	clusterStatus, err := getClusterStatus(ctx, solrClient, *collectionSetSpec, checksumsCollectionName)
+	if err != nil {
+		stopTimer()
+		logger.Error(err, "failed to get the Solr cluster status")
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+	}
+
+	//
+	// A node that's starting up (e.g. behind a load balancer) can answer with only some of the collections. Acting on
+	// that would recreate (or clean up) collections that are really there, so if most of the collections (including
+	// the checksums collection) have gone missing since the status was last updated then back off and read the cluster
+	// status again before anything is changed. If they're still missing after a few tries then they really are gone ...
+	//
+	err = checkClusterStatusShrink(*collectionSetSpec, clusterStatus.Collections)
+	if err != nil {
+		if !clusterStatusShrinkPersisted(collectionSetSpec.Status) {
+			stopTimer()
+			logger.Info(fmt.Sprintf("%s, so treating it as a partial read", err.Error()))
+			return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetClusterStatusShrank,
+				err)
+		}
+		logger.Info(fmt.Sprintf("%s in [%d] reconciles in a row, so accepting it", err.Error(),
+			collectionSetSpec.Status.ConsecutiveErrors))
+	}
+
+	clusterStatus, isIntializing, err := r.InitializeSolrCluster(ctx, solrClient, *collectionSetSpec, clusterStatus,
+		checksumsCollectionName)
+	stopTimer()
 	if err != nil {
 		logger.Error(err, "failed to initialize the Solr cluster")
+		if isIntializing {
+			return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetInitializing, err,
+				metav1.Condition{
+					Type:    typeSolrCollectionSetInitialized,
+					Status:  metav1.ConditionFalse,
+					Reason:  reasonSolrCollectionSetInitializing,
+					Message: err.Error(),
+				})
+		}
 		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
 	}
 	// Emit the intializing event if Solr is initializing ...
@@ -205,32 +805,280 @@ func (r *SolrCollectionSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			collectionSetSpec.Name, collectionSetSpec.Namespace)
 	}
 
+	// If an operation was interrupted (by the operator shutting down or the reconcile timing out) then say so.
+	// Refreshing the status from the cluster state below (and the rest of the reconcile) verifies and finishes the
+	// operation, and once the reconcile gets all the way through UpdateObservedGeneration() clears it ...
+	if collectionSetSpec.Status.InterruptedOperation != "" {
+		logger.Info("resuming interrupted operation", "operation", collectionSetSpec.Status.InterruptedOperation)
+	}
+
+	// Remember which blue/green collections were active before the status is updated. This is used to detect aliases
+	// that have drifted ...
+	previouslyActive := activeInstances(collectionSetSpec.Status.SolrCollections)
+
 	//
 	// Compare the cluster status with the spec and persist the outcome into Kubernetes ...
 	//
-	err = r.UpdateStatus(ctx, req, collectionSetSpec, clusterStatus)
+	// Collection sets which are only being observed don't use a checksums collection ...
+	stopTimer = startPhaseTimer(ctx, phaseUpdateStatus, collectionSetSpec.Name)
+	var checksumCollectionStatus *solrCollectionSet.ChecksumCollectionStatus
+	if collectionSetSpec.Spec.Mode != solrCollectionSet.SolrCollectionSetModeObserve {
+		checksumCollectionStatus = checksumCollectionStatusOf(ctx, solrClient, *collectionSetSpec, clusterStatus,
+			checksumsCollectionName)
+	}
+	collectionStats := r.collectionStatsOf(ctx, solrClient, *collectionSetSpec, clusterStatus)
+	err = r.UpdateStatus(ctx, req, collectionSetSpec, clusterStatus, checksumCollectionStatus, collectionStats)
+	stopTimer()
 	if err != nil {
 		logger.Error(err, "update status failed")
 		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
 	}
 
+	// If the collection set is only being observed then the status is all there is to do ...
+	if collectionSetSpec.Spec.Mode == solrCollectionSet.SolrCollectionSetModeObserve {
+		err = r.UpdateObservedGeneration(ctx, req, collectionSetSpec)
+		if err != nil {
+			logger.Error(err, "update observed generation failed")
+			return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+		}
+		return requeue()
+	}
+
+	//
+	// Don't change a degraded cluster. With nodes down the cluster status doesn't reflect the capacity of the cluster,
+	// so e.g. replicas could be removed that are still needed once the nodes are back ...
+	//
+	err = checkLiveNodes(*collectionSetSpec, clusterStatus)
+	if err != nil {
+		logger.Info(fmt.Sprintf("%s, so leaving the cluster alone", err.Error()))
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetClusterDegraded, err)
+	}
+
 	//
 	// Reconcile config sets ...
 	//   (Note: This doesn't update the collection set spec so passing the collection set value vs the pointer)
 	//
-	err = r.ManageConfigSets(ctx, *collectionSetSpec, checksumsCollectionName)
+	forceResync := collectionSetSpec.Annotations[annotationForceConfigSetResync] == "true"
+	stopTimer = startPhaseTimer(ctx, phaseManageConfigSets, collectionSetSpec.Name)
+	configSetStatuses, err := r.ManageConfigSets(ctx, solrClient, *collectionSetSpec, checksumsCollectionName, forceResync)
+	stopTimer()
 	if err != nil {
 		logger.Error(err, "failed to manage config set")
+		r.RecordInterruptedOperation(ctx, collectionSetSpec, "manage config sets", err)
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetReconcileError, err,
+			metav1.Condition{
+				Type:    typeSolrCollectionSetConfigSetsSynced,
+				Status:  metav1.ConditionFalse,
+				Reason:  reasonSolrCollectionSetConfigSetSyncFailed,
+				Message: err.Error(),
+			})
+	}
+	// The re-sync is one-shot, so remove the annotation now that it's done ...
+	if _, exists := collectionSetSpec.Annotations[annotationForceConfigSetResync]; exists {
+		_, err = r.removeAnnotation(ctx, collectionSetSpec, annotationForceConfigSetResync)
+		if err != nil {
+			logger.Error(err, "failed to remove the config set re-sync annotation")
+			return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+		}
+	}
+	// Make sure every config set referenced by the spec exists before creating any collections, otherwise the
+	// collection creates would fail with confusing Solr errors ...
+	missingConfigSets, err := findMissingConfigSets(ctx, solrClient, *collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "failed to check for missing config sets")
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+	}
+	err = r.UpdateConfigSetStatus(ctx, req, collectionSetSpec, configSetStatuses, missingConfigSets)
+	if err != nil {
+		logger.Error(err, "update config set status failed")
 		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
 	}
+	if missingConfigSets != nil {
+		logger.Error(missingConfigSets, "config sets are missing")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetMissingConfigSet,
+			missingConfigSets)
+	}
+
+	//
+	// Guard against a spec that calls for more collections than allowed (e.g. because of a typo). In that case don't
+	// create anything ...
+	//
+	err = checkMaxCollections(*collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "too many collections specified")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetMaxCollectionsExceeded, err)
+	}
+
+	//
+	// Aliases and collections share names in Solr, so don't create anything if the spec has them colliding ...
+	//
+	err = checkNameCollisions(*collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "aliases collide with other names")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetNameCollision, err)
+	}
+	// Collections claiming the same alias would repoint it back and forth on every reconcile ...
+	err = checkAliasConflicts(*collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "aliases are claimed by more than one collection")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetAliasConflict, err)
+	}
+
+	//
+	// Solr can't change the number of shards of an existing collection, so rather than act on a spec that can't be
+	// met leave the collection set alone until the spec is fixed ...
+	//
+	err = checkShardCounts(*collectionSetSpec, clusterStatus.Collections)
+	if err != nil {
+		logger.Error(err, "shard count can't be changed")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetShardCountImmutable, err)
+	}
+	err = checkRouterFields(*collectionSetSpec, clusterStatus.Collections)
+	if err != nil {
+		logger.Error(err, "router field can't be changed")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetRouterFieldImmutable, err)
+	}
+
+	//
+	// Don't try to create collections whose replicas can't be placed the way the spec asks on the live nodes ...
+	//
+	err = checkPlacements(*collectionSetSpec, clusterStatus)
+	if err != nil {
+		logger.Error(err, "collections can't be placed")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetPlacementInfeasible, err)
+	}
+
+	//
+	// Turning blue/green on or off would leave the existing collections behind (and clean them up) while creating new,
+	// empty collections, so leave the collection set alone until the existing collections have been dealt with ...
+	//
+	err = checkBlueGreenTransition(*collectionSetSpec, clusterStatus.Collections)
+	if err != nil {
+		logger.Error(err, "blue/green can't be turned on or off while collections from the other mode exist")
+		return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetBlueGreenTransition, err)
+	}
+
+	//
+	// If the reconcile has been scoped to some of the collections (see annotationReconcileOnly) then the collections,
+	// their aliases, reloads, config overlays and replicas are only reconciled for those, routed aliases are only
+	// created if their names match, and nothing is cleaned up (neither collections nor aliases). The status, the config
+	// sets and the checks (above) still cover every collection, and shard splits and optimizes still go ahead since
+	// they're asked for by collection ...
+	//
+	scopedSpec := collectionSetSpec
+	if value, exists := collectionSetSpec.Annotations[annotationReconcileOnly]; exists {
+		scoped, err := scopedCollectionSet(*collectionSetSpec, value)
+		if err != nil {
+			logger.Error(err, "invalid reconcile-only annotation")
+			return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+		}
+		logger.Info(fmt.Sprintf("only reconciling collections matching [%s]", value), "collections",
+			len(scoped.Spec.Collections))
+		scopedSpec = &scoped
+	}
+
+	//
+	// If the collections were emptied (or drastically cut) by mistake then cleanup would delete most of them, so refuse
+	// to clean up more than the spec allows in one go unless it's been explicitly allowed ...
+	//
+	cleanupGuardErr := checkCleanupGuard(*scopedSpec, clusterStatus.Collections, &r.pendingDeletions)
+	allowCleanup := collectionSetSpec.Annotations[annotationAllowCleanup] == "true"
+	if cleanupGuardErr != nil {
+		if !allowCleanup {
+			logger.Error(cleanupGuardErr, "cleanup guard tripped")
+			return r.requeueOnErrorWithReason(ctx, req, collectionSetSpec, reasonSolrCollectionSetCleanupGuardTripped,
+				cleanupGuardErr)
+		}
+		logger.Info(fmt.Sprintf("%s but it has been allowed with [%s]", cleanupGuardErr.Error(),
+			annotationAllowCleanup))
+	}
 
 	//
 	// Reconcile collections ...
 	//   (Note: This doesn't update the  collection set spec so passing the collection set value vs the pointer)
-	changed = r.ManageCollections(ctx, *collectionSetSpec, clusterStatus.Collections, clusterStatus.Aliases)
+	stopTimer = startPhaseTimer(ctx, phaseManageCollections, collectionSetSpec.Name)
+	changed = r.ManageCollections(ctx, solrClient, *scopedSpec, clusterStatus.Collections, clusterStatus.Aliases)
+	stopTimer()
+	// ManageCollections() logs its own errors and carries on, so an interruption shows in the context ...
+	r.RecordInterruptedOperation(ctx, collectionSetSpec, "manage collections", context.Cause(ctx))
+	if cleanupGuardErr != nil {
+		// The cleanup that was allowed has happened, so the guard is back on for the next one ...
+		if _, err := r.removeAnnotation(ctx, collectionSetSpec, annotationAllowCleanup); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to remove annotation [%s]", annotationAllowCleanup))
+		}
+	}
 	if changed {
 		// Requeue (i.e. run the reconcile again) to make sure Solr is in a stable state before proceeding.
-		return requeueImmediately()
+		return r.requeueAfterChange(ctx, collectionSetSpec)
+	}
+
+	//
+	// Create aliases and repoint any that have drifted ...
+	//
+	changed = r.ManageAliases(ctx, solrClient, scopedSpec, clusterStatus, previouslyActive)
+	if changed {
+		return r.requeueAfterChange(ctx, collectionSetSpec)
+	}
+
+	//
+	// Reload collections whose config set changed. With blue/green only the inactive color is reloaded so that the
+	// change can be checked before the alias is pointed at it ...
+	//
+	reloaded := r.ReloadCollections(ctx, solrClient, *scopedSpec, clusterStatus, configSetStatuses)
+	if *collectionSetSpec.Spec.OptimizeAfterReload && len(reloaded) > 0 {
+		err = r.startOptimize(ctx, solrClient, collectionSetSpec, reloaded)
+		if err != nil {
+			logger.Error(err, "optimize after reload failed")
+			return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+		}
+	}
+
+	//
+	// Keep the config overlays of collections matching the spec ...
+	//
+	r.ManageConfigOverlays(ctx, solrClient, *scopedSpec, clusterStatus.Collections)
+
+	//
+	// Create routed aliases ...
+	//
+	changed = r.ManageRoutedAliases(ctx, solrClient, *scopedSpec, clusterStatus.Aliases)
+	if changed {
+		return r.requeueAfterChange(ctx, collectionSetSpec)
+	}
+
+	//
+	// Split a shard if it has been requested via annotation ...
+	//
+	changed, pending, err := r.SplitShard(ctx, solrClient, collectionSetSpec, clusterStatus.Collections)
+	if err != nil {
+		logger.Error(err, "split shard failed")
+		r.RecordInterruptedOperation(ctx, collectionSetSpec, "split shard", err)
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+	}
+	if changed {
+		return r.requeueAfterChange(ctx, collectionSetSpec)
+	}
+	// Don't adjust the replicas of the collection while a split is running, just check on it again later ...
+	if pending {
+		return reconcile.Result{RequeueAfter: time.Second * pendingOperationPollSeconds}, nil
+	}
+
+	//
+	// Optimize collections if it has been requested via annotation, and check on the optimizes running in the
+	// background ...
+	//
+	changed, err = r.OptimizeCollections(ctx, solrClient, collectionSetSpec, clusterStatus.Collections)
+	if err != nil {
+		logger.Error(err, "optimize collections failed")
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+	}
+	if changed {
+		return r.requeueAfterChange(ctx, collectionSetSpec)
+	}
+	optimizing, err := r.CheckOptimizes(ctx, collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "optimize failed")
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
 	}
 
 	//
@@ -238,55 +1086,135 @@ func (r *SolrCollectionSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// The number of replicas and the number of worker nodes in the Kubernetes cluster is usually the same. However,
 	// during scale out it takes a while for the autoscaler to create nodes on which to schedule additional replicas.
 	// That means that AdjustReplicas() will sometime get errors because there aren't Solr nodes available to create
-	// replias on (because worker nodes are being created). In that case isScaling will return true.
+	// replias on (because worker nodes are being created). In that case isScaling will return true. It's also true if
+	// the scale out is being spread over several reconciles (see ScaleOutBatchSize).
 	//
-	isScaling, err := r.AdjustReplicas(ctx, *collectionSetSpec, clusterStatus.Collections, checksumsCollectionName)
+	stopTimer = startPhaseTimer(ctx, phaseAdjustReplicas, collectionSetSpec.Name)
+	// Nodes that haven't been missing for long are most likely restarting, so the replicas on them still count ...
+	liveNodes := presumedLiveNodes(clusterStatus.LiveNodes, collectionSetSpec.Status.MissingNodes,
+		replicaRepairDelay(*collectionSetSpec))
+	isScaling, err := r.AdjustReplicas(ctx, solrClient, *scopedSpec, clusterStatus.Collections,
+		clusterStatus.Aliases, liveNodes, checksumsCollectionName)
+	stopTimer()
 	if err != nil {
 		logger.Error(err, "adjust replicas failed")
+		// If the operator is shutting down (or the reconcile timed out) then the operation may have been left half done,
+		// so make a note of it for the next reconcile ...
+		r.RecordInterruptedOperation(ctx, collectionSetSpec, "adjust replicas", err)
+		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
+	}
+
+	//
+	// The spec has been fully processed so record the generation that was reconciled ...
+	//
+	err = r.UpdateObservedGeneration(ctx, req, collectionSetSpec)
+	if err != nil {
+		logger.Error(err, "update observed generation failed")
 		return r.RequeueOnError(ctx, req, collectionSetSpec, err)
 	}
 	if isScaling {
 		return reconcile.Result{RequeueAfter: time.Second * backoffRequeueSeconds}, nil
 	}
+	if optimizing {
+		return reconcile.Result{RequeueAfter: time.Second * pendingOperationPollSeconds}, nil
+	}
+	// Come back once the grace period of a collection marked for deletion is up, or once the replicas on a missing node
+	// are due to be replaced ...
+	wait, ok := nextDeletionDue(collectionSetSpec.Status.MarkedForDeletion)
+	repairWait, repairOk := nextRepairDue(collectionSetSpec.Status.MissingNodes, replicaRepairDelay(*collectionSetSpec))
+	if repairOk && (!ok || repairWait < wait) {
+		wait, ok = repairWait, true
+	}
+	if ok {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
 
 	return requeue()
 }
 
-// InitializeSolrCluster gets the Solr ready to interact with and returns the current state. It's okay to call this
-// method repeatedly.
-func (r *SolrCollectionSetReconciler) InitializeSolrCluster(ctx context.Context,
-	collectionSet solrCollectionSet.SolrCollectionSet,
-	checksumsCollectionName string) (clusterStatus solr.ClusterStatus, isInitializing bool, err error) {
+// InitializeSolrCluster gets the Solr ready to interact with, given its current state (see getClusterStatus()), and
+// returns the state after that. It's okay to call this method repeatedly.
+func (r *SolrCollectionSetReconciler) InitializeSolrCluster(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus,
+	checksumsCollectionName string) (_ solr.ClusterStatus, isInitializing bool, err error) {
 
 	logger := log.FromContext(ctx)
 
-	// If no Solr client has been instantiated then do it ...
-	if solrClient == (solr.SolrClient{}) {
-		logger.Info("instantiating a solr client")
-		secretRef := collectionSet.Spec.SecretRef
-		clusterUrl := collectionSet.Spec.SolrClusterUrl
-		sc, err := r.makeSolrClient(ctx, secretRef, clusterUrl)
-		solrClient = sc
+	// Collection sets which are only being observed never change the Solr cluster, so don't touch the checksums
+	// collection. Neither is it touched while the cluster is degraded (see checkLiveNodes()) ...
+	if collectionSet.Spec.Mode == solrCollectionSet.SolrCollectionSetModeObserve {
+		return clusterStatus, false, nil
+	}
+	if checkLiveNodes(collectionSet, clusterStatus) != nil {
+		return clusterStatus, false, nil
+	}
+
+	// See if the checksums collection exists. If it doesn't, create it ...
+	checksumsCollection, exists := clusterStatus.Collections[checksumsCollectionName]
+
+	// If the checksums collection exists, but wasn't created with the checksums config set then it doesn't have a
+	// usable schema. Deleting it would lose the checksum records, so it's pointed at the checksums config set instead.
+	// Likewise, if its config set has gone missing (e.g. it was removed by hand) or is older than the embedded config
+	// set, then the config set is uploaded. Either way the collection is reloaded so that it picks up the config set.
+	// This only replaces the config set, so the checksum records are kept ...
+	configSet := checksumsConfigSetFor(collectionSet)
+	if exists {
+		configSets, err := solrClient.GetConfigSets(ctx)
 		if err != nil {
 			return solr.ClusterStatus{}, false, err
 		}
+		version, _ := strconv.Atoi(checksumsCollection.Properties[checksumsSchemaVersionProperty])
+		misconfigured := checksumsCollection.ConfigName != configSet.name
+		missing := !contains(configSets, configSet.name)
+		if misconfigured || missing || version < checksumsConfigSetVersion {
+			if misconfigured {
+				logger.Info(fmt.Sprintf("checksums collection [%s] has config set [%s] rather than [%s] so pointing it "+
+					"at [%s]", checksumsCollectionName, checksumsCollection.ConfigName, configSet.name, configSet.name))
+			} else if missing {
+				logger.Info(fmt.Sprintf("config set [%s] for checksums collection [%s] is missing so recreating it",
+					configSet.name, checksumsCollectionName))
+			} else {
+				logger.Info(fmt.Sprintf("upgrading config set [%s] for checksums collection [%s] from version [%d] to [%d]",
+					configSet.name, checksumsCollectionName, version, checksumsConfigSetVersion))
+			}
+			err = r.uploadChecksumConfigSet(ctx, solrClient, configSet)
+			if err != nil {
+				return solr.ClusterStatus{}, false, err
+			}
+			if misconfigured {
+				err = solrClient.SetConfigSet(ctx, checksumsCollectionName, configSet.name)
+				if err != nil {
+					return solr.ClusterStatus{}, false, err
+				}
+			}
+			err = solrClient.ReloadCollection(ctx, checksumsCollectionName)
+			if err != nil {
+				return solr.ClusterStatus{}, false, err
+			}
+			err = setChecksumsSchemaVersion(ctx, solrClient, checksumsCollectionName)
+			if err != nil {
+				return solr.ClusterStatus{}, false, err
+			}
+		} else if version > checksumsConfigSetVersion {
+			logger.Info(fmt.Sprintf("checksums collection [%s] has config set version [%d] which is newer than [%d] so "+
+				"leaving it alone", checksumsCollectionName, version, checksumsConfigSetVersion))
+		}
 	}
 
-	// Fetch the Solr cluster status from the Solr API ...
-	clusterStatus, err = solrClient.GetClusterStatus(ctx)
-	if err != nil {
-		return solr.ClusterStatus{}, false, err
-	}
-
-	// See if the checksums collection exists. If it doesn't, create it ...
-	_, exists := clusterStatus.Collections[checksumsCollectionName]
 	if !exists {
 		// If the checksum collection doesn't exist then the cluster is initializing. There are a couple more things
 		// that could be checked as well, but I think this is a pretty good indicator and I don't believe it would be
 		// helpful to throw multiples of this event ...
 		isInitializing = true
-		logger.Info(fmt.Sprintf("Creating collection [%s] for checksums", configChecksumsCollectionNameTemplate))
-		err := createChecksumCollection(ctx, checksumsCollectionName, *collectionSet.Spec.ReplicationFactor)
+		logger.Info(fmt.Sprintf("Creating collection [%s] for checksums", checksumsCollectionName))
+		// A shared checksums collection doesn't belong to any one collection set ...
+		owner := collectionOwner(collectionSet)
+		if *collectionSet.Spec.SharedChecksums {
+			owner = solr.CollectionOwner{}
+		}
+		err := r.createChecksumCollection(ctx, solrClient, checksumsCollectionName, configSet,
+			collectionSet.Spec.ChecksumCollectionReplicationFactor(), *collectionSet.Spec.AutoAddReplicas,
+			solr.CollectionPlacement{Policy: collectionSet.Spec.PlacementPolicy}, owner)
 		if err != nil {
 			logger.Error(err, "failed create checksum collection")
 			return solr.ClusterStatus{}, isInitializing, err
@@ -295,7 +1223,7 @@ func (r *SolrCollectionSetReconciler) InitializeSolrCluster(ctx context.Context,
 		// Re-fetch the Solr cluster status just to provide an update to date status since a collection was added. I
 		// suppose it would be more efficient to manually add the collection the response, but it's a pretty low cost
 		// operator as far as I can tell ...
-		clusterStatus, err = solrClient.GetClusterStatus(ctx)
+		clusterStatus, err = getClusterStatus(ctx, solrClient, collectionSet, checksumsCollectionName)
 		if err != nil {
 			return solr.ClusterStatus{}, false, err
 		}
@@ -303,15 +1231,52 @@ func (r *SolrCollectionSetReconciler) InitializeSolrCluster(ctx context.Context,
 	return clusterStatus, isInitializing, nil
 }
 
-// UpdateStatus applies the given cluster status to the given collection set ...
-func (r *SolrCollectionSetReconciler) UpdateStatus(
-	ctx context.Context, req ctrl.Request, collectionSet *solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus) error {
-
-	logger := log.FromContext(ctx)
+// getClusterStatus fetches the cluster status, scoped to the collections of the given collection set if it asks for
+// that ...
+func getClusterStatus(ctx context.Context, solrClient solr.SolrClient, collectionSet solrCollectionSet.SolrCollectionSet,
+	checksumsCollectionName string) (solr.ClusterStatus, error) {
+	if !*collectionSet.Spec.ScopeClusterStatus {
+		return solrClient.GetClusterStatus(ctx)
+	}
+	// The specified collections (including disabled ones, which may still exist), the previously managed collections
+	// (which may need cleaning up), the routed aliases, and the checksums collection ...
+	var collectionNames = map[string]bool{checksumsCollectionName: true}
+	for _, spec := range collectionSet.Spec.Collections {
+		if *collectionSet.Spec.BlueGreenEnabled {
+			collectionNames[spec.Name+"_blue"] = true
+			collectionNames[spec.Name+"_green"] = true
+		} else {
+			collectionNames[spec.Name] = true
+		}
+	}
+	for _, collectionName := range collectionSet.Status.ManagedCollections {
+		collectionNames[collectionName] = true
+	}
+	for _, spec := range collectionSet.Spec.RoutedAliases {
+		collectionNames[spec.Name] = true
+	}
+	return solrClient.GetClusterStatusOf(ctx, slices.Sorted(maps.Keys(collectionNames)))
+}
+
+// UpdateStatus applies the given cluster status (and checksums collection status) to the given collection set. If
+// collection statistics are given they're applied as well, otherwise the previous statistics are kept ...
+func (r *SolrCollectionSetReconciler) UpdateStatus(
+	ctx context.Context, req ctrl.Request, collectionSet *solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus,
+	checksumCollectionStatus *solrCollectionSet.ChecksumCollectionStatus,
+	collectionStats map[string]solr.CollectionStatus) error {
+
+	logger := log.FromContext(ctx)
 
 	// Create storage for the new/empty status for the collection set  ...
 	newStatusObject := solrCollectionSet.SolrCollectionSetStatus{}
 	events := populateCollectionSetStatus(&newStatusObject, collectionSet, clusterStatus, logger)
+	newStatusObject.ChecksumCollection = checksumCollectionStatus
+	if checksumCollectionStatus != nil {
+		setInitializedCondition(&newStatusObject, collectionSet, checksumCollectionStatus.Exists)
+	}
+	if r.CollectionStatsInterval > 0 {
+		applyCollectionStats(&newStatusObject, collectionSet.Status, collectionStats)
+	}
 	// Emit events if there are any ...
 	if len(events) != 0 {
 		for eventType, reason := range events {
@@ -319,10 +1284,7 @@ func (r *SolrCollectionSetReconciler) UpdateStatus(
 		}
 	}
 
-	// Sort the collections otherwise DeepEqual won't consider the collections equal ...
-	sort.Slice(newStatusObject.SolrCollections, func(i, j int) bool {
-		return newStatusObject.SolrCollections[i].InstanceName < newStatusObject.SolrCollections[j].InstanceName
-	})
+	r.warnIfUnstableTooLong(collectionSet, &newStatusObject)
 
 	// If the new status object and the old status object differ, then apply the changes. Note that patching the
 	// collection set will cause the reconcile to be requeued.
@@ -345,6 +1307,337 @@ func (r *SolrCollectionSetReconciler) UpdateStatus(
 	return nil
 }
 
+// collectionStatsOf fetches the document counts and index sizes of the collections of the given collection set, mapped
+// by collection name. Nil is returned if they aren't due to be fetched. Collections whose statistics can't be fetched
+// are left out ...
+func (r *SolrCollectionSetReconciler) collectionStatsOf(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus) map[string]solr.CollectionStatus {
+	if r.CollectionStatsInterval <= 0 {
+		return nil
+	}
+	updatedAt := collectionSet.Status.CollectionStatsUpdatedAt
+	if updatedAt != nil && time.Since(updatedAt.Time) < r.CollectionStatsInterval {
+		return nil
+	}
+
+	logger := log.FromContext(ctx)
+
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+	var collectionStats = make(map[string]solr.CollectionStatus)
+	for collectionName := range specCollectionsMap {
+		if _, exists := clusterStatus.Collections[collectionName]; !exists {
+			continue
+		}
+		collectionStatus, err := solrClient.GetCollectionStatus(ctx, collectionName)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("could not fetch the statistics of collection [%s]", collectionName))
+			continue
+		}
+		collectionStats[collectionName] = collectionStatus
+	}
+	return collectionStats
+}
+
+// instanceName determines the name in Solr of the collection the given status is for. Collections that aren't
+// blue/green don't have an instance name, so they go by their name ...
+func instanceName(collectionStatus solrCollectionSet.SolrCollectionStatus) string {
+	if collectionStatus.InstanceName != "" {
+		return collectionStatus.InstanceName
+	}
+	return collectionStatus.Name
+}
+
+// applyCollectionStats writes the given collection statistics into the given new status. If no statistics are given
+// then the statistics in the old status are carried forward ...
+func applyCollectionStats(newStatus *solrCollectionSet.SolrCollectionSetStatus,
+	oldStatus solrCollectionSet.SolrCollectionSetStatus, collectionStats map[string]solr.CollectionStatus) {
+	if collectionStats == nil {
+		newStatus.CollectionStatsUpdatedAt = oldStatus.CollectionStatsUpdatedAt
+		var oldCollectionStatuses = make(map[string]solrCollectionSet.SolrCollectionStatus)
+		for _, collectionStatus := range oldStatus.SolrCollections {
+			oldCollectionStatuses[instanceName(collectionStatus)] = collectionStatus
+		}
+		for i := range newStatus.SolrCollections {
+			if old, exists := oldCollectionStatuses[instanceName(newStatus.SolrCollections[i])]; exists {
+				newStatus.SolrCollections[i].DocCount = old.DocCount
+				newStatus.SolrCollections[i].SizeBytes = old.SizeBytes
+			}
+		}
+		return
+	}
+
+	updatedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+	newStatus.CollectionStatsUpdatedAt = &updatedAt
+	for i := range newStatus.SolrCollections {
+		collectionStatus, exists := collectionStats[instanceName(newStatus.SolrCollections[i])]
+		if !exists {
+			continue
+		}
+		if docCount := collectionStatus.DocCount(); docCount >= 0 {
+			newStatus.SolrCollections[i].DocCount = &docCount
+		}
+		if sizeBytes := collectionStatus.SizeBytes(); sizeBytes >= 0 {
+			newStatus.SolrCollections[i].SizeBytes = &sizeBytes
+		}
+	}
+}
+
+// setInitializedCondition sets the initialized condition of the given new status according to whether the checksums
+// collection exists. The existing condition is carried forward if it hasn't changed ...
+func setInitializedCondition(newStatus *solrCollectionSet.SolrCollectionSetStatus,
+	collectionSet *solrCollectionSet.SolrCollectionSet, checksumsCollectionExists bool) {
+	condition := metav1.Condition{
+		Type:    typeSolrCollectionSetInitialized,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonSolrCollectionSetInitialized,
+		Message: "The checksums collection has been set up",
+	}
+	if !checksumsCollectionExists {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonSolrCollectionSetInitializing
+		condition.Message = "The checksums collection is being set up"
+	}
+	existingCondition := meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetInitialized)
+	if existingCondition != nil && conditionsEqual(condition, *existingCondition) {
+		condition = *existingCondition
+	}
+	meta.SetStatusCondition(&newStatus.Conditions, condition)
+}
+
+// checksumCollectionStatusOf determines the status of the checksums collection from the cluster status, plus a count
+// of the checksum records of the collection set ...
+func checksumCollectionStatusOf(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus,
+	checksumsCollectionName string) *solrCollectionSet.ChecksumCollectionStatus {
+
+	checksumStatus := &solrCollectionSet.ChecksumCollectionStatus{
+		Name:              checksumsCollectionName,
+		ReplicationStatus: "--",
+	}
+	collection, exists := clusterStatus.Collections[checksumsCollectionName]
+	if !exists {
+		return checksumStatus
+	}
+	checksumStatus.Exists = true
+	checksumStatus.ReplicationStatus = fmt.Sprintf("%d/%d", collection.ManagedReplicaCount(), collection.ReplicationFactor)
+	checksumStatus.Healthy = len(collection.Shards) > 0
+	for _, shard := range collection.Shards {
+		hasLeader := false
+		for _, replica := range shard.Replicas {
+			if replica.State != "active" {
+				checksumStatus.Healthy = false
+			}
+			hasLeader = hasLeader || replica.Leader
+		}
+		if !hasLeader {
+			checksumStatus.Healthy = false
+		}
+	}
+
+	recordCount, err := solrClient.Count(ctx, checksumsCollectionName, checksumsQueryFor(collectionSet))
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("could not count the records of checksums collection [%s]",
+			checksumsCollectionName))
+		return checksumStatus
+	}
+	checksumStatus.RecordCount = &recordCount
+	return checksumStatus
+}
+
+// UpdateConfigSetStatus applies the given config set statuses to the given collection set. Since the config sets
+// were successfully managed the config sets synced condition is set as well, unless config sets referenced by the spec
+// are missing (in which case missingConfigSets describes them) ...
+func (r *SolrCollectionSetReconciler) UpdateConfigSetStatus(ctx context.Context, req ctrl.Request,
+	collectionSet *solrCollectionSet.SolrCollectionSet, configSetStatuses []solrCollectionSet.ConfigSetStatus,
+	missingConfigSets error) error {
+
+	logger := log.FromContext(ctx)
+
+	// Sort the config sets otherwise DeepEqual won't consider them equal ...
+	sort.Slice(configSetStatuses, func(i, j int) bool {
+		return configSetStatuses[i].Name < configSetStatuses[j].Name
+	})
+
+	oldInstance := collectionSet.DeepCopy()
+	statusCopy := oldInstance.Status.DeepCopy()
+	statusCopy.ConfigSets = configSetStatuses
+	syncedCondition := metav1.Condition{
+		Type:    typeSolrCollectionSetConfigSetsSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonSolrCollectionSetConfigSetsSynced,
+		Message: "Config sets in the cluster match the spec",
+	}
+	if missingConfigSets != nil {
+		syncedCondition.Status = metav1.ConditionFalse
+		syncedCondition.Reason = reasonSolrCollectionSetMissingConfigSet
+		syncedCondition.Message = missingConfigSets.Error()
+	}
+	meta.SetStatusCondition(&statusCopy.Conditions, syncedCondition)
+
+	if reflect.DeepEqual(collectionSet.Status, *statusCopy) {
+		return nil
+	}
+
+	collectionSet.Status = *statusCopy
+	err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to save config set status [%s]", collectionSet.Name))
+		return err
+	}
+
+	// Re-fetch the SolrCollectionSet after updating the status
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to re-fetch SolrCollectionSet [%s]", collectionSet.Name))
+		return err
+	}
+
+	return nil
+}
+
+// saveDeleteFailures records the number of times in a row deleting each collection has failed ...
+func (r *SolrCollectionSetReconciler) saveDeleteFailures(ctx context.Context,
+	collectionSet solrCollectionSet.SolrCollectionSet, deleteFailures map[string]int32) {
+
+	logger := log.FromContext(ctx)
+
+	oldInstance := collectionSet.DeepCopy()
+	newInstance := collectionSet.DeepCopy()
+	newInstance.Status.DeleteFailures = deleteFailures
+	if err := r.Status().Patch(ctx, newInstance, client.MergeFrom(oldInstance)); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to save delete failures [%s]", collectionSet.Name))
+	}
+}
+
+// saveDeletionMarks records the collections which are waiting out the cleanup grace period before being deleted ...
+func (r *SolrCollectionSetReconciler) saveDeletionMarks(ctx context.Context,
+	collectionSet solrCollectionSet.SolrCollectionSet, deletionMarks map[string]solrCollectionSet.DeletionMark) {
+
+	logger := log.FromContext(ctx)
+
+	oldInstance := collectionSet.DeepCopy()
+	newInstance := collectionSet.DeepCopy()
+	newInstance.Status.MarkedForDeletion = deletionMarks
+	if err := r.Status().Patch(ctx, newInstance, client.MergeFrom(oldInstance)); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to save collections marked for deletion [%s]", collectionSet.Name))
+	}
+}
+
+// nextDeletionDue works out how long until the grace period of the next collection marked for deletion is up. Ok is
+// false if no grace periods are still running ...
+func nextDeletionDue(deletionMarks map[string]solrCollectionSet.DeletionMark) (wait time.Duration, ok bool) {
+	for _, mark := range deletionMarks {
+		untilDue := time.Until(mark.DeleteAfter.Time)
+		if untilDue > 0 && (!ok || untilDue < wait) {
+			wait = untilDue
+			ok = true
+		}
+	}
+	// Allow for the deadline being truncated to the second ...
+	return wait + time.Second, ok
+}
+
+// requeueAfterChange requeues immediately after Solr was changed so that the change is verified before going on. If
+// that has happened too many times in a row (e.g. because a change never shows up in the cluster status) then the
+// requeue is delayed so as not to hot-loop against Solr. Saving the count updates the status, which queues another
+// reconcile right away, so like the error backoff the delay is enforced when the reconcile starts ...
+func (r *SolrCollectionSetReconciler) requeueAfterChange(ctx context.Context,
+	collectionSet *solrCollectionSet.SolrCollectionSet) (ctrl.Result, error) {
+
+	logger := log.FromContext(ctx)
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.ConsecutiveImmediateRequeues++
+	if err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		// Without the count there's no telling whether this is a hot loop, so err on the side of caution ...
+		logger.Error(err, fmt.Sprintf("failed to save immediate requeue count [%s]", collectionSet.Name))
+		return requeueWithBackoff()
+	}
+	if collectionSet.Status.ConsecutiveImmediateRequeues > maxConsecutiveImmediateRequeues {
+		logger.Info(fmt.Sprintf("Solr has been changed in [%d] reconciles in a row without the cluster settling so "+
+			"delaying the next reconcile", collectionSet.Status.ConsecutiveImmediateRequeues))
+		r.requeueBackoffs.add(client.ObjectKeyFromObject(collectionSet).String(), collectionSet.Generation,
+			time.Second*backoffRequeueSeconds)
+		return requeueWithBackoff()
+	}
+	return requeueImmediately()
+}
+
+// RecordInterruptedOperation records the given operation (and the error it failed with) if it was interrupted because
+// the given context is done, i.e. by the operator shutting down or by the reconcile timing out. The context is done so
+// the status is written using a short-lived context of its own. It does nothing if the context isn't done ...
+func (r *SolrCollectionSetReconciler) RecordInterruptedOperation(ctx context.Context,
+	collectionSet *solrCollectionSet.SolrCollectionSet, operation string, err error) {
+
+	logger := log.FromContext(ctx)
+
+	if ctx.Err() == nil {
+		return
+	}
+	cause := "the operator shutting down"
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		cause = fmt.Sprintf("the reconcile timing out after [%s]", r.ReconcileTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second*interruptedOperationTimeoutSeconds)
+	defer cancel()
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.InterruptedOperation = fmt.Sprintf("%s was interrupted by %s: %s", operation, cause, err.Error())
+	if err := r.Status().Patch(shutdownCtx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to record interrupted operation [%s]", collectionSet.Name))
+	}
+}
+
+// acknowledgeReconcileRequest records the value of the reconcile-now annotation in the status. Once it's recorded the
+// same value doesn't force another reconcile, so the status update that it causes doesn't lead to a loop ...
+func (r *SolrCollectionSetReconciler) acknowledgeReconcileRequest(ctx context.Context,
+	collectionSet *solrCollectionSet.SolrCollectionSet, value string) error {
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.LastReconcileRequest = value
+	return r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance))
+}
+
+// UpdateObservedGeneration records the generation of the given collection set as the generation most recently
+// reconciled. Since the reconcile got all the way through, the counts of consecutive immediate requeues and errors are
+// reset as well (ending any backoff), and any interrupted operation has been finished so it's cleared ...
+func (r *SolrCollectionSetReconciler) UpdateObservedGeneration(ctx context.Context, req ctrl.Request,
+	collectionSet *solrCollectionSet.SolrCollectionSet) error {
+
+	logger := log.FromContext(ctx)
+
+	r.errorBackoffs.reset(req.NamespacedName.String())
+	r.requeueBackoffs.reset(req.NamespacedName.String())
+	if collectionSet.Status.ObservedGeneration == collectionSet.Generation &&
+		collectionSet.Status.ConsecutiveImmediateRequeues == 0 && collectionSet.Status.ConsecutiveErrors == 0 &&
+		collectionSet.Status.InterruptedOperation == "" {
+		return nil
+	}
+
+	if collectionSet.Status.InterruptedOperation != "" {
+		logger.Info("interrupted operation has been finished", "operation", collectionSet.Status.InterruptedOperation)
+	}
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.ObservedGeneration = collectionSet.Generation
+	collectionSet.Status.ConsecutiveImmediateRequeues = 0
+	collectionSet.Status.ConsecutiveErrors = 0
+	collectionSet.Status.InterruptedOperation = ""
+	err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to save observed generation [%s]", collectionSet.Name))
+		return err
+	}
+
+	// Re-fetch the SolrCollectionSet after updating the status
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to re-fetch SolrCollectionSet [%s]", collectionSet.Name))
+		return err
+	}
+
+	return nil
+}
+
 // populateCollectionSetStatus populates a collection set status object ...
 func populateCollectionSetStatus(
 	newStatus *solrCollectionSet.SolrCollectionSetStatus,
@@ -361,9 +1654,44 @@ func populateCollectionSetStatus(
 	// Why isn't the collectionSpec set stable ...
 	unstableReason := ""
 
+	// The collections and replicas each get their own condition. Stable is the aggregate of these (and the config
+	// sets synced condition which is maintained by UpdateConfigSetStatus()) ...
+	collectionsReady := true
+	collectionsReason := reasonSolrCollectionSetCollectionsReady
+	replicasReady := true
+	replicasReason := reasonSolrCollectionSetReplicasReady
+
+	// Config set statuses are maintained by UpdateConfigSetStatus() so carry them forward as-is ...
+	newStatus.ConfigSets = collectionSet.Status.ConfigSets
+	// Likewise, the observed generation is maintained by UpdateObservedGeneration() ...
+	newStatus.ObservedGeneration = collectionSet.Status.ObservedGeneration
+	newStatus.ConsecutiveImmediateRequeues = collectionSet.Status.ConsecutiveImmediateRequeues
+	newStatus.ConsecutiveErrors = collectionSet.Status.ConsecutiveErrors
+	newStatus.DeleteFailures = collectionSet.Status.DeleteFailures
+	newStatus.MarkedForDeletion = collectionSet.Status.MarkedForDeletion
+	// The acknowledged reconcile request is maintained by acknowledgeReconcileRequest(). Dropping it would have the next
+	// reconcile treat the annotation as a new request ...
+	newStatus.LastReconcileRequest = collectionSet.Status.LastReconcileRequest
+	// Likewise, pending operations are maintained by the operations themselves (e.g. SplitShard()) ...
+	newStatus.PendingOperations = collectionSet.Status.PendingOperations
+	// Likewise, the interrupted operation (if any) is recorded by RecordInterruptedOperation() and cleared by
+	// UpdateObservedGeneration() ...
+	newStatus.InterruptedOperation = collectionSet.Status.InterruptedOperation
+
+	newStatus.Aliases = aliasStatusesOf(*collectionSet, clusterStatus,
+		activeInstances(collectionSet.Status.SolrCollections))
+
+	// The replicas on nodes which haven't been missing for long still count, like they do when replicas are adjusted
+	// (see presumedLiveNodes()) ...
+	newStatus.MissingNodes = missingNodesOf(clusterStatus, collectionSet.Status.MissingNodes)
+	liveNodes := presumedLiveNodes(clusterStatus.LiveNodes, newStatus.MissingNodes, replicaRepairDelay(*collectionSet))
+
 	// Set replication factor in the new spec status ...
 	var collectionSetReplicationFactor = *collectionSet.Spec.ReplicationFactor
 	newStatus.ReplicationFactor = collectionSetReplicationFactor
+	if collectionSet.Spec.Replicas != nil {
+		newStatus.Replicas = *collectionSet.Spec.Replicas
+	}
 
 	// Look at the overall status of the collections ...
 	specifiedCollectionCount := countSpecifiedCollections(collectionSet.Spec.Collections, *collectionSet.Spec.BlueGreenEnabled)
@@ -371,6 +1699,7 @@ func populateCollectionSetStatus(
 
 	if specifiedCollectionCount != solrCollectionsCount {
 		isStable = false
+		collectionsReady = false
 		number := abs(int32(specifiedCollectionCount - solrCollectionsCount))
 		// If blue/green is enabled then only count each blue/green as 1 collection ...
 		if *collectionSet.Spec.BlueGreenEnabled {
@@ -378,12 +1707,14 @@ func populateCollectionSetStatus(
 		}
 		if specifiedCollectionCount < solrCollectionsCount {
 			unstableReason = reasonSolrCollectionRemovingCollections
+			collectionsReason = reasonSolrCollectionRemovingCollections
 			events[eventSolrCollectionSetRemovingCollection] =
 				fmt.Sprintf("SolrCollectionSpec [%s] is in namespace [%s] is removing [%d] collections",
 					collectionSet.Name, collectionSet.Namespace, number)
 		}
 		if specifiedCollectionCount > solrCollectionsCount {
 			unstableReason = reasonSolrCollectionAddingCollections
+			collectionsReason = reasonSolrCollectionAddingCollections
 			events[eventSolrCollectionSetAddingCollection] =
 				fmt.Sprintf("SolrCollectionSpec [%s] is in namespace [%s] is adding [%d] collections",
 					collectionSet.Name, collectionSet.Namespace, number)
@@ -398,8 +1729,10 @@ func populateCollectionSetStatus(
 	//
 	// Reverse map the aliases map (collectionSpec->alias) ...
 	var collectionsToAliasesMap = make(map[string]string)
-	for alias, collection := range clusterStatus.Aliases {
-		collectionsToAliasesMap[collection] = alias
+	for alias, collections := range clusterStatus.Aliases {
+		for _, collection := range collections {
+			collectionsToAliasesMap[collection] = alias
+		}
 	}
 
 	// Create a SolrSectionStatus object for each specified collectionSpec with only basic data populated. The rest
@@ -411,19 +1744,29 @@ func populateCollectionSetStatus(
 			for _, suffix := range []string{"_blue", "_green"} {
 				instanceName := collectionName + suffix
 				newItem := newSolrSectionStatus(collectionSpec, instanceName)
+				newItem.TargetReplicas = instanceReplicas(*collectionSet, collectionSpec, instanceName, clusterStatus.Aliases)
 				collectionStatusMap[instanceName] = &newItem
 			}
 		} else {
 			// No blue/green here ...
 			newItem := newSolrSectionStatus(collectionSpec, "")
+			newItem.TargetReplicas = instanceReplicas(*collectionSet, collectionSpec, collectionName, clusterStatus.Aliases)
 			collectionStatusMap[collectionName] = &newItem
 		}
 	}
+	// Map the (enabled) spec collections to find the replication factor each collection should have ...
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
 
 	// This is the word that goes into the scaling status slot on the status object ...
 	scalingStatus := "Stable"
-	// Iterate through the solr collections from the cluster and update the collection status objects ...
-	for name, collection := range clusterStatus.Collections {
+	// The number of replicas in the cluster vs the number called for by the spec, across every shard, so that the
+	// progress of a scale out (which can take several reconciles) shows ...
+	var replicaProgress, replicaTarget int32
+	// Iterate through the solr collections from the cluster and update the collection status objects. They're visited
+	// in order so that the same cluster state always results in the same reasons ...
+	for _, name := range slices.Sorted(maps.Keys(clusterStatus.Collections)) {
+		collection := clusterStatus.Collections[name]
 		// Only count specified collections (collections that the operator itself uses begin with '_') ...
 		if strings.HasPrefix(collection.Name, "_") {
 			continue
@@ -435,63 +1778,166 @@ func populateCollectionSetStatus(
 			var collectionName = name
 			collectionName = strings.TrimSuffix(collectionName, "_blue")
 			collectionName = strings.TrimSuffix(collectionName, "_green")
-			// See if there's an alias pointing to the collectionSpec ...
-			_, exists := collectionsToAliasesMap[collectionName]
+			// See if there's an alias pointing to the collection. The aliases are mapped by the full collection name
+			// (i.e. with the suffix) ...
+			_, exists := collectionsToAliasesMap[name]
 			if !exists {
 				isActive = false
 			}
 		}
 
-		// If the replication factor of the collectionSpec doesn't match the replication factor specified in the set then
+		solrCollectionStatus, hasStatus := collectionStatusMap[name]
+
+		// A disabled collection that still exists (because cleanup isn't enabled) is left alone, so it doesn't affect
+		// the stability of the set ...
+		if hasStatus && solrCollectionStatus.Disabled {
+			solrCollectionStatus.ReplicationFactor = collection.ReplicationFactor
+			solrCollectionStatus.ReplicaCount = collection.ReplicaCount
+			solrCollectionStatus.Active = isActive
+			solrCollectionStatus.Exists = true
+			continue
+		}
+
+		// If the replication factor of the collectionSpec doesn't match the replication factor specified for it then
 		// that means the collectionSpec set is unstable ....
-		if collectionSetReplicationFactor != collection.ReplicationFactor {
+		// Each shard is scaled to the number of replicas in the spec, which is the replication factor unless the spec
+		// says otherwise ...
+		replicationFactor := collectionSetReplicationFactor
+		targetReplicas := collection.ReplicationFactor
+		if spec, exists := specCollectionsMap[name]; exists {
+			replicationFactor = collectionSet.Spec.CollectionReplicationFactor(spec)
+			targetReplicas = instanceReplicas(*collectionSet, spec, name, clusterStatus.Aliases)
+		}
+		if replicationFactor != collection.ReplicationFactor &&
+			collectionSet.Spec.ReconcilesSetting(solrCollectionSet.SolrCollectionSettingReplicationFactor) {
 			isStable = false
 			unstableReason = reasonSolrCollectionReplicationFactorMismatch
+			replicasReady = false
+			replicasReason = reasonSolrCollectionReplicationFactorMismatch
+		}
+
+		// replicationStatus is the number of replicas that are in the cluster vs the number of replicas called for by
+		// the spec. Only replicas of the managed type (i.e. not PULL replicas) are counted against the target. With
+		// more than one shard it's the worst shard that's reported, so that one under-replicated shard isn't hidden ...
+		shardStatuses := shardStatusesOf(collection, targetReplicas, liveNodes)
+		for _, shardStatus := range shardStatuses {
+			replicaProgress += shardStatus.ReplicaCount
+			replicaTarget += shardStatus.TargetReplicas
 		}
+		var replicaCount = collection.ManagedReplicaCount()
+		if worst, exists := worstShard(shardStatuses); exists {
+			replicaCount = worst.ReplicaCount
+		}
+		replicationStatus := fmt.Sprintf("%d/%d", replicaCount, targetReplicas)
 
-		// replicationStatus is the number of replicas called for by the collectionSpec's replication status vs the number
-		// of replicas that are in the cluster ...
-		var replicaCount = collection.ReplicaCount
-		replicationStatus := fmt.Sprintf("%d/%d", replicaCount, collection.ReplicationFactor)
+		// Replicas which aren't active (e.g. down or recovering) can't serve requests, so the set isn't stable even if
+		// the number of replicas is right. Scaling (below) takes precedence as the reason since new replicas recover
+		// as a matter of course ...
+		notActiveReplicaCount := collection.NotActiveReplicaCount()
+		if notActiveReplicaCount > 0 {
+			isStable = false
+			unstableReason = reasonSolrCollectionSetReplicasRecovering
+			replicasReady = false
+			replicasReason = reasonSolrCollectionSetReplicasRecovering
+		}
 
-		if collection.ReplicaCount != collection.ReplicationFactor {
+		// Each shard is compared to the target number of replicas separately. Replicas on nodes that aren't live don't
+		// count, and replacing them is a repair (which AdjustReplicas() reports) rather than a scale out ...
+		for _, shard := range collection.Shards {
+			shardReplicaCount := shard.LiveReplicaCountOfType(collection.ManagedReplicaType(), liveNodes)
+			if shardReplicaCount == targetReplicas {
+				continue
+			}
 			isStable = false
-			if collection.ReplicaCount < collection.ReplicationFactor {
+			replicasReady = false
+			if shardReplicaCount < targetReplicas {
 				scalingStatus = reasonSolrCollectionSetScalingOut
 				unstableReason = reasonSolrCollectionSetScalingOut
+				replicasReason = reasonSolrCollectionSetScalingOut
+				if shardReplicaCount < shard.ReplicaCountOfType(collection.ManagedReplicaType()) {
+					continue
+				}
 				events[eventSolrCollectionSetScaleOut] =
 					fmt.Sprintf("SolrCollectionSpec [%s] is in namespace [%s] is scaling out from [%d] replicas to [%d]",
-						collectionSet.Name, collectionSet.Namespace, collection.ReplicaCount, collection.ReplicationFactor)
+						collectionSet.Name, collectionSet.Namespace, shardReplicaCount, targetReplicas)
 			}
-			if collection.ReplicaCount > collection.ReplicationFactor {
+			if shardReplicaCount > targetReplicas {
 				scalingStatus = reasonSolrCollectionSetScalingIn
 				unstableReason = reasonSolrCollectionSetScalingIn
+				replicasReason = reasonSolrCollectionSetScalingIn
 				events[eventSolrCollectionSetScaleIn] =
 					fmt.Sprintf("SolrCollectionSpec [%s] is in namespace [%s] is scaling in from [%d] replicas to [%d]",
-						collectionSet.Name, collectionSet.Namespace, collection.ReplicaCount, collection.ReplicationFactor)
+						collectionSet.Name, collectionSet.Namespace, shardReplicaCount, targetReplicas)
 			}
 		}
 
-		solrCollectionStatus, exists := collectionStatusMap[name]
-		if !exists {
+		if !hasStatus {
 			continue
 		}
 
 		solrCollectionStatus.ReplicationFactor = collection.ReplicationFactor
 		solrCollectionStatus.ReplicaCount = collection.ReplicaCount
 		solrCollectionStatus.ReplicationStatus = replicationStatus
+		solrCollectionStatus.ReplicasNotActive = notActiveReplicaCount
+		solrCollectionStatus.Shards = shardStatuses
 		solrCollectionStatus.Active = isActive
 		solrCollectionStatus.Exists = true
+		if collection.CreationTimeMillis > 0 {
+			// Truncate to seconds (in local time) to match what comes back from Kubernetes, otherwise DeepEqual would
+			// never consider the statuses equal ...
+			createdAt := metav1.NewTime(time.Unix(collection.CreationTimeMillis/1000, 0).Local())
+			solrCollectionStatus.CreatedAt = &createdAt
+		}
+	}
+
+	// If scaling is paused then say so rather than claiming to be scaling. The replicas still count towards stability
+	// so that it's clear they don't match the spec ...
+	if !*collectionSet.Spec.ScalingEnabled {
+		scalingStatus = reasonSolrCollectionSetScalingPaused
+		if !replicasReady {
+			replicasReason = reasonSolrCollectionSetScalingPaused
+			if unstableReason == reasonSolrCollectionSetScalingOut || unstableReason == reasonSolrCollectionSetScalingIn {
+				unstableReason = reasonSolrCollectionSetScalingPaused
+			}
+		}
+		delete(events, eventSolrCollectionSetScaleOut)
+		delete(events, eventSolrCollectionSetScaleIn)
 	}
 
 	// Set the scaling status (now that the scaling status is known) ...
 	newStatus.ScaleStatus = scalingStatus
 
+	// Keep track of the collections managed by the set. That's the specified collections, plus previously managed
+	// collections which are no longer specified but haven't been cleaned up yet ...
+	newStatus.ManagedCollections = managedCollections(collectionSet.Status.ManagedCollections,
+		maps.Keys(collectionStatusMap), clusterStatus.Collections)
+
 	// Write the collection status object into the status object ...
 	newStatus.SolrCollections = []solrCollectionSet.SolrCollectionStatus{}
 	for _, collectionStatus := range collectionStatusMap {
 		newStatus.SolrCollections = append(newStatus.SolrCollections, *collectionStatus)
 	}
+	// The collections come from a map, so sort them otherwise DeepEqual won't consider the statuses equal (and the
+	// status would be patched on every reconcile). Without blue/green the instance names are empty, so sort by name
+	// first ...
+	sort.Slice(newStatus.SolrCollections, func(i, j int) bool {
+		if newStatus.SolrCollections[i].Name != newStatus.SolrCollections[j].Name {
+			return newStatus.SolrCollections[i].Name < newStatus.SolrCollections[j].Name
+		}
+		return newStatus.SolrCollections[i].InstanceName < newStatus.SolrCollections[j].InstanceName
+	})
+	// The last promotions are maintained by ManageAliases() so carry them forward ...
+	for _, oldCollectionStatus := range collectionSet.Status.SolrCollections {
+		if oldCollectionStatus.LastPromotionTime == nil {
+			continue
+		}
+		for i := range newStatus.SolrCollections {
+			if newStatus.SolrCollections[i].Name == oldCollectionStatus.Name {
+				newStatus.SolrCollections[i].LastPromotedColor = oldCollectionStatus.LastPromotedColor
+				newStatus.SolrCollections[i].LastPromotionTime = oldCollectionStatus.LastPromotionTime
+			}
+		}
+	}
 
 	// Examine conditions ...
 
@@ -501,17 +1947,50 @@ func populateCollectionSetStatus(
 		existingConditions[condition.Type] = condition
 	}
 
+	// The config sets synced condition isn't determined here, but it still counts towards stability ...
+	configSetsCondition, configSetsConditionExists := existingConditions[typeSolrCollectionSetConfigSetsSynced]
+	if configSetsConditionExists {
+		meta.SetStatusCondition(&newStatus.Conditions, configSetsCondition)
+		if configSetsCondition.Status == metav1.ConditionFalse {
+			isStable = false
+			unstableReason = configSetsCondition.Reason
+		}
+	}
+
 	// Fix status ...
 	var stableStatus = metav1.ConditionTrue
 	var stableMessage string
 	if isStable {
 		// It's a stable reason here, but unstable make more sense about everywhere else ...
 		unstableReason = reasonSolrCollectionSetStable
+		// A set without collections is valid, but there's little to see in the status, so say why ...
+		if len(collectionSet.Spec.Collections) == 0 {
+			unstableReason = reasonSolrCollectionSetNoCollectionsSpecified
+			collectionsReason = reasonSolrCollectionSetNoCollectionsSpecified
+			stableMessage = "No collections are specified"
+			// Only emit the event when the set first ends up without collections ...
+			existingCondition := meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetStable)
+			if existingCondition == nil || existingCondition.Reason != reasonSolrCollectionSetNoCollectionsSpecified {
+				events[eventSolrCollectionSetNoCollectionsSpecified] =
+					fmt.Sprintf("SolrCollectionSpec [%s] in namespace [%s] doesn't specify any collections",
+						collectionSet.Name, collectionSet.Namespace)
+			}
+		}
 	} else {
 		stableStatus = metav1.ConditionFalse
 		stableMessage = "Spec and cluster status are not aligned"
 	}
 
+	// Keep track of how long the set has been unstable ...
+	if !isStable {
+		newStatus.UnstableSince = collectionSet.Status.UnstableSince
+		newStatus.UnstableWarningEmitted = collectionSet.Status.UnstableWarningEmitted
+		if newStatus.UnstableSince == nil {
+			unstableSince := metav1.NewTime(time.Now().Truncate(time.Second))
+			newStatus.UnstableSince = &unstableSince
+		}
+	}
+
 	// Make a map of new conditions based on the logic above ...
 	newConditions := make(map[string]metav1.Condition)
 
@@ -521,6 +2000,12 @@ func populateCollectionSetStatus(
 		Reason:  unstableReason,
 		Message: stableMessage,
 	}
+	newConditions[typeSolrCollectionSetCollectionsReady] = readinessCondition(typeSolrCollectionSetCollectionsReady,
+		collectionsReady, collectionsReason, "Collections in the cluster match the spec",
+		"Collections in the cluster do not match the spec")
+	newConditions[typeSolrCollectionSetReplicasReady] = readinessCondition(typeSolrCollectionSetReplicasReady,
+		replicasReady, replicasReason, "Replicas in the cluster match the spec",
+		fmt.Sprintf("Replicas in the cluster do not match the spec [%d/%d]", replicaProgress, replicaTarget))
 
 	// Iterate though the condition that were just formulated and apply the to the status ...
 	for t, condition := range newConditions {
@@ -550,6 +2035,93 @@ func populateCollectionSetStatus(
 	return events
 }
 
+// warnIfUnstableTooLong emits a warning event (with the reason the set is unstable) if the given status has been
+// unstable for longer than the unstable warning threshold. The warning is only emitted once each time the set becomes
+// unstable, which is recorded in the given status ...
+func (r *SolrCollectionSetReconciler) warnIfUnstableTooLong(collectionSet *solrCollectionSet.SolrCollectionSet,
+	status *solrCollectionSet.SolrCollectionSetStatus) {
+	if r.UnstableWarningThreshold <= 0 || status.UnstableSince == nil || status.UnstableWarningEmitted {
+		return
+	}
+	unstableFor := time.Since(status.UnstableSince.Time)
+	if unstableFor < r.UnstableWarningThreshold {
+		return
+	}
+	reason, message := "unknown", ""
+	if stableCondition := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetStable); stableCondition != nil {
+		reason, message = stableCondition.Reason, stableCondition.Message
+	}
+	r.Recorder.Eventf(collectionSet, corev1.EventTypeWarning, eventSolrCollectionSetUnstableTooLong,
+		"SolrCollectionSpec [%s] in namespace [%s] has been unstable for [%s] with reason [%s] [%s]",
+		collectionSet.Name, collectionSet.Namespace, unstableFor.Round(time.Second), reason, message)
+	status.UnstableWarningEmitted = true
+}
+
+// shardStatusesOf determines the status of each active shard of the collection, sorted by name. Like the scaling,
+// only replicas of the managed type on the given live nodes are counted against the target ...
+func shardStatusesOf(collection solr.Collection, targetReplicas int32,
+	liveNodes []string) []solrCollectionSet.ShardStatus {
+
+	var shardStatuses []solrCollectionSet.ShardStatus
+	for _, name := range slices.Sorted(maps.Keys(collection.Shards)) {
+		shard := collection.Shards[name]
+		shardStatus := solrCollectionSet.ShardStatus{
+			Name:           name,
+			ReplicaCount:   shard.LiveReplicaCountOfType(collection.ManagedReplicaType(), liveNodes),
+			TargetReplicas: targetReplicas,
+		}
+		for _, replica := range shard.Replicas {
+			if replica.State != "" && replica.State != solr.ReplicaStateActive {
+				shardStatus.ReplicasNotActive++
+			}
+		}
+		switch {
+		case shardStatus.ReplicaCount < targetReplicas:
+			shardStatus.Health = solrCollectionSet.ShardHealthUnderReplicated
+		case shardStatus.ReplicasNotActive > 0:
+			shardStatus.Health = solrCollectionSet.ShardHealthRecovering
+		case shardStatus.ReplicaCount > targetReplicas:
+			shardStatus.Health = solrCollectionSet.ShardHealthOverReplicated
+		default:
+			shardStatus.Health = solrCollectionSet.ShardHealthHealthy
+		}
+		shardStatuses = append(shardStatuses, shardStatus)
+	}
+	return shardStatuses
+}
+
+// worstShard finds the shard furthest from its target number of replicas. Being short of replicas is worse than
+// having too many, so an under-replicated shard is always picked over an over-replicated one ...
+func worstShard(shardStatuses []solrCollectionSet.ShardStatus) (worst solrCollectionSet.ShardStatus, exists bool) {
+	shortfall := func(shardStatus solrCollectionSet.ShardStatus) int32 {
+		return shardStatus.TargetReplicas - shardStatus.ReplicaCount
+	}
+	isWorse := func(candidate int32, current int32) bool {
+		if (candidate > 0) != (current > 0) {
+			return candidate > 0
+		}
+		if candidate > 0 {
+			return candidate > current
+		}
+		return candidate < current
+	}
+	for _, shardStatus := range shardStatuses {
+		if !exists || isWorse(shortfall(shardStatus), shortfall(worst)) {
+			worst, exists = shardStatus, true
+		}
+	}
+	return worst, exists
+}
+
+// readinessCondition creates a condition of the given type whose status and message depend on whether it's ready ...
+func readinessCondition(conditionType string, ready bool, reason string, readyMessage string,
+	notReadyMessage string) metav1.Condition {
+	if ready {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: reason, Message: readyMessage}
+	}
+	return metav1.Condition{Type: conditionType, Status: metav1.ConditionFalse, Reason: reason, Message: notReadyMessage}
+}
+
 // newSolrSectionStatus creates and instance of SolrCollectionStatus only data from the spec ...
 func newSolrSectionStatus(collectionSpec solrCollectionSet.SolrCollection, instanceName string) solrCollectionSet.SolrCollectionStatus {
 	var isBlueGreen bool
@@ -567,6 +2139,7 @@ func newSolrSectionStatus(collectionSpec solrCollectionSet.SolrCollection, insta
 		ReplicaCount:      0,
 		BlueGreen:         isBlueGreen,
 		ReplicationStatus: "--",
+		Disabled:          collectionSpec.IsDisabled(),
 	}
 }
 
@@ -578,14 +2151,26 @@ func conditionsEqual(c1 metav1.Condition, c2 metav1.Condition) (isEqual bool) {
 	return isEqual
 }
 
-// AdjustReplicas adjusts the number of Solr replicas to match the spec ...
-func (r *SolrCollectionSetReconciler) AdjustReplicas(ctx context.Context,
+// AdjustReplicas adjusts the number of Solr replicas to match the spec. Replication factor changes happen in two
+// steps: ManageCollections() records the new replication factor with MODIFYCOLLECTION (which doesn't add or remove
+// replicas) and then, on a later reconcile, this adds or removes replicas until each shard matches it. Only replicas
+// on the given live nodes are counted, so the replicas lost with a node that has died are replaced (i.e. repaired).
+// isScaling is true if more replicas still have to be added on a later reconcile, either because Solr is waiting on
+// nodes or because the spec's scaleOutBatchSize was used up ...
+func (r *SolrCollectionSetReconciler) AdjustReplicas(ctx context.Context, solrClient solr.SolrClient,
 	collectionSet solrCollectionSet.SolrCollectionSet,
 	solrCollections map[string]solr.Collection,
+	aliases map[string][]string,
+	liveNodes []string,
 	checksumCollectionName string) (isScaling bool, err error) {
 
 	logger := log.FromContext(ctx)
 
+	if !*collectionSet.Spec.ScalingEnabled {
+		logger.Info("not checking replicas since scaling is paused")
+		return false, nil
+	}
+
 	logger.Info("checking replicas")
 
 	// Map the spec collections so that the blue/green collections are included ...
@@ -594,71 +2179,186 @@ func (r *SolrCollectionSetReconciler) AdjustReplicas(ctx context.Context,
 
 	// Iterate the collections defined in the Kube spec and determine what updates need to be made to the replica counts
 	var adjustReplicas = make(map[string]solr.ReplicationAdjustment)
-	for collectionName := range specCollectionsMap {
+	for collectionName, spec := range specCollectionsMap {
 		collection, exists := solrCollections[collectionName]
+		replicationFactor := collectionSet.Spec.CollectionReplicationFactor(spec)
 		if !exists {
 			logger.Error(fmt.Errorf("couldn't find collection [%s]", collectionName), "")
+		} else if r.pendingDeletions.isPending(collectionName) {
+			logger.Info(fmt.Sprintf("not adjusting replicas of collection [%s] since its deletion is in-flight", collectionName))
+		} else if collection.ReplicationFactor != replicationFactor &&
+			collectionSet.Spec.ReconcilesSetting(solrCollectionSet.SolrCollectionSettingReplicationFactor) {
+			// MODIFYCOLLECTION only changes the replication factor recorded by Solr, it doesn't add or remove replicas.
+			// ManageCollections() updates the recorded factor first and replicas are only adjusted once that's done, so
+			// the two steps never work against each other ...
+			logger.Info(fmt.Sprintf("not adjusting replicas of collection [%s] until its replication factor is updated",
+				collectionName))
 		} else {
-			queueReplicaAdjustment(collection, *collectionSet.Spec.ReplicationFactor, adjustReplicas, logger)
+			queueReplicaAdjustment(collection, instanceReplicas(collectionSet, spec, collectionName, aliases),
+				liveNodes, adjustReplicas, logger)
 		}
 	}
 
-	// Check the checksums collection explicitly (since it isn't in the spec) ....
+	// Check the checksums collection explicitly (since it isn't in the spec). Like the other collections its replicas
+	// are only adjusted once ManageCollections() has updated its replication factor ...
 	checksumCollection, exists := solrCollections[checksumCollectionName]
 	if exists {
-		queueReplicaAdjustment(checksumCollection, *collectionSet.Spec.ReplicationFactor, adjustReplicas, logger)
+		replicationFactor := checksumsReplicationFactor(collectionSet, checksumCollection)
+		if checksumCollection.ReplicationFactor != replicationFactor &&
+			collectionSet.Spec.ReconcilesSetting(solrCollectionSet.SolrCollectionSettingReplicationFactor) {
+			logger.Info(fmt.Sprintf("not adjusting replicas of collection [%s] until its replication factor is updated",
+				checksumCollectionName))
+		} else {
+			queueReplicaAdjustment(checksumCollection, replicationFactor, liveNodes, adjustReplicas, logger)
+		}
 	} else {
 		logger.Error(fmt.Errorf("couldn't find the checksum collection [%s]", checksumCollectionName), "")
 	}
 
-	for collection, adjustment := range adjustReplicas {
+	// Large scale outs can be spread over several reconciles (see ScaleOutBatchSize), so the adjustments are made in
+	// order to keep working on the same shards from one reconcile to the next ...
+	var addBudget int32 = -1
+	if collectionSet.Spec.ScaleOutBatchSize != nil {
+		addBudget = *collectionSet.Spec.ScaleOutBatchSize
+	}
+	for _, key := range slices.Sorted(maps.Keys(adjustReplicas)) {
+		adjustment := adjustReplicas[key]
 		var diff = adjustment.TargetCount - adjustment.CurrentCount
 		if diff > 0 {
-			isScaling, err := solrClient.AddReplicas(ctx, collection, diff)
-			if isScaling {
+			if addBudget == 0 {
+				logger.Info(fmt.Sprintf("not adding replicas to collection [%s] shard [%s] until the next reconcile "+
+					"since the scale out batch size has been used up", adjustment.Collection, adjustment.Shard))
+				isScaling = true
+				continue
+			}
+			if addBudget > 0 && diff > addBudget {
+				logger.Info(fmt.Sprintf("only adding [%d] of the [%d] replicas missing from collection [%s] shard [%s] "+
+					"in this reconcile", addBudget, diff, adjustment.Collection, adjustment.Shard))
+				diff = addBudget
+				isScaling = true
+			}
+			if addBudget > 0 {
+				addBudget -= diff
+			}
+			if adjustment.Repair {
+				r.Recorder.Eventf(&collectionSet, corev1.EventTypeWarning, eventSolrCollectionSetReplicaRepair,
+					"Adding [%d] replicas to collection [%s] shard [%s] to replace replicas on nodes that aren't live",
+					diff, adjustment.Collection, adjustment.Shard)
+			}
+			waitingOnNodes, err := solrClient.AddReplicas(ctx, adjustment.Collection, adjustment.Shard,
+				adjustment.ReplicaType, diff, collectionSet.Spec.CreateNodeSet, collectionSet.Spec.PlacementPolicy)
+			if waitingOnNodes {
 				return true, nil
 			} else {
 				if err != nil {
-					return false, err
+					return false, fmt.Errorf("add [%d] replicas to collection [%s] shard [%s]: %w",
+						diff, adjustment.Collection, adjustment.Shard, err)
 				}
 			}
 		} else {
-			err := solrClient.RemoveReplicas(ctx, collection, abs(diff))
-			if err != nil {
-				return false, err
+			// Choose the replicas to remove rather than letting Solr pick (it may pick leaders) ...
+			shard := solrCollections[adjustment.Collection].Shards[adjustment.Shard]
+			for _, replica := range chooseReplicasToRemove(shard, adjustment.ReplicaType, abs(diff)) {
+				logger.Info(fmt.Sprintf("removing replica [%s] on node [%s] from collection [%s] shard [%s]",
+					replica.Name, replica.Node, adjustment.Collection, adjustment.Shard))
+				err := solrClient.RemoveReplica(ctx, adjustment.Collection, adjustment.Shard, replica.Name)
+				if err != nil {
+					return false, fmt.Errorf("remove replica [%s] from collection [%s] shard [%s]: %w",
+						replica.Name, adjustment.Collection, adjustment.Shard, err)
+				}
 			}
 		}
 	}
-	return false, nil
+	return isScaling, nil
 }
 
-// queueReplicaAdjustment deals with adding replica adjustments to the queue ...
-func queueReplicaAdjustment(collection solr.Collection, collectionSetReplicationFactor int32,
-	adjustReplicasMap map[string]solr.ReplicationAdjustment, logger logr.Logger) {
+// chooseReplicasToRemove picks the given number of replicas of the given type to remove from a shard. Replicas that
+// aren't active go first, then non-leaders, then replicas on the nodes with the highest ordinal (since those are the
+// nodes a StatefulSet removes first when it's scaled down) ...
+func chooseReplicasToRemove(shard solr.Shard, replicaType string, count int32) []solr.Replica {
+	var candidates []solr.Replica
+	for _, replica := range shard.Replicas {
+		if replica.Type == replicaType {
+			candidates = append(candidates, replica)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if aActive, bActive := a.State == "active", b.State == "active"; aActive != bActive {
+			return !aActive
+		}
+		if a.Leader != b.Leader {
+			return !a.Leader
+		}
+		return nodeOrdinal(a.Node) > nodeOrdinal(b.Node)
+	})
+	if int(count) < len(candidates) {
+		candidates = candidates[:count]
+	}
+	return candidates
+}
+
+// nodeOrdinal finds the StatefulSet ordinal of a Solr node name (e.g. 2 for solr-2.solr-headless:8983_solr). Returns
+// -1 if the node name doesn't have one ...
+func nodeOrdinal(node string) int {
+	host, _, _ := strings.Cut(node, ":")
+	host, _, _ = strings.Cut(host, ".")
+	index := strings.LastIndex(host, "-")
+	if index < 0 {
+		return -1
+	}
+	ordinal, err := strconv.Atoi(host[index+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
 
-	adjustment := collectionSetReplicationFactor - collection.ReplicaCount
-	if adjustment != 0 {
-		var msg strings.Builder
-		msg.WriteString(fmt.Sprintf("collection %s replication factor is %d and replica count is %d",
-			collection.Name, collectionSetReplicationFactor, collection.ReplicaCount))
+// queueReplicaAdjustment deals with adding replica adjustments to the queue. Each active shard of the collection is
+// adjusted separately. Only replicas on the given live nodes are counted (see solr.Shard.LiveReplicaCountOfType()) ...
+func queueReplicaAdjustment(collection solr.Collection, targetReplicas int32, liveNodes []string,
+	adjustReplicasMap map[string]solr.ReplicationAdjustment, logger logr.Logger) {
 
-		var action = "add"
-		if adjustment < 0 {
-			action = "remove"
-		}
-		msg.WriteString(fmt.Sprintf(" so queueing action to %s %d replicas", action, abs(adjustment)))
-		logger.Info(msg.String())
+	// Only replicas of the managed type are compared to the target, otherwise collections with a mix of
+	// replica types would never converge ...
+	replicaType := collection.ManagedReplicaType()
+	for shardName, shard := range collection.Shards {
+		replicaCount := shard.LiveReplicaCountOfType(replicaType, liveNodes)
+		adjustment := targetReplicas - replicaCount
+		// Replicas are being repaired (rather than scaled out) if some were lost with their nodes ...
+		repair := adjustment > 0 && replicaCount < shard.ReplicaCountOfType(replicaType)
+		if adjustment != 0 {
+			var msg strings.Builder
+			msg.WriteString(fmt.Sprintf("collection %s shard %s target replica count is %d and %s replica count is %d",
+				collection.Name, shardName, targetReplicas, replicaType, replicaCount))
+			if repair {
+				msg.WriteString(fmt.Sprintf(" (not counting %d on nodes that aren't live)",
+					shard.ReplicaCountOfType(replicaType)-replicaCount))
+			}
 
-		adjustReplicasMap[collection.Name] = solr.ReplicationAdjustment{
-			CurrentCount: collection.ReplicaCount,
-			TargetCount:  collectionSetReplicationFactor,
+			var action = "add"
+			if adjustment < 0 {
+				action = "remove"
+			}
+			msg.WriteString(fmt.Sprintf(" so queueing action to %s %d replicas", action, abs(adjustment)))
+			logger.Info(msg.String())
+
+			adjustReplicasMap[collection.Name+"/"+shardName] = solr.ReplicationAdjustment{
+				Collection:   collection.Name,
+				Shard:        shardName,
+				CurrentCount: replicaCount,
+				TargetCount:  targetReplicas,
+				ReplicaType:  replicaType,
+				Repair:       repair,
+			}
 		}
 	}
 }
 
-// ManageConfigSets manages Solr schema config sets ....
-func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, collectionSet solrCollectionSet.SolrCollectionSet,
-	checksumCollectionName string) error {
+// ManageConfigSets manages Solr schema config sets and returns the resulting status of each config set. If
+// forceResync is true every config set is uploaded whether or not its checksum matches ....
+func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, checksumCollectionName string, forceResync bool) ([]solrCollectionSet.ConfigSetStatus, error) {
 
 	logger := log.FromContext(ctx)
 
@@ -667,27 +2367,27 @@ func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, coll
 	// Get the config sets from the Solr cluster ...
 	var solrConfigSets, err = solrClient.GetConfigSets(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Read the Kubernetes configmaps which contain the Solr config sets (aka schemas) ...
 	configMapList := &corev1.ConfigMapList{}
 	// label selection criteria ...
 	selectorLabels := make(map[string]string)
-	selectorLabels["collectionSet"] = collectionSet.Name
+	selectorLabels[configMapLabelCollectionSet] = collectionSet.Name
 	selector := labels.SelectorFromSet(selectorLabels)
 	listOps := &client.ListOptions{
 		Namespace:     collectionSet.Namespace,
 		LabelSelector: selector,
 	}
 	if err := r.List(ctx, configMapList, listOps); err != nil {
-		return err
+		return nil, err
 	}
 	// Map the configmaps that came from Kubernetes by the collection name label ...
 	configMaps := map[string]corev1.ConfigMap{}
 	for _, cm := range configMapList.Items {
-		var name, exists = cm.Labels["collection"]
-		if !exists {
-			return fmt.Errorf("config set configmap [%s] has no 'collection' label", cm.Name)
+		name, err := configSetNameOf(cm)
+		if err != nil {
+			return nil, err
 		}
 		configMaps[name] = cm
 	}
@@ -695,25 +2395,44 @@ func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, coll
 	// Grab the config set checksums from Solr to determine whether they have changed.
 	// If this is the early in the management process then there may not be any in Solr as they get created when the
 	// config set is created (obviously?)...
-	checksumsResponse, err := solrClient.Query(ctx, checksumCollectionName, "*:*")
+	// If the checksums collection can't be queried (e.g. it's unhealthy) then the checksums are treated as unknown. That
+	// causes every config set to be re-uploaded, which is safe, rather than failing the whole reconcile ...
+	checksumsUnavailable := false
+	// If configured, the checksums are read from (and written to) the leader so that a checksum is never read stale
+	// right after it was written ...
+	checksumsResponse, err := solrClient.Query(ctx, checksumCollectionName, checksumsQueryFor(collectionSet),
+		r.SolrChecksumsFromLeader)
 	if err != nil {
-		return err
+		logger.Error(err, fmt.Sprintf("could not query checksums collection [%s] so treating checksums as unknown",
+			checksumCollectionName))
+		checksumsUnavailable = true
 	}
+	// A malformed record is skipped, which means its config set gets re-uploaded (and the record rewritten) ...
 	var configSetChecksums = make(map[string]string)
-	for _, rec := range checksumsResponse {
-		var collection = rec["collection"]
-		var checksum = rec["checksum"]
-		configSetChecksums[collection.(string)] = checksum.(string)
+	for _, doc := range checksumsResponse {
+		rec, err := parseChecksumRecord(doc)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("skipping malformed record in checksums collection [%s]",
+				checksumCollectionName))
+			continue
+		}
+		configSetChecksums[rec.Collection] = rec.Checksum
 	}
 
 	// Iterate through the config maps and determine what actions need to be taken to bring Solr in line with the
 	// Kubernetes spec ...
 	var configMapsToUpload = map[string]corev1.ConfigMap{}
 	var configMapsToRemove = map[string]string{} // this doesn't strictly have to be a map, but it's a little easier
+	var verifyTimes = make(map[string]metav1.Time)
 
+	if forceResync {
+		logger.Info("re-uploading all config sets since a re-sync was requested")
+	}
 	for name, configMap := range configMaps {
 		exists := contains(solrConfigSets, name)
-		if !exists {
+		if forceResync {
+			configMapsToUpload[name] = configMap
+		} else if !exists {
 			logger.Info(fmt.Sprintf("queueing config set [%s] for create", name))
 			configMapsToUpload[name] = configMap
 		} else {
@@ -731,6 +2450,26 @@ func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, coll
 					addToUpdate = true
 				}
 			}
+			// If the checksums match the configmap hasn't changed, but the config set may have been changed in Solr
+			// out-of-band, so compare what's actually in Solr if asked to. That means downloading the config set, so
+			// it's only done every ConfigSetVerifyInterval ...
+			if !addToUpdate && *collectionSet.Spec.VerifyConfigSets && r.configSetVerifyDue(collectionSet, name) {
+				var drifted bool
+				configSetDecoded, err := r.decodeConfigSet(configSetSpec.Data["configset"])
+				if err != nil {
+					err = fmt.Errorf("could not decode config set %s: %w", name, err)
+				} else {
+					drifted, err = configSetDrifted(ctx, solrClient, name, configSetDecoded)
+				}
+				if err != nil {
+					logger.Error(err, fmt.Sprintf("could not verify config set %s in Solr", name))
+				} else if drifted {
+					logger.Info(fmt.Sprintf("config set %s in Solr differs from configmap %s", name, configMap.Name))
+					addToUpdate = true
+				} else {
+					verifyTimes[name] = metav1.Now()
+				}
+			}
 			if addToUpdate {
 				logger.Info(fmt.Sprintf("queueing config set %s for update", name))
 				configMapsToUpload[name] = configMap
@@ -739,35 +2478,84 @@ func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, coll
 	}
 
 	// If cleanup is enabled iterate through the Solr config sets and flag the ones for delete which aren't in the spec
-	// (except the ones that are defined outside the Kubernetes spec i.e. are prefixed with "_")
+	// (except the ones that are defined outside the Kubernetes spec i.e. are prefixed with "_"). Only the config sets
+	// this collection set uploaded (i.e. that it has checksum records for, or that are in its status) are removed, since
+	// the others may belong to other collection sets using the cluster (e.g. a config set uploaded just before its
+	// collection is created) ...
 	if *collectionSet.Spec.CleanupEnabled {
+		var uploaded = make(map[string]bool)
+		for name := range configSetChecksums {
+			uploaded[name] = true
+		}
+		for _, configSetStatus := range collectionSet.Status.ConfigSets {
+			uploaded[configSetStatus.Name] = true
+		}
 		for _, name := range solrConfigSets {
 			_, exists := configMaps[name]
-			if !exists && !strings.HasPrefix(name, "_") {
+			// The checksums config set is shared by every collection set so be explicit about never removing it ...
+			if !exists && uploaded[name] && !strings.HasPrefix(name, "_") && name != configChecksumsConfigSetName &&
+				name != configSharedChecksumsConfigSetName {
 				configMapsToRemove[name] = name
 			}
 		}
 	}
 
 	// Process uploads ...
+	var uploadTimes = make(map[string]metav1.Time)
 	for collection, configMap := range configMapsToUpload {
 		configsetEncoded := configMap.Data["configset"]
-		configsetDecoded, err := base64.StdEncoding.DecodeString(configsetEncoded)
+		// Decode the config set before uploading it so bad base64 data fails before anything is sent to Solr (rather
+		// than part way through the upload) and the zip can be sent with its length ...
+		configsetDecoded, err := r.decodeConfigSet(configsetEncoded)
 		if err != nil {
-			return fmt.Errorf("could not base64 decode 'configset' property on configmap %s for collection %s", configMap.Name, collection)
+			return nil, fmt.Errorf("could not decode configset %s from configmap %s: %w", collection, configMap.Name, err)
 		}
-		err = solrClient.UploadConfigSet(ctx, collection, configsetDecoded)
+		err = solrClient.UploadConfigSet(ctx, collection, bytes.NewReader(configsetDecoded), int64(len(configsetDecoded)))
 		if err != nil {
-			return fmt.Errorf("could not upload configset %s", collection)
+			return nil, fmt.Errorf("could not upload configset %s from configmap %s: %w", collection, configMap.Name, err)
 		}
 		// Write the checksum to Solr ...
 		var rec = fmt.Sprintf(`{
 			"collection": "%s",
 			"checksum": "%s"
 		}`, collection, checksum(configsetEncoded))
-		err = solrClient.WriteRecord(ctx, checksumCollectionName, rec)
+		if *collectionSet.Spec.SharedChecksums {
+			setId := checksumsSetId(collectionSet)
+			rec = fmt.Sprintf(`{
+			"id": "%s/%s",
+			"set": "%s",
+			"collection": "%s",
+			"checksum": "%s"
+		}`, setId, collection, setId, collection, checksum(configsetEncoded))
+		}
+		err = solrClient.WriteRecord(ctx, checksumCollectionName, rec, r.SolrChecksumsFromLeader)
+		if err != nil {
+			if !checksumsUnavailable {
+				return nil, fmt.Errorf("could not write checksum to %s for collection %s", checksumCollectionName, collection)
+			}
+			// The checksums collection is already known to be unhealthy, so the config set will just get re-uploaded
+			// on the next reconcile ...
+			logger.Error(err, fmt.Sprintf("could not write checksum to %s for collection %s", checksumCollectionName, collection))
+		}
+		r.Recorder.Eventf(&collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetConfigSetUploaded,
+			"SolrCollectionSpec [%s] in namespace [%s] uploaded config set [%s]",
+			collectionSet.Name, collectionSet.Namespace, collection)
+		uploadTimes[collection] = metav1.Now()
+	}
+
+	// Never remove a config set a collection is still using (e.g. an older version of a config set which the inactive
+	// color hasn't been moved off yet). Solr would refuse anyway, which would fail the reconcile ...
+	if len(configMapsToRemove) > 0 {
+		allCollections, err := solrClient.GetClusterStatus(ctx)
 		if err != nil {
-			return fmt.Errorf("could not write checksum to %s for collection %s", checksumCollectionName, collection)
+			return nil, err
+		}
+		for collectionName, collection := range allCollections.Collections {
+			if _, exists := configMapsToRemove[collection.ConfigName]; exists {
+				logger.Info(fmt.Sprintf("not removing config set [%s] since collection [%s] still uses it",
+					collection.ConfigName, collectionName))
+				delete(configMapsToRemove, collection.ConfigName)
+			}
 		}
 	}
 
@@ -775,103 +2563,249 @@ func (r *SolrCollectionSetReconciler) ManageConfigSets(ctx context.Context, coll
 	for name := range configMapsToRemove {
 		err := solrClient.DeleteConfigSet(ctx, name)
 		if err != nil {
-			return fmt.Errorf("could not clean up config set [%s]", name)
+			return nil, fmt.Errorf("could not clean up config set [%s]", name)
 		}
+		r.Recorder.Eventf(&collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetConfigSetRemoved,
+			"SolrCollectionSpec [%s] in namespace [%s] removed config set [%s]",
+			collectionSet.Name, collectionSet.Namespace, name)
 	}
 
-	return nil
+	// Record the status of each of the config sets. At this point Solr has the specified config set, so the checksum
+	// of the spec is also the checksum in Solr. Carry forward the last updated (and verified) times for config sets
+	// that weren't uploaded (or verified) during this reconcile. An upload also counts as a verification ...
+	var previousStatuses = make(map[string]solrCollectionSet.ConfigSetStatus)
+	for _, configSetStatus := range collectionSet.Status.ConfigSets {
+		previousStatuses[configSetStatus.Name] = configSetStatus
+	}
+	var configSetStatuses = make([]solrCollectionSet.ConfigSetStatus, 0, len(configMaps))
+	for name, configMap := range configMaps {
+		configSetStatus := solrCollectionSet.ConfigSetStatus{
+			Name:     name,
+			Checksum: checksum(configMap.Data["configset"]),
+		}
+		if uploadTime, exists := uploadTimes[name]; exists {
+			configSetStatus.LastUpdated = &uploadTime
+			configSetStatus.LastVerified = &uploadTime
+		} else if previousStatus, exists := previousStatuses[name]; exists {
+			configSetStatus.LastUpdated = previousStatus.LastUpdated
+			configSetStatus.LastVerified = previousStatus.LastVerified
+		}
+		if verifyTime, exists := verifyTimes[name]; exists {
+			configSetStatus.LastVerified = &verifyTime
+		}
+		configSetStatuses = append(configSetStatuses, configSetStatus)
+	}
+
+	return configSetStatuses, nil
 }
 
-// ManageCollections manages collections ...
-func (r *SolrCollectionSetReconciler) ManageCollections(ctx context.Context,
+// ManageCollections manages collections. New collections which are verified to be active are added to the given
+// solrCollections ...
+func (r *SolrCollectionSetReconciler) ManageCollections(ctx context.Context, solrClient solr.SolrClient,
 	collectionSet solrCollectionSet.SolrCollectionSet, solrCollections map[string]solr.Collection,
-	aliases map[string]string) (changed bool) {
+	aliases map[string][]string) (changed bool) {
 
 	logger := log.FromContext(ctx)
 
 	logger.Info("checking collections")
 
 	// Read spec data into variables for code readability ...
-	replicationFactor := collectionSet.Spec.ReplicationFactor
+	autoAddReplicas := collectionSet.Spec.AutoAddReplicas
 	isBlueGreenEnabled := collectionSet.Spec.BlueGreenEnabled
 	isCleanupEnabled := collectionSet.Spec.CleanupEnabled
 
-	// Reverse map the aliases map (collection->aliases). This is used down in the delete collection section ...
-	var collectionsToAliasesMap = make(map[string]string)
-	for alias, collection := range aliases {
-		collectionsToAliasesMap[collection] = alias
-	}
-
 	// Determine which collections need to be created.
 	// Map the collections collectionSet for easy access
 	// Create _blue/_green entries if isBlueGreenEnabled is true. Otherwise, just use the plain collection name.
 	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
 	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *isBlueGreenEnabled)
 
+	// collections waiting out the cleanup grace period, mapped by collection name ...
+	var deletionMarks map[string]solrCollectionSet.DeletionMark
+
 	// maps of collection actions to take ...
 	var createCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
 	var deleteAliasesMap = make(map[string]string)
+	var assignAliasesMap = make(map[string][]string)
 	var deleteCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
-	var adjustReplicationFactorMap = make(map[string]solr.Collection)
+	var adjustReplicationFactorMap = make(map[string]int32)
+	var adjustAutoAddReplicasMap = make(map[string]solr.Collection)
+	var adjustConfigSetMap = make(map[string]string)
 
 	// Iterate through the specs and see if the collection exists in Solr. If not add it to the "create" map ...
 	for collectionName, spec := range specCollectionsMap {
 		_, exists := solrCollections[collectionName]
 		if !exists {
+			// Don't recreate a collection that was just deleted until Solr has had a chance to finish removing it ...
+			if r.pendingDeletions.isPending(collectionName) {
+				logger.Info(fmt.Sprintf("not creating collection [%s] since its deletion is still in-flight", collectionName))
+				continue
+			}
 			logger.Info(fmt.Sprintf("queueing collection [%s] for create", collectionName))
 			createCollectionsMap[collectionName] = spec
 		}
 	}
 
-	// If cleanup is enabled, iterate though the solrCollections collections and see if they are still specified.
-	// If not add to the "delete" map assuming clean up is enabled
+	// If cleanup is enabled, iterate though the collections managed by the set and see if they are still specified.
+	// If not add to the "delete" map assuming clean up is enabled. Collections which aren't managed by this set (e.g.
+	// they belong to another collection set in the same cluster) are left alone ...
 	if *isCleanupEnabled {
-		for collectionName := range solrCollections {
+		owner := collectionOwner(collectionSet)
+		var cleanupCandidates = make(map[string]bool)
+		for _, collectionName := range collectionSet.Status.ManagedCollections {
+			cleanupCandidates[collectionName] = true
+		}
+		for collectionName, collection := range solrCollections {
+			if collection.HasOwner() && collection.Owner.Uid == owner.Uid {
+				cleanupCandidates[collectionName] = true
+			}
+		}
+		for collectionName := range cleanupCandidates {
+			collection, exists := solrCollections[collectionName]
+			if !exists {
+				continue
+			}
+			// Never remove a collection which is stamped as belonging to another collection set ...
+			if collection.HasOwner() && collection.Owner.Uid != owner.Uid {
+				logger.Info(fmt.Sprintf("not removing collection [%s] since it's owned by collection set [%s/%s]",
+					collectionName, collection.Owner.Namespace, collection.Owner.Name))
+				continue
+			}
 			// if the collection is no longer in the spec then queue for removal (as long as it isn't prefixed with "_") ...
 			spec, exists := specCollectionsMap[collectionName]
 			if !exists && !strings.HasPrefix(collectionName, "_") {
+				// Don't delete a collection again if its deletion is still in-flight ...
+				if r.pendingDeletions.isPending(collectionName) {
+					logger.Info(fmt.Sprintf("collection [%s] deletion is still in-flight", collectionName))
+					continue
+				}
+				// Keep the collection for the grace period (if there is one) in case it was removed from the spec by
+				// mistake. The mark is kept until the collection is gone so that a failed delete doesn't restart it ...
+				if gracePeriod := collectionSet.Spec.CleanupGracePeriodSeconds; gracePeriod != nil && *gracePeriod > 0 {
+					mark, marked := collectionSet.Status.MarkedForDeletion[collectionName]
+					if !marked {
+						markedAt := time.Now().Truncate(time.Second)
+						mark = solrCollectionSet.DeletionMark{
+							MarkedAt:    metav1.NewTime(markedAt),
+							DeleteAfter: metav1.NewTime(markedAt.Add(time.Duration(*gracePeriod) * time.Second)),
+						}
+						logger.Info(fmt.Sprintf("marking collection [%s] for deletion after [%s]", collectionName,
+							mark.DeleteAfter.Format(time.RFC3339)))
+						r.Recorder.Eventf(&collectionSet, corev1.EventTypeWarning,
+							eventSolrCollectionSetCollectionMarkedForDeletion,
+							"SolrCollectionSpec [%s] in namespace [%s] will delete collection [%s] after [%s] unless "+
+								"it's put back in the spec", collectionSet.Name, collectionSet.Namespace, collectionName,
+							mark.DeleteAfter.Format(time.RFC3339))
+					}
+					if deletionMarks == nil {
+						deletionMarks = make(map[string]solrCollectionSet.DeletionMark)
+					}
+					deletionMarks[collectionName] = mark
+					if time.Now().Before(mark.DeleteAfter.Time) {
+						continue
+					}
+				}
 				logger.Info(fmt.Sprintf("queueing collection [%s] for removal", collectionName))
 				deleteCollectionsMap[collectionName] = spec
-				// Check for an alias as that'll have to be cleaned up before the collection can be removed ...
-				alias, exists := collectionsToAliasesMap[collectionName]
-				if exists {
-					logger.Info(fmt.Sprintf("queueing alias [%s] for removal", alias))
-					deleteAliasesMap[collectionName] = alias
+			}
+		}
+
+		// Aliases that target collections queued for removal have to be cleaned up before the collections can be
+		// removed. If an alias also targets other collections then just point it at the remaining collections rather
+		// than removing it ...
+		for alias, targets := range aliases {
+			var remaining []string
+			for _, target := range targets {
+				if _, isDeleted := deleteCollectionsMap[target]; !isDeleted {
+					remaining = append(remaining, target)
 				}
 			}
+			if len(remaining) == len(targets) {
+				continue
+			}
+			if len(remaining) > 0 {
+				logger.Info(fmt.Sprintf("queueing alias [%s] to target collections [%s]", alias, strings.Join(remaining, ", ")))
+				assignAliasesMap[alias] = remaining
+			} else {
+				logger.Info(fmt.Sprintf("queueing alias [%s] for removal", alias))
+				deleteAliasesMap[alias] = alias
+			}
 		}
 	}
+	// Marks of collections that are back in the spec (or gone, or no longer being cleaned up) are dropped ...
+	if !reflect.DeepEqual(deletionMarks, collectionSet.Status.MarkedForDeletion) {
+		r.saveDeletionMarks(ctx, collectionSet, deletionMarks)
+	}
 
 	// Iterate though the solrCollections/existing collections and see if the replication factor needs updating.
 	// (collection that haven't been created yet will automatically get created with the current replication factor)
+	// Settings that aren't reconciled are only applied when collections are created ...
+	reconcilesReplicationFactor := collectionSet.Spec.ReconcilesSetting(solrCollectionSet.SolrCollectionSettingReplicationFactor)
 	for collectionName, collection := range solrCollections {
 		// make sure the collection is part of the collectionSet (and isn't being cleaned up or ignored)
-		_, exists := specCollectionsMap[collectionName]
-		if exists {
-			if collection.ReplicationFactor != *replicationFactor {
+		spec, exists := specCollectionsMap[collectionName]
+		if exists && !r.pendingDeletions.isPending(collectionName) {
+			replicationFactor := collectionSet.Spec.CollectionReplicationFactor(spec)
+			if collection.ReplicationFactor != replicationFactor && reconcilesReplicationFactor {
 				logger.Info(fmt.Sprintf("queueing collection [%s] for replication factor adjustment", collectionName))
-				adjustReplicationFactorMap[collectionName] = collection
+				adjustReplicationFactorMap[collectionName] = replicationFactor
 			}
+			if collection.ConfigName != "" && collection.ConfigName != spec.ConfigsetName {
+				logger.Info(fmt.Sprintf("queueing collection [%s] to change config set from [%s] to [%s]",
+					collectionName, collection.ConfigName, spec.ConfigsetName))
+				adjustConfigSetMap[collectionName] = spec.ConfigsetName
+			}
+			// Newer versions of Solr don't support autoAddReplicas (and don't report it) so only adjust it if it's reported
+			if collection.AutoAddReplicas != nil && *collection.AutoAddReplicas != *autoAddReplicas &&
+				collectionSet.Spec.ReconcilesSetting(solrCollectionSet.SolrCollectionSettingAutoAddReplicas) {
+				logger.Info(fmt.Sprintf("queueing collection [%s] for autoAddReplicas adjustment", collectionName))
+				adjustAutoAddReplicasMap[collectionName] = collection
+			}
+		}
+	}
+
+	// The checksums collection isn't in the spec, but it follows the replication factor of the set (or its own
+	// replication factor if one is given) so that its replicas don't drift from the rest of the cluster. A shared
+	// checksums collection is left alone (see checksumsReplicationFactor()) ...
+	checksumsCollectionName := checksumsCollectionNameFor(collectionSet)
+	if collection, exists := solrCollections[checksumsCollectionName]; exists {
+		replicationFactor := checksumsReplicationFactor(collectionSet, collection)
+		if collection.ReplicationFactor != replicationFactor && reconcilesReplicationFactor {
+			logger.Info(fmt.Sprintf("queueing collection [%s] for replication factor adjustment", checksumsCollectionName))
+			adjustReplicationFactorMap[checksumsCollectionName] = replicationFactor
 		}
 	}
 
-	// Process create collections ...
+	// Process create collections. Each new collection is waited on (up to CollectionCreateWait) so that, once it's
+	// active with all of its replicas, it can be added to the solrCollections and used by the rest of the reconcile
+	// without another pass. Only the collections which couldn't be verified cause a requeue ...
 	if len(createCollectionsMap) > 0 {
 		logger.Info("creating collections", "collections", seqToString(maps.Keys(createCollectionsMap)))
 		for collectionName, collectionSpec := range createCollectionsMap {
-			err := solrClient.CreateCollection(ctx, collectionName, collectionSpec.ConfigsetName, *collectionSet.Spec.ReplicationFactor)
+			collection, ready, err := solrClient.CreateCollectionIfMissing(ctx, collectionName,
+				collectionSpec.ConfigsetName, collectionSet.Spec.CollectionNumShards(collectionSpec),
+				collectionSpec.RouterField, collectionSet.Spec.CollectionReplicationFactor(collectionSpec),
+				*autoAddReplicas, collectionPlacement(collectionSet, collectionSpec), collectionOwner(collectionSet),
+				r.CollectionCreateWait)
 			if err != nil {
 				logger.Error(err, "create collection failed")
 			}
-			// If this is a blue/green then go ahead and create an alias if one doesn't already exist ...
-			if *isBlueGreenEnabled {
-				_, exists := aliases[collectionSpec.Alias]
-				if !exists {
-					err = solrClient.AssignAlias(ctx, collectionSpec.Alias, collectionName)
-					if err != nil {
-						logger.Error(err, "create alias failed")
-					}
-				}
+			if !ready || solrCollections == nil {
+				changed = true
+				continue
+			}
+			logger.Info(fmt.Sprintf("created collection [%s] is active", collectionName))
+			solrCollections[collectionName] = collection
+		}
+	}
+
+	// Process assign aliases ...
+	if len(assignAliasesMap) > 0 {
+		logger.Info("assigning aliases", "aliases", seqToString(maps.Keys(assignAliasesMap)))
+		for alias, collectionNames := range assignAliasesMap {
+			err := solrClient.AssignAlias(ctx, alias, collectionNames)
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("assign alias [%s] failed", alias))
 			}
 		}
 		changed = true
@@ -889,23 +2823,41 @@ func (r *SolrCollectionSetReconciler) ManageCollections(ctx context.Context,
 		changed = true
 	}
 
-	// Process delete collections ...
+	// Process delete collections. A collection that keeps failing to delete (e.g. because of leftover cores) is force
+	// deleted so that it doesn't block the cleanup forever ...
+	var deleteFailures map[string]int32
 	if len(deleteCollectionsMap) > 0 {
 		logger.Info("deleting collections", "collections", seqToString(maps.Keys(deleteCollectionsMap)))
 		for collectionName := range deleteCollectionsMap {
-			err := solrClient.DeleteCollection(ctx, collectionName)
+			failures := collectionSet.Status.DeleteFailures[collectionName]
+			force := failures >= forceDeleteAfterFailures
+			if force {
+				logger.Info(fmt.Sprintf("force deleting collection [%s] since deleting it failed [%d] times",
+					collectionName, failures))
+			}
+			err := solrClient.DeleteCollection(ctx, collectionName, force)
 			if err != nil {
 				logger.Error(err, fmt.Sprintf("delete collection [%s] failed", collectionName))
+				if deleteFailures == nil {
+					deleteFailures = make(map[string]int32)
+				}
+				deleteFailures[collectionName] = failures + 1
+				continue
 			}
+			r.pendingDeletions.add(collectionName)
 		}
 		changed = true
 	}
+	if !reflect.DeepEqual(deleteFailures, collectionSet.Status.DeleteFailures) {
+		r.saveDeleteFailures(ctx, collectionSet, deleteFailures)
+	}
 
-	// Process adjust replication factor ...
+	// Process adjust replication factor. This only updates the replication factor recorded by Solr, AdjustReplicas()
+	// adds or removes the replicas afterward ...
 	if len(adjustReplicationFactorMap) > 0 {
-		logger.Info("adjusting replication factor", "collections", seqToString(maps.Keys(deleteCollectionsMap)))
-		for collectionName := range adjustReplicationFactorMap {
-			err := solrClient.SetReplicationFactor(ctx, collectionName, *replicationFactor)
+		logger.Info("adjusting replication factor", "collections", seqToString(maps.Keys(adjustReplicationFactorMap)))
+		for collectionName, replicationFactor := range adjustReplicationFactorMap {
+			err := solrClient.SetReplicationFactor(ctx, collectionName, replicationFactor)
 			if err != nil {
 				logger.Error(err, "replication factor update on failed")
 			}
@@ -913,205 +2865,1894 @@ func (r *SolrCollectionSetReconciler) ManageCollections(ctx context.Context,
 		changed = true
 	}
 
-	return changed
-}
-
-// makeSolrClient Creates a client for the Solr API ...
-func (r *SolrCollectionSetReconciler) makeSolrClient(ctx context.Context, secretRef string, clusterUrl string) (solrClient solr.SolrClient, error error) {
-	// Query Solr for the actual cluster state ...
-	if secretRef != "" {
-
-		basicAuthSecret := &corev1.Secret{}
-		err := r.Get(ctx, types.NamespacedName{
-			Name:      secretRef,
-			Namespace: "default",
-		}, basicAuthSecret)
+	// Process config set changes. The config sets are normally known to exist by now (see findMissingConfigSets()), but
+	// make sure since pointing a collection at a config set that doesn't exist breaks the collection ...
+	if len(adjustConfigSetMap) > 0 {
+		logger.Info("changing config sets", "collections", seqToString(maps.Keys(adjustConfigSetMap)))
+		solrConfigSets, err := solrClient.GetConfigSets(ctx)
 		if err != nil {
-			return solrClient, fmt.Errorf("could not read the basic auth secret [%s]", secretRef)
+			logger.Error(err, "could not list config sets so not changing any")
+			clear(adjustConfigSetMap)
 		}
-		// Initialize solrClient if it isn't already ...
-		if solrClient == (solr.SolrClient{}) {
-			solrClient = solr.SolrClient{
-				Username: string(basicAuthSecret.Data["username"]),
-				Password: string(basicAuthSecret.Data["password"]),
-				Url:      clusterUrl,
+		for collectionName, configSetName := range adjustConfigSetMap {
+			if !contains(solrConfigSets, configSetName) {
+				logger.Info(fmt.Sprintf("not changing the config set of collection [%s] since config set [%s] doesn't "+
+					"exist", collectionName, configSetName))
+				continue
+			}
+			err := solrClient.SetConfigSet(ctx, collectionName, configSetName)
+			if err == nil {
+				err = solrClient.ReloadCollection(ctx, collectionName)
 			}
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("config set change of collection [%s] failed", collectionName))
+				continue
+			}
+			r.Recorder.Eventf(&collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetConfigSetChanged,
+				"SolrCollectionSpec [%s] in namespace [%s] changed the config set of collection [%s] to [%s]",
+				collectionSet.Name, collectionSet.Namespace, collectionName, configSetName)
+			changed = true
 		}
-	} else {
-		return solrClient, fmt.Errorf("no secret was provided for Solr basic auth")
 	}
-	return solrClient, nil
-}
-
-// checksum calculates the md5 checksum of a string.
-func checksum(data string) string {
-	bytes := []byte(data)
-	hash := md5.Sum(bytes)
-	return hex.EncodeToString(hash[:])
-}
 
-// seqToString Takes a sequence and turns it into a string where the elements are comma delimited
-func seqToString(seq iter.Seq[string]) string {
-	i := 0
-	for range seq {
-		i++
+	// Process adjust autoAddReplicas ...
+	if len(adjustAutoAddReplicasMap) > 0 {
+		logger.Info("adjusting autoAddReplicas", "collections", seqToString(maps.Keys(adjustAutoAddReplicasMap)))
+		for collectionName := range adjustAutoAddReplicasMap {
+			err := solrClient.SetAutoAddReplicas(ctx, collectionName, *autoAddReplicas)
+			if err != nil {
+				logger.Error(err, "autoAddReplicas update failed")
+			}
+		}
+		changed = true
 	}
-	var parts = make([]string, i)
 
-	i = 0
-	for v := range seq {
-		parts[i] = v
-		i++
-	}
-	sort.Strings(parts)
-	return strings.Join(parts, ", ")
+	return changed
 }
 
-// createChecksumCollection creates a checksum config set and collection ...
-func createChecksumCollection(ctx context.Context, checksumsCollectionName string, replicationFactor int32) error {
-	// assume if the collection doesn't exist then the schema doesn't either, so create it ...
-	bytes, err := utils.Zip("checksum_collection_configset", checksumCollectionSchema)
-	if err != nil {
-		return err
-	}
-	err = solrClient.UploadConfigSet(ctx, configChecksumsConfigSetName, bytes)
-	if err != nil {
-		return err
-	}
-	// create the collection
-	err = solrClient.CreateCollection(ctx, checksumsCollectionName, configChecksumsConfigSetName, replicationFactor)
-	if err != nil {
-		return err
-	}
-	return nil
-}
+// ManageRoutedAliases creates the routed aliases in the spec which don't exist yet. Solr manages the collections of a
+// routed alias itself, so once an alias exists it's left alone. Changed is true if any aliases were created ...
+func (r *SolrCollectionSetReconciler) ManageRoutedAliases(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, aliases map[string][]string) (changed bool) {
 
-// mapCollections maps collection to their collection name ...
-func mapCollections(specCollections []solrCollectionSet.SolrCollection,
-	storage map[string]solrCollectionSet.SolrCollection, isBlueGreenEneabled bool) {
-	// Map the collections collectionsSpec for easy access
-	// Create _blue/_green entries if isBlueGreenEnabled is true. Otherwise, just use the plain collection name.
+	logger := log.FromContext(ctx)
 
-	for _, spec := range specCollections {
-		collectionName := spec.Name
-		if isBlueGreenEneabled {
-			storage[collectionName+"_blue"] = spec
-			storage[collectionName+"_green"] = spec
-		} else {
-			storage[collectionName] = spec
+	for _, spec := range collectionSet.Spec.RoutedAliases {
+		if _, exists := aliases[spec.Name]; exists {
+			continue
+		}
+		logger.Info(fmt.Sprintf("creating %s routed alias [%s]", spec.Router, spec.Name))
+		err := solrClient.CreateRoutedAlias(ctx, solr.RoutedAlias{
+			Name:              spec.Name,
+			Router:            spec.Router,
+			Field:             spec.Field,
+			Start:             spec.Start,
+			Interval:          spec.Interval,
+			MaxFutureMs:       spec.MaxFutureMs,
+			MaxCardinality:    spec.MaxCardinality,
+			ConfigSetName:     spec.ConfigsetName,
+			ReplicationFactor: *collectionSet.Spec.ReplicationFactor,
+		})
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("create routed alias [%s] failed", spec.Name))
+			continue
 		}
+		changed = true
 	}
+
+	return changed
 }
 
-// RequeueOnError handles reconcile errors ...
-func (r *SolrCollectionSetReconciler) RequeueOnError(
-	ctx context.Context,
-	req ctrl.Request,
-	collectionSet *solrCollectionSet.SolrCollectionSet,
-	error error) (ctrl.Result, error) {
+// ManageAliases makes sure the alias of each blue/green collection (or of each collection, if createAliasesAlways is
+// set) exists and points at the expected collections. The desired aliases are worked out from the spec and compared
+// with the aliases in Solr, and only the aliases which are missing or point somewhere else are assigned, so nothing is
+// sent to Solr while the aliases are right. Aliases the collection set managed before (i.e. that are in the status),
+// but no longer does (e.g. the alias of a collection was renamed or the collection was disabled) are removed. Aliases
+// of collections that are being cleaned up are removed by ManageCollections(). Changed is true if any aliases were
+// assigned or removed ...
+func (r *SolrCollectionSetReconciler) ManageAliases(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet *solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus, previouslyActive map[string]string) (changed bool) {
 
 	logger := log.FromContext(ctx)
-	logger.Info("requeueing on error")
-
-	// Because an error has been hit, the collection set is no longer stable ...
-	stableCondition := metav1.Condition{
-		Type:    typeSolrCollectionSetStable,
-		Status:  metav1.ConditionFalse,
-		Reason:  reasonSolrCollectionSetReconcileError,
-		Message: error.Error(),
-	}
 
-	// If the new status object and the old status object differ, then apply the changes ...
-	oldInstance := collectionSet.DeepCopy()
-	// Make a copy of the
-	statusCopy := oldInstance.Status.DeepCopy()
-	// Write the conditions into the status object ...
-	meta.SetStatusCondition(&statusCopy.Conditions, stableCondition)
+	desired := desiredAliases(*collectionSet, clusterStatus.Collections, clusterStatus.Aliases, previouslyActive)
+	promotions := colorPromotions(*collectionSet, previouslyActive)
+	var promoted []colorPromotion
+	for _, alias := range slices.Sorted(maps.Keys(desired)) {
+		expected := desired[alias]
+		targets := clusterStatus.Aliases[alias]
+		if sameAliasTargets(targets, expected) {
+			continue
+		}
 
-	// If anything changed then write out the new status. This will cause a call to Reconcile() to be queued for
-	// immediate processing.
-	if !reflect.DeepEqual(collectionSet.Status, *statusCopy) {
-		collectionSet.Status = *statusCopy
-		err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance))
+		if len(targets) == 0 {
+			logger.Info(fmt.Sprintf("creating alias [%s] targeting [%s]", alias, strings.Join(expected, ", ")))
+		} else {
+			logger.Info(fmt.Sprintf("alias [%s] points at [%s] rather than [%s] so repointing it",
+				alias, strings.Join(targets, ", "), strings.Join(expected, ", ")))
+		}
+		err := solrClient.AssignAlias(ctx, alias, expected)
 		if err != nil {
-			logger.Error(err, fmt.Sprintf("failed to save collection set status [%s]", collectionSet.Name))
-			return requeueWithBackoff()
+			logger.Error(err, fmt.Sprintf("assign alias [%s] failed", alias))
+			continue
 		}
-		// Re-fetch the SolrCollectionSet after updating the status
-		if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
-			logger.Error(err, fmt.Sprintf("failed to re-fetch SolrCollectionSet [%s]", collectionSet.Name))
-			return requeueWithBackoff()
+		if promotion, exists := promotions[alias]; exists && slices.Equal(expected, []string{promotion.toInstance()}) {
+			r.Recorder.Eventf(collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetPromotedColor,
+				"SolrCollectionSpec [%s] in namespace [%s] promoted collection [%s] from [%s] to [%s] as requested by "+
+					"activeColor in generation [%d]", collectionSet.Name, collectionSet.Namespace, promotion.collection,
+				promotion.from, promotion.to, collectionSet.Generation)
+			promoted = append(promoted, promotion)
+		} else if len(targets) > 0 {
+			r.Recorder.Eventf(collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetAliasDriftCorrected,
+				"SolrCollectionSpec [%s] in namespace [%s] repointed alias [%s] from [%s] to [%s]",
+				collectionSet.Name, collectionSet.Namespace, alias, strings.Join(targets, ", "),
+				strings.Join(expected, ", "))
 		}
+		changed = true
+	}
+	if len(promoted) > 0 {
+		r.savePromotions(ctx, *collectionSet, promoted)
 	}
 
-	return requeue()
+	managed := managedAliasNames(*collectionSet, desired)
+	for _, aliasStatus := range collectionSet.Status.Aliases {
+		alias := aliasStatus.Name
+		if _, exists := clusterStatus.Aliases[alias]; !exists || managed[alias] {
+			continue
+		}
+		logger.Info(fmt.Sprintf("removing alias [%s] since it's no longer managed by the collection set", alias))
+		err := solrClient.DeleteAlias(ctx, alias)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("delete alias [%s] failed", alias))
+			continue
+		}
+		r.Recorder.Eventf(collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetAliasRemoved,
+			"SolrCollectionSpec [%s] in namespace [%s] removed alias [%s]", collectionSet.Name,
+			collectionSet.Namespace, alias)
+		changed = true
+	}
+	return changed
 }
 
-// requeue returns a standard delayed requeue ...
-func requeue() (ctrl.Result, error) {
-	return reconcile.Result{}, nil
+// colorPromotion is a switch of the alias of a blue/green collection from one color to the other ...
+type colorPromotion struct {
+	collection string
+	from       string
+	to         string
 }
 
-// requeueImmediately does just that ...
-func requeueImmediately() (ctrl.Result, error) {
-	return reconcile.Result{RequeueAfter: time.Millisecond}, nil
+// toInstance is the name of the collection instance the alias is switched to ...
+func (p colorPromotion) toInstance() string {
+	return p.collection + "_" + p.to
 }
 
-// requeueImmediately does just that ...
-func requeueWithBackoff() (ctrl.Result, error) {
-	return reconcile.Result{RequeueAfter: time.Second * backoffRequeueSeconds}, nil
-}
+// colorPromotions maps the aliases of the blue/green collections whose activeColor differs from the color that was
+// last active to the promotion that's called for. Collections whose last active color isn't known are left out, since
+// then there's no telling a promotion from an alias that has drifted ...
+func colorPromotions(collectionSet solrCollectionSet.SolrCollectionSet,
+	previouslyActive map[string]string) map[string]colorPromotion {
 
-// abs calculates the absolute value of an int32 ...
-func abs(x int32) int32 {
-	if x < 0 {
-		return -x
+	var promotions = make(map[string]colorPromotion)
+	if !*collectionSet.Spec.BlueGreenEnabled {
+		return promotions
 	}
-	return x
-}
-
-// contains tests if the given list contains the given string ...
-func contains(list []string, target string) bool {
-	for _, v := range list {
-		if v == target {
-			return true
+	for _, spec := range collectionSet.Spec.Collections {
+		if spec.IsDisabled() || spec.AliasAllColors || spec.ActiveColor == "" {
+			continue
+		}
+		instanceName, exists := previouslyActive[spec.Name]
+		if !exists {
+			continue
+		}
+		from := strings.TrimPrefix(instanceName, spec.Name+"_")
+		if from != spec.ActiveColor {
+			promotions[spec.Alias] = colorPromotion{collection: spec.Name, from: from, to: spec.ActiveColor}
 		}
 	}
-	return false
+	return promotions
 }
 
-// countSolrCollections counts up the number of collections in the given map MINUS the unmanaged ones ...
-func countSolrCollections(collections map[string]solr.Collection, specCollections []solrCollectionSet.SolrCollection, isBlueGreenEnabled bool) (count int) {
+// savePromotions records the color each of the given collections was promoted to (and when) in their statuses ...
+func (r *SolrCollectionSetReconciler) savePromotions(ctx context.Context,
+	collectionSet solrCollectionSet.SolrCollectionSet, promotions []colorPromotion) {
+
+	logger := log.FromContext(ctx)
 
-	// Make a list of the specified collection names ...
-	var specCollectionList = make([]string, len(specCollections))
-	for i, collection := range specCollections {
-		specCollectionList[i] = collection.Name
+	promotedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+	oldInstance := collectionSet.DeepCopy()
+	newInstance := collectionSet.DeepCopy()
+	for _, promotion := range promotions {
+		for i := range newInstance.Status.SolrCollections {
+			if newInstance.Status.SolrCollections[i].Name == promotion.collection {
+				newInstance.Status.SolrCollections[i].LastPromotedColor = promotion.to
+				newInstance.Status.SolrCollections[i].LastPromotionTime = &promotedAt
+			}
+		}
+	}
+	if err := r.Status().Patch(ctx, newInstance, client.MergeFrom(oldInstance)); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to save color promotions [%s]", collectionSet.Name))
 	}
+}
 
-	for _, collection := range collections {
-		// Don't count collections that begin with _ ...
-		if !strings.HasPrefix(collection.Name, "_") {
-			var collectionName = collection.Name
-			if isBlueGreenEnabled {
-				// Strip the b/g suffix if b/g is enabled ...
-				collectionName = strings.TrimSuffix(collectionName, "_blue")
-				collectionName = strings.TrimSuffix(collectionName, "_green")
+// desiredAliases maps the alias of each blue/green collection to the collections it should target. An alias across all
+// colors targets whichever colors exist, otherwise an alias targets the expected color (see expectedActiveInstance()).
+// Without blue/green there are only aliases if createAliasesAlways is set, each targeting its collection. Aliases of
+// disabled collections, or whose collections don't exist yet, are left out ...
+func desiredAliases(collectionSet solrCollectionSet.SolrCollectionSet, solrCollections map[string]solr.Collection,
+	aliases map[string][]string, previouslyActive map[string]string) map[string][]string {
+
+	var desired = make(map[string][]string)
+	if !*collectionSet.Spec.BlueGreenEnabled {
+		if !*collectionSet.Spec.CreateAliasesAlways {
+			return desired
+		}
+		for _, spec := range collectionSet.Spec.Collections {
+			// An alias can't have the same name as a collection ...
+			if spec.IsDisabled() || spec.Alias == spec.Name {
+				continue
 			}
-			exists := contains(specCollectionList, collectionName)
-			if exists {
-				count++
+			if _, exists := solrCollections[spec.Name]; exists {
+				desired[spec.Alias] = []string{spec.Name}
+			}
+		}
+		return desired
+	}
+	for _, spec := range collectionSet.Spec.Collections {
+		if spec.IsDisabled() {
+			continue
+		}
+		if spec.AliasAllColors {
+			var targets []string
+			for _, instanceName := range []string{spec.Name + "_blue", spec.Name + "_green"} {
+				if _, exists := solrCollections[instanceName]; exists {
+					targets = append(targets, instanceName)
+				}
+			}
+			if len(targets) > 0 {
+				desired[spec.Alias] = targets
 			}
+			continue
+		}
+		expected := expectedActiveInstance(spec, collectionSet.Spec.DefaultColor, aliases[spec.Alias], solrCollections,
+			previouslyActive)
+		if expected != "" {
+			desired[spec.Alias] = []string{expected}
 		}
+	}
+	return desired
+}
 
+// managedAliasNames returns the names of the aliases managed by the collection set, given its desired aliases (see
+// desiredAliases()). Aliases whose collections don't exist yet aren't desired, but they're still managed by the set ...
+func managedAliasNames(collectionSet solrCollectionSet.SolrCollectionSet, desired map[string][]string) map[string]bool {
+	var aliasNames = make(map[string]bool)
+	if !*collectionSet.Spec.BlueGreenEnabled && !*collectionSet.Spec.CreateAliasesAlways {
+		return aliasNames
 	}
-	return count
+	for alias := range desired {
+		aliasNames[alias] = true
+	}
+	for _, spec := range collectionSet.Spec.Collections {
+		// Without blue/green an alias can't have the same name as its collection ...
+		if spec.IsDisabled() || (!*collectionSet.Spec.BlueGreenEnabled && spec.Alias == spec.Name) {
+			continue
+		}
+		aliasNames[spec.Alias] = true
+	}
+	return aliasNames
 }
 
-// countSpecifiedCollections counts the number of specified collections taking into account blue/green collections
-func countSpecifiedCollections(collections []solrCollectionSet.SolrCollection, isBlueGreenEnabled bool) (count int) {
-	multiplier := 1
-	count = len(collections)
+// aliasStatusesOf reports where each alias managed by the collection set points, and whether that's where it should
+// point (see desiredAliases()). Aliases which were managed before, but no longer are, are reported (without desired
+// targets) until ManageAliases() has removed them. The aliases are sorted by name ...
+func aliasStatusesOf(collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus,
+	previouslyActive map[string]string) []solrCollectionSet.AliasStatus {
+
+	desired := desiredAliases(collectionSet, clusterStatus.Collections, clusterStatus.Aliases, previouslyActive)
+	aliasNames := managedAliasNames(collectionSet, desired)
+	for _, aliasStatus := range collectionSet.Status.Aliases {
+		if _, exists := clusterStatus.Aliases[aliasStatus.Name]; exists {
+			aliasNames[aliasStatus.Name] = true
+		}
+	}
+
+	var aliasStatuses []solrCollectionSet.AliasStatus
+	for _, alias := range slices.Sorted(maps.Keys(aliasNames)) {
+		targets := slices.Sorted(slices.Values(clusterStatus.Aliases[alias]))
+		desiredTargets := slices.Sorted(slices.Values(desired[alias]))
+		aliasStatuses = append(aliasStatuses, solrCollectionSet.AliasStatus{
+			Name:           alias,
+			Targets:        targets,
+			DesiredTargets: desiredTargets,
+			InSync:         len(desiredTargets) > 0 && sameAliasTargets(targets, desiredTargets),
+		})
+	}
+	return aliasStatuses
+}
+
+// sameAliasTargets tests if two lists of alias targets hold the same collections, in any order ...
+func sameAliasTargets(targets1 []string, targets2 []string) bool {
+	return reflect.DeepEqual(slices.Sorted(slices.Values(targets1)), slices.Sorted(slices.Values(targets2)))
+}
+
+// expectedActiveInstance determines which color of a blue/green collection its alias should point at. That's the
+// color in the spec, otherwise the color that was last active, otherwise the color the alias currently points at,
+// otherwise the default color. An empty string is returned if the expected collection doesn't exist (yet) ...
+func expectedActiveInstance(spec solrCollectionSet.SolrCollection, defaultColor string, aliasTargets []string,
+	solrCollections map[string]solr.Collection, previouslyActive map[string]string) string {
+
+	blue := spec.Name + "_blue"
+	green := spec.Name + "_green"
+	exists := func(instanceName string) bool {
+		_, exists := solrCollections[instanceName]
+		return exists
+	}
+
+	if spec.ActiveColor != "" {
+		instanceName := spec.Name + "_" + spec.ActiveColor
+		if !exists(instanceName) {
+			return ""
+		}
+		return instanceName
+	}
+	if instanceName, ok := previouslyActive[spec.Name]; ok && exists(instanceName) {
+		return instanceName
+	}
+	if len(aliasTargets) == 1 && (aliasTargets[0] == blue || aliasTargets[0] == green) && exists(aliasTargets[0]) {
+		return aliasTargets[0]
+	}
+	if instanceName := spec.Name + "_" + defaultColor; exists(instanceName) {
+		return instanceName
+	}
+	for _, instanceName := range []string{blue, green} {
+		if exists(instanceName) {
+			return instanceName
+		}
+	}
+	return ""
+}
+
+// ReloadCollections reloads the collections whose config set has changed since they were last loaded so that they pick
+// up the change. The checksum of the config set each collection was loaded with is kept in a collection property.
+// With blue/green only collections that no alias points at are reloaded, so a config set change is staged on the
+// inactive color and can be checked there before promoting it. Once the alias moves over the formerly active color is
+// reloaded in turn. Collections aliased across all colors (see aliasAllColors) are always reloaded. Note that Solr
+// reads the config set whenever a core loads, so a node restart picks up the change regardless. The names of the
+// collections that were reloaded are returned ...
+func (r *SolrCollectionSetReconciler) ReloadCollections(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus,
+	configSetStatuses []solrCollectionSet.ConfigSetStatus) (reloaded []string) {
+
+	logger := log.FromContext(ctx)
+
+	// Only config sets that come from configmaps have checksums ...
+	var configSetChecksums = make(map[string]string)
+	for _, configSetStatus := range configSetStatuses {
+		configSetChecksums[configSetStatus.Name] = configSetStatus.Checksum
+	}
+	var aliasedCollections = make(map[string]bool)
+	for _, targets := range clusterStatus.Aliases {
+		for _, target := range targets {
+			aliasedCollections[target] = true
+		}
+	}
+
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+	for collectionName, spec := range specCollectionsMap {
+		collection, exists := clusterStatus.Collections[collectionName]
+		configSetChecksum, hasChecksum := configSetChecksums[spec.ConfigsetName]
+		if !exists || !hasChecksum || collection.ConfigName != spec.ConfigsetName {
+			continue
+		}
+		loadedChecksum := collection.Properties[configSetChecksumProperty]
+		if loadedChecksum == configSetChecksum {
+			continue
+		}
+		// A collection that hasn't been stamped yet (e.g. it was just created) is assumed to have loaded the current
+		// config set. An alias across all colors never stops pointing at either color, so those collections are
+		// reloaded in place rather than waiting for the alias to move ...
+		if loadedChecksum != "" {
+			if *collectionSet.Spec.BlueGreenEnabled && aliasedCollections[collectionName] && !spec.AliasAllColors {
+				logger.Info(fmt.Sprintf("not reloading active collection [%s] since config set [%s] changed; it's "+
+					"reloaded once the alias points elsewhere", collectionName, spec.ConfigsetName))
+				continue
+			}
+			logger.Info(fmt.Sprintf("reloading collection [%s] since config set [%s] changed", collectionName,
+				spec.ConfigsetName))
+			err := solrClient.ReloadCollection(ctx, collectionName)
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("reload of collection [%s] failed", collectionName))
+				continue
+			}
+			r.Recorder.Eventf(&collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetCollectionReloaded,
+				"SolrCollectionSpec [%s] in namespace [%s] reloaded collection [%s] with config set [%s]",
+				collectionSet.Name, collectionSet.Namespace, collectionName, spec.ConfigsetName)
+			reloaded = append(reloaded, collectionName)
+		}
+		err := solrClient.SetCollectionProperty(ctx, collectionName, configSetChecksumProperty, configSetChecksum)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("could not record the config set checksum of collection [%s]", collectionName))
+		}
+	}
+	sort.Strings(reloaded)
+	return reloaded
+}
+
+// instanceReplicas returns the number of replicas each shard of the given instance of a collection is scaled to. With
+// blue/green an instance is active if an alias points at it or it's the active color in the spec (see
+// SolrCollectionSetSpec.InstanceReplicas()) ...
+func instanceReplicas(collectionSet solrCollectionSet.SolrCollectionSet, spec solrCollectionSet.SolrCollection,
+	instanceName string, aliases map[string][]string) int32 {
+
+	active := !*collectionSet.Spec.BlueGreenEnabled ||
+		(spec.ActiveColor != "" && instanceName == spec.Name+"_"+spec.ActiveColor)
+	for _, targets := range aliases {
+		active = active || slices.Contains(targets, instanceName)
+	}
+	return collectionSet.Spec.InstanceReplicas(spec, active)
+}
+
+// activeInstances maps the names of the active blue/green collections in the given statuses to their instance names.
+// Collections with more than one active color are ignored ...
+func activeInstances(collectionStatuses []solrCollectionSet.SolrCollectionStatus) map[string]string {
+	var active = make(map[string]string)
+	var ambiguous = make(map[string]bool)
+	for _, collectionStatus := range collectionStatuses {
+		if !collectionStatus.BlueGreen || !collectionStatus.Active {
+			continue
+		}
+		if _, exists := active[collectionStatus.Name]; exists {
+			ambiguous[collectionStatus.Name] = true
+		}
+		active[collectionStatus.Name] = collectionStatus.InstanceName
+	}
+	for name := range ambiguous {
+		delete(active, name)
+	}
+	return active
+}
+
+// SplitShard splits the shard named by the split shard annotation (if there is one). The split is submitted to Solr
+// asynchronously and recorded in the status, so rather than waiting on it later reconciles check on it (see
+// checkSplitShard()). Changed is true if the annotation was processed or the split finished, pending is true while the
+// split is running ...
+func (r *SolrCollectionSetReconciler) SplitShard(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet *solrCollectionSet.SolrCollectionSet,
+	solrCollections map[string]solr.Collection) (changed bool, pending bool, err error) {
+
+	logger := log.FromContext(ctx)
+
+	if operation := pendingOperation(collectionSet.Status.PendingOperations, operationSplitShard); operation != nil {
+		return r.checkSplitShard(ctx, solrClient, collectionSet, *operation)
+	}
+
+	target, exists := collectionSet.Annotations[annotationSplitShard]
+	if !exists {
+		return false, false, nil
+	}
+
+	collectionName, shardName, found := strings.Cut(target, "/")
+	if !found || collectionName == "" || shardName == "" {
+		logger.Info(fmt.Sprintf("ignoring annotation [%s] with invalid value [%s], expected <collection>/<shard>",
+			annotationSplitShard, target))
+		changed, err = r.removeAnnotation(ctx, collectionSet, annotationSplitShard)
+		return changed, false, err
+	}
+
+	// Only active shards can be split. If the shard isn't active then it has most likely already been split ...
+	collection, exists := solrCollections[collectionName]
+	if !exists {
+		logger.Info(fmt.Sprintf("ignoring split of shard [%s] since collection [%s] doesn't exist", shardName, collectionName))
+		changed, err = r.removeAnnotation(ctx, collectionSet, annotationSplitShard)
+		return changed, false, err
+	}
+	if _, exists := collection.Shards[shardName]; !exists {
+		logger.Info(fmt.Sprintf("ignoring split of shard [%s] since it isn't an active shard of collection [%s]",
+			shardName, collectionName))
+		changed, err = r.removeAnnotation(ctx, collectionSet, annotationSplitShard)
+		return changed, false, err
+	}
+	// The split would leave the collection with more shards than numShards, which checkShardCounts() would then refuse
+	// to reconcile until the spec is changed ...
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+	if spec, exists := specCollectionsMap[collectionName]; exists && spec.NumShards != nil {
+		logger.Info(fmt.Sprintf("ignoring split of shard [%s] since collection [%s] specifies numShards [%d]; remove "+
+			"numShards from the spec to split its shards", shardName, collectionName, *spec.NumShards))
+		changed, err = r.removeAnnotation(ctx, collectionSet, annotationSplitShard)
+		return changed, false, err
+	}
+
+	// The request id just has to be unique ...
+	requestId := fmt.Sprintf("split-%s-%s-%d", collectionName, shardName, time.Now().UnixNano())
+	logger.Info(fmt.Sprintf("splitting shard [%s] of collection [%s]", shardName, collectionName), "requestId", requestId)
+	err = solrClient.SplitShard(ctx, collectionName, shardName, requestId)
+	if err != nil {
+		return false, false, err
+	}
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.PendingOperations = append(collectionSet.Status.PendingOperations,
+		solrCollectionSet.AsyncOperation{
+			Operation:   operationSplitShard,
+			Target:      target,
+			RequestId:   requestId,
+			SubmittedAt: metav1.Now(),
+		})
+	if err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		return false, false, fmt.Errorf("could not record split of shard [%s] of collection [%s] (request [%s]): %w",
+			shardName, collectionName, requestId, err)
+	}
+	return true, false, nil
+}
+
+// checkSplitShard checks on the given pending split. Once the split has finished (or been given up on) it's removed
+// from the status along with the annotation that asked for it, so a failed split isn't submitted over and over. Changed
+// is true if the split finished, pending is true while it's running ...
+func (r *SolrCollectionSetReconciler) checkSplitShard(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet *solrCollectionSet.SolrCollectionSet,
+	operation solrCollectionSet.AsyncOperation) (changed bool, pending bool, err error) {
+
+	logger := log.FromContext(ctx)
+
+	state, err := solrClient.GetRequestStatus(ctx, operation.RequestId)
+	if err != nil {
+		return false, false, err
+	}
+	switch state {
+	case solr.AsyncStateCompleted:
+		r.Recorder.Eventf(collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetShardSplit,
+			"SolrCollectionSpec [%s] in namespace [%s] split shard [%s]",
+			collectionSet.Name, collectionSet.Namespace, operation.Target)
+	case solr.AsyncStateFailed, solr.AsyncStateNotFound:
+		err = fmt.Errorf("split of shard [%s] (request [%s]) finished with state [%s]",
+			operation.Target, operation.RequestId, state)
+	default:
+		if time.Since(operation.SubmittedAt.Time) < time.Minute*splitShardTimeoutMinutes {
+			logger.Info(fmt.Sprintf("split of shard [%s] is still [%s]", operation.Target, state),
+				"requestId", operation.RequestId)
+			return false, true, nil
+		}
+		err = fmt.Errorf("gave up waiting on split of shard [%s] (request [%s]) after [%d] minutes",
+			operation.Target, operation.RequestId, splitShardTimeoutMinutes)
+	}
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.PendingOperations = slices.DeleteFunc(slices.Clone(collectionSet.Status.PendingOperations),
+		func(pending solrCollectionSet.AsyncOperation) bool {
+			return pending.RequestId == operation.RequestId
+		})
+	if err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		return false, false, fmt.Errorf("could not clear split of shard [%s] (request [%s]): %w",
+			operation.Target, operation.RequestId, err)
+	}
+	// The annotation may have been changed to ask for another split in the meantime ...
+	if collectionSet.Annotations[annotationSplitShard] == operation.Target {
+		if _, removeErr := r.removeAnnotation(ctx, collectionSet, annotationSplitShard); removeErr != nil {
+			return false, false, removeErr
+		}
+	}
+	return err == nil, false, err
+}
+
+// pendingOperation returns the first of the given pending operations of the given kind, or nil if there isn't one ...
+func pendingOperation(operations []solrCollectionSet.AsyncOperation, kind string) *solrCollectionSet.AsyncOperation {
+	for i := range operations {
+		if operations[i].Operation == kind {
+			return &operations[i]
+		}
+	}
+	return nil
+}
+
+// OptimizeCollections starts an optimize of the collections named by the optimize annotation (if there is one) and then
+// removes the annotation. Collections that don't exist are skipped. The optimize runs in the background (see
+// startOptimize()). Changed is true if the annotation was processed ...
+func (r *SolrCollectionSetReconciler) OptimizeCollections(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet *solrCollectionSet.SolrCollectionSet, solrCollections map[string]solr.Collection) (changed bool, err error) {
+
+	logger := log.FromContext(ctx)
+
+	value, exists := collectionSet.Annotations[annotationOptimize]
+	if !exists {
+		return false, nil
+	}
+
+	var collectionNames []string
+	if value == "true" {
+		var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+		mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+		collectionNames = slices.Sorted(maps.Keys(specCollectionsMap))
+	} else {
+		for _, collectionName := range strings.Split(value, ",") {
+			if collectionName = strings.TrimSpace(collectionName); collectionName != "" {
+				collectionNames = append(collectionNames, collectionName)
+			}
+		}
+	}
+
+	var existingNames []string
+	for _, collectionName := range collectionNames {
+		if _, exists := solrCollections[collectionName]; !exists {
+			logger.Info(fmt.Sprintf("ignoring optimize of collection [%s] since it doesn't exist", collectionName))
+			continue
+		}
+		existingNames = append(existingNames, collectionName)
+	}
+	if len(existingNames) > 0 {
+		err = r.startOptimize(ctx, solrClient, collectionSet, existingNames)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return r.removeAnnotation(ctx, collectionSet, annotationOptimize)
+}
+
+// ManageConfigOverlays makes the config overlays of the existing collections of the set match the config overlays in
+// the spec. Collections that don't specify a config overlay are left alone. Failures are logged, the overlay is just
+// checked again on the next reconcile ...
+func (r *SolrCollectionSetReconciler) ManageConfigOverlays(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet, solrCollections map[string]solr.Collection) {
+
+	logger := log.FromContext(ctx)
+
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+	for _, collectionName := range slices.Sorted(maps.Keys(specCollectionsMap)) {
+		spec := specCollectionsMap[collectionName]
+		if _, exists := solrCollections[collectionName]; !exists || spec.ConfigOverlay == nil {
+			continue
+		}
+		overlay, err := solrClient.GetConfigOverlay(ctx, collectionName)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("could not check the config overlay of collection [%s]", collectionName))
+			continue
+		}
+		set, unset := configOverlayChanges(spec.ConfigOverlay, overlay)
+		if len(set) == 0 && len(unset) == 0 {
+			continue
+		}
+		logger.Info(fmt.Sprintf("updating the config overlay of collection [%s]", collectionName), "set",
+			seqToString(maps.Keys(set)), "unset", strings.Join(unset, ", "))
+		err = solrClient.SetConfigProperties(ctx, collectionName, set, unset)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("could not update the config overlay of collection [%s]", collectionName))
+		}
+	}
+}
+
+// configOverlayChanges works out the properties to set and unset to make the given live overlay match the given
+// desired overlay ...
+func configOverlayChanges(desired map[string]string, live map[string]string) (set map[string]string, unset []string) {
+	set = make(map[string]string)
+	for name, value := range desired {
+		if liveValue, exists := live[name]; !exists || liveValue != value {
+			set[name] = value
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(live)) {
+		if _, exists := desired[name]; !exists {
+			unset = append(unset, name)
+		}
+	}
+	return set, unset
+}
+
+// scopedCollectionSet returns a copy of the given collection set with only the collections (and routed aliases) whose
+// names match one of the given comma separated patterns (see path.Match()). The other collections aren't in the spec
+// of the copy, so cleanup is turned off, and the deletion marks and alias statuses are left out (which keeps the marks
+// as they are, and keeps ManageAliases() from removing the aliases of the other collections) ...
+func scopedCollectionSet(collectionSet solrCollectionSet.SolrCollectionSet,
+	patterns string) (solrCollectionSet.SolrCollectionSet, error) {
+
+	var matchers []string
+	for _, pattern := range strings.Split(patterns, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return solrCollectionSet.SolrCollectionSet{}, fmt.Errorf("pattern [%s] of annotation [%s]: %w", pattern,
+				annotationReconcileOnly, err)
+		}
+		matchers = append(matchers, pattern)
+	}
+
+	matches := func(name string) bool {
+		return slices.ContainsFunc(matchers, func(pattern string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		})
+	}
+	scoped := *collectionSet.DeepCopy()
+	scoped.Spec.Collections = slices.DeleteFunc(scoped.Spec.Collections, func(spec solrCollectionSet.SolrCollection) bool {
+		return !matches(spec.Name)
+	})
+	scoped.Spec.RoutedAliases = slices.DeleteFunc(scoped.Spec.RoutedAliases, func(spec solrCollectionSet.SolrRoutedAlias) bool {
+		return !matches(spec.Name)
+	})
+	cleanupEnabled := false
+	scoped.Spec.CleanupEnabled = &cleanupEnabled
+	scoped.Status.MarkedForDeletion = nil
+	scoped.Status.Aliases = nil
+	return scoped, nil
+}
+
+// startOptimize starts optimizing the given collections (one after the other) in the background and records the
+// optimize in the status so that later reconciles check on it (see CheckOptimizes()). An event is emitted as each
+// collection is optimized ...
+func (r *SolrCollectionSetReconciler) startOptimize(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet *solrCollectionSet.SolrCollectionSet, collectionNames []string) error {
+
+	logger := log.FromContext(ctx)
+
+	// The request id just has to be unique ...
+	requestId := fmt.Sprintf("optimize-%s-%d", collectionSet.Name, time.Now().UnixNano())
+	logger.Info(fmt.Sprintf("optimizing collections [%s]", strings.Join(collectionNames, ", ")), "requestId", requestId)
+
+	// The optimize outlives the reconcile, so it gets a context of its own ...
+	optimizeCtx := context.WithoutCancel(ctx)
+	eventTarget := collectionSet.DeepCopy()
+	r.optimizes.start(requestId, func() error {
+		for _, collectionName := range collectionNames {
+			if err := r.optimizeCollection(optimizeCtx, solrClient, eventTarget, collectionName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.PendingOperations = append(collectionSet.Status.PendingOperations,
+		solrCollectionSet.AsyncOperation{
+			Operation:   operationOptimize,
+			Target:      strings.Join(collectionNames, ","),
+			RequestId:   requestId,
+			SubmittedAt: metav1.Now(),
+		})
+	if err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		return fmt.Errorf("could not record optimize of collections [%s] (request [%s]): %w",
+			strings.Join(collectionNames, ", "), requestId, err)
+	}
+	return nil
+}
+
+// CheckOptimizes checks on the optimizes running in the background. The ones that have finished are removed from the
+// status, and the errors of the ones that failed (or were lost because the operator restarted) are returned. Optimizing
+// is true while any of them is still running ...
+func (r *SolrCollectionSetReconciler) CheckOptimizes(ctx context.Context,
+	collectionSet *solrCollectionSet.SolrCollectionSet) (optimizing bool, err error) {
+
+	logger := log.FromContext(ctx)
+
+	var errs []error
+	var finished []string
+	for _, operation := range collectionSet.Status.PendingOperations {
+		if operation.Operation != operationOptimize {
+			continue
+		}
+		state, optimizeErr := r.optimizes.state(operation.RequestId)
+		switch state {
+		case solr.AsyncStateRunning:
+			logger.Info(fmt.Sprintf("optimize of collections [%s] is still running", operation.Target),
+				"requestId", operation.RequestId)
+			optimizing = true
+			continue
+		case solr.AsyncStateFailed:
+			errs = append(errs, fmt.Errorf("optimize of collections [%s] (request [%s]) failed: %w",
+				operation.Target, operation.RequestId, optimizeErr))
+		case solr.AsyncStateNotFound:
+			errs = append(errs, fmt.Errorf("lost track of the optimize of collections [%s] (request [%s]), most "+
+				"likely because the operator restarted", operation.Target, operation.RequestId))
+		}
+		finished = append(finished, operation.RequestId)
+	}
+	if len(finished) == 0 {
+		return optimizing, nil
+	}
+
+	oldInstance := collectionSet.DeepCopy()
+	collectionSet.Status.PendingOperations = slices.DeleteFunc(slices.Clone(collectionSet.Status.PendingOperations),
+		func(pending solrCollectionSet.AsyncOperation) bool {
+			return slices.Contains(finished, pending.RequestId)
+		})
+	if err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		errs = append(errs, fmt.Errorf("could not clear finished optimizes: %w", err))
+	}
+	return optimizing, errors.Join(errs...)
+}
+
+// optimizeCollection optimizes the given collection and emits an event once it's done ...
+func (r *SolrCollectionSetReconciler) optimizeCollection(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet *solrCollectionSet.SolrCollectionSet, collectionName string) error {
+
+	logger := log.FromContext(ctx)
+
+	logger.Info(fmt.Sprintf("optimizing collection [%s]", collectionName))
+	optimizeCtx, cancel := context.WithTimeout(ctx, time.Minute*optimizeTimeoutMinutes)
+	defer cancel()
+	err := solrClient.OptimizeCollection(optimizeCtx, collectionName)
+	if err != nil {
+		return err
+	}
+	r.Recorder.Eventf(collectionSet, corev1.EventTypeNormal, eventSolrCollectionSetCollectionOptimized,
+		"SolrCollectionSpec [%s] in namespace [%s] optimized collection [%s]",
+		collectionSet.Name, collectionSet.Namespace, collectionName)
+	return nil
+}
+
+// removeAnnotation removes the given annotation from the collection set ...
+func (r *SolrCollectionSetReconciler) removeAnnotation(ctx context.Context,
+	collectionSet *solrCollectionSet.SolrCollectionSet, annotation string) (changed bool, err error) {
+
+	oldInstance := collectionSet.DeepCopy()
+	delete(collectionSet.Annotations, annotation)
+	if err := r.Patch(ctx, collectionSet, client.MergeFrom(oldInstance)); err != nil {
+		return false, fmt.Errorf("could not remove annotation [%s]: %w", annotation, err)
+	}
+	return true, nil
+}
+
+// isLeader tests if this operator instance has been elected leader ...
+func (r *SolrCollectionSetReconciler) isLeader() bool {
+	if r.Elected == nil {
+		return true
+	}
+	select {
+	case <-r.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// solrClientFor returns the client for the Solr cluster of the given collection set, instantiating it if this is the
+// first collection set to use the cluster (or the secret it was instantiated with has changed) ...
+func (r *SolrCollectionSetReconciler) solrClientFor(ctx context.Context,
+	collectionSet solrCollectionSet.SolrCollectionSet) (solr.SolrClient, error) {
+	// Fall back to the operator-wide defaults if the collection set doesn't say ...
+	secretRef := collectionSet.Spec.SecretRef
+	if secretRef == "" && r.SolrCredentialsPath == "" {
+		secretRef = r.DefaultSolrSecretName
+	}
+	clusterUrl := collectionSet.Spec.SolrClusterUrl
+	if clusterUrl == "" {
+		clusterUrl = r.DefaultSolrClusterUrl
+	}
+	// ... and then to the Solr cluster named after the collection set ...
+	if clusterUrl == "" {
+		clusterUrl = fmt.Sprintf(defaultSolrClusterUrlTemplate, collectionSet.Spec.SolrScheme, collectionSet.Name,
+			*collectionSet.Spec.SolrPort)
+	}
+	// The secret is read (from the cache) on every reconcile so that the client is recreated once the credentials in
+	// it are rotated ...
+	var secret *corev1.Secret
+	var secretVersion string
+	if secretRef != "" {
+		secret = &corev1.Secret{}
+		if err := r.Get(ctx, r.solrSecretName(secretRef), secret); err != nil {
+			return solr.SolrClient{}, fmt.Errorf("could not read the basic auth secret [%s]: %w", secretRef, err)
+		}
+		secretVersion = secret.ResourceVersion
+	}
+	solrClient, err := r.solrClients.get(clusterUrl, secretRef, secretVersion, func() (solr.SolrClient, error) {
+		log.FromContext(ctx).Info(fmt.Sprintf("instantiating a solr client for [%s]", clusterUrl))
+		return r.makeSolrClient(secretRef, secret, clusterUrl)
+	})
+	if err != nil {
+		return solrClient, err
+	}
+	// Collection sets sharing a client can still authenticate differently ...
+	solrClient.AuthMode = collectionSet.Spec.AuthMode
+	return solrClient, nil
+}
+
+// makeSolrClient Creates a client for the Solr API. The credentials come from the given secret (already read by the
+// caller), or from the credentials path if no secret is given ...
+func (r *SolrCollectionSetReconciler) makeSolrClient(secretRef string, basicAuthSecret *corev1.Secret,
+	clusterUrl string) (solrClient solr.SolrClient, error error) {
+	// Query Solr for the actual cluster state ...
+	if secretRef == "" && r.SolrCredentialsPath != "" {
+		solrClient = solr.SolrClient{
+			CredentialsPath: r.SolrCredentialsPath,
+			Url:             clusterUrl,
+			RateLimiter:     r.SolrRateLimiter,
+
+			CommitStrategy:     r.SolrCommitStrategy,
+			CommitWithinMillis: r.SolrCommitWithinMillis,
+
+			OnMutation: r.auditSolrMutation,
+			Transport:  solrRequestTimer{},
+		}
+	} else if secretRef != "" {
+		secretName := r.solrSecretName(secretRef)
+		// Initialize solrClient if it isn't already ...
+		if !solrClient.IsConfigured() {
+			solrClient = solr.SolrClient{
+				Username:    string(basicAuthSecret.Data["username"]),
+				Password:    string(basicAuthSecret.Data["password"]),
+				TokenSource: r.secretTokenSource(secretName),
+				Url:         clusterUrl,
+				RateLimiter: r.SolrRateLimiter,
+				Headers:     secretHeaders(basicAuthSecret),
+
+				CommitStrategy:     r.SolrCommitStrategy,
+				CommitWithinMillis: r.SolrCommitWithinMillis,
+
+				OnMutation: r.auditSolrMutation,
+				Transport:  solrRequestTimer{},
+			}
+		}
+	} else {
+		return solrClient, fmt.Errorf("no secret was provided for Solr basic auth")
+	}
+	return solrClient, nil
+}
+
+// solrSecretName returns the name (and namespace) of the given Solr secret ...
+func (r *SolrCollectionSetReconciler) solrSecretName(secretRef string) types.NamespacedName {
+	secretNamespace := r.DefaultSolrSecretNamespace
+	if secretNamespace == "" {
+		secretNamespace = defaultSolrSecretNamespace
+	}
+	return types.NamespacedName{Name: secretRef, Namespace: secretNamespace}
+}
+
+// secretTokenSource returns a token source which reads the bearer token from the "token" key of the given secret.
+// The secret is read on every call (from the cache), so a token that's refreshed in the secret is picked up ...
+func (r *SolrCollectionSetReconciler) secretTokenSource(secretName types.NamespacedName) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, secretName, secret); err != nil {
+			return "", fmt.Errorf("could not read the secret [%s]: %w", secretName.Name, err)
+		}
+		return string(secret.Data["token"]), nil
+	}
+}
+
+// secretHeaders reads the request headers from the given secret. Each key of the form "header.<Header-Name>" becomes a
+// header (e.g. "header.X-Api-Key") ...
+func secretHeaders(secret *corev1.Secret) map[string]string {
+	headers := make(map[string]string)
+	for key, value := range secret.Data {
+		if name, found := strings.CutPrefix(key, solrSecretHeaderPrefix); found && name != "" {
+			headers[name] = string(value)
+		}
+	}
+	return headers
+}
+
+// configSetNameOf determines the name the config set held by the given configmap has in Solr. That's the collection
+// label of the configmap, suffixed with its config set version label (if it has one) so that several versions of a
+// config set can be in Solr side by side. A version of "hash" is replaced by the start of the config set's checksum ...
+func configSetNameOf(configMap corev1.ConfigMap) (string, error) {
+	name, exists := configMap.Labels[configMapLabelCollection]
+	if !exists {
+		return "", fmt.Errorf("config set configmap [%s] has no '%s' label", configMap.Name, configMapLabelCollection)
+	}
+	switch version := configMap.Labels[configMapLabelConfigSetVersion]; version {
+	case "":
+		return name, nil
+	case configSetVersionHash:
+		return name + "-" + checksum(configMap.Data["configset"])[:configSetVersionHashLength], nil
+	default:
+		return name + "-" + version, nil
+	}
+}
+
+// checksumRecord is a record in the checksums collection holding the checksum of a config set ...
+type checksumRecord struct {
+	// The config set (named for the collection it was originally for)
+	Collection string
+	// The checksum of the base64 encoded config set zip
+	Checksum string
+}
+
+// parseChecksumRecord reads a checksum record from a doc returned by a query of the checksums collection. Depending on
+// the schema Solr may return a field as a list of values, in which case the list has to hold exactly one value ...
+func parseChecksumRecord(doc map[string]interface{}) (checksumRecord, error) {
+	collection, err := docString(doc, "collection")
+	if err != nil {
+		return checksumRecord{}, err
+	}
+	checksum, err := docString(doc, "checksum")
+	if err != nil {
+		return checksumRecord{}, err
+	}
+	return checksumRecord{Collection: collection, Checksum: checksum}, nil
+}
+
+// docString reads a single string value from a field of a doc returned by a Solr query ...
+func docString(doc map[string]interface{}, field string) (string, error) {
+	value := doc[field]
+	if values, isList := value.([]interface{}); isList {
+		if len(values) != 1 {
+			return "", fmt.Errorf("field [%s] has [%d] values rather than one", field, len(values))
+		}
+		value = values[0]
+	}
+	switch value := value.(type) {
+	case nil:
+		return "", fmt.Errorf("field [%s] is missing", field)
+	case string:
+		return value, nil
+	default:
+		return "", fmt.Errorf("field [%s] is a [%T] rather than a string", field, value)
+	}
+}
+
+// checksum calculates the md5 checksum of a string.
+func checksum(data string) string {
+	bytes := []byte(data)
+	hash := md5.Sum(bytes)
+	return hex.EncodeToString(hash[:])
+}
+
+// decodeConfigSet decodes the given base64 encoded config set zip (i.e. from a configmap), reusing the decoded zip if
+// the config set was decoded before ...
+func (r *SolrCollectionSetReconciler) decodeConfigSet(configSetEncoded string) ([]byte, error) {
+	return r.configSetZips.get(checksum(configSetEncoded), r.ConfigSetCacheSize, func() ([]byte, error) {
+		return base64.StdEncoding.DecodeString(configSetEncoded)
+	})
+}
+
+// configSetVerifyDue tests if the given config set is due to be compared with what's in Solr (see
+// ConfigSetVerifyInterval) ...
+func (r *SolrCollectionSetReconciler) configSetVerifyDue(collectionSet solrCollectionSet.SolrCollectionSet,
+	configSetName string) bool {
+	if r.ConfigSetVerifyInterval <= 0 {
+		return true
+	}
+	for _, configSetStatus := range collectionSet.Status.ConfigSets {
+		if configSetStatus.Name == configSetName && configSetStatus.LastVerified != nil {
+			return time.Since(configSetStatus.LastVerified.Time) >= r.ConfigSetVerifyInterval
+		}
+	}
+	return true
+}
+
+// configSetDrifted tests if the given config set in Solr differs from the given zip (i.e. from the configmap). The
+// contents of the zips are compared since Solr doesn't return the zip that was uploaded ...
+func configSetDrifted(ctx context.Context, solrClient solr.SolrClient, configSetName string,
+	configSetDecoded []byte) (bool, error) {
+	specChecksum, err := utils.ZipContentChecksum(configSetDecoded, nil)
+	if err != nil {
+		return false, fmt.Errorf("could not read config set %s from the configmap: %w", configSetName, err)
+	}
+	downloaded, err := solrClient.DownloadConfigSet(ctx, configSetName)
+	if err != nil {
+		return false, err
+	}
+	renames, err := managedSchemaRenames(configSetDecoded, downloaded)
+	if err != nil {
+		return false, fmt.Errorf("could not read config set %s downloaded from Solr: %w", configSetName, err)
+	}
+	solrChecksum, err := utils.ZipContentChecksum(downloaded, renames)
+	if err != nil {
+		return false, fmt.Errorf("could not read config set %s downloaded from Solr: %w", configSetName, err)
+	}
+	return specChecksum != solrChecksum, nil
+}
+
+// managedSchemaRenames maps the files of the downloaded config set back to the files of the configmap's config set when
+// Solr has switched it to a managed schema. Solr renames schema.xml to schema.xml.bak when it does, and writes the
+// managed schema (managed-schema, or managed-schema.xml since Solr 9) in its place. The backup is compared with the
+// configmap's schema.xml, and the generated managed schema is ignored, so that the switch isn't taken for drift ...
+func managedSchemaRenames(configSetDecoded []byte, downloaded []byte) (map[string]string, error) {
+	specNames, err := utils.ZipFileNames(configSetDecoded)
+	if err != nil {
+		return nil, err
+	}
+	solrNames, err := utils.ZipFileNames(downloaded)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(specNames, "schema.xml") || contains(solrNames, "schema.xml") ||
+		!contains(solrNames, "schema.xml.bak") {
+		return nil, nil
+	}
+	renames := map[string]string{"schema.xml.bak": "schema.xml"}
+	for _, managedSchema := range []string{"managed-schema", "managed-schema.xml"} {
+		if !contains(specNames, managedSchema) {
+			renames[managedSchema] = ""
+		}
+	}
+	return renames, nil
+}
+
+// seqToString Takes a sequence and turns it into a string where the elements are comma delimited
+func seqToString(seq iter.Seq[string]) string {
+	i := 0
+	for range seq {
+		i++
+	}
+	var parts = make([]string, i)
+
+	i = 0
+	for v := range seq {
+		parts[i] = v
+		i++
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// createChecksumCollection creates a checksum config set and collection ...
+func (r *SolrCollectionSetReconciler) createChecksumCollection(ctx context.Context, solrClient solr.SolrClient,
+	checksumsCollectionName string, configSet checksumsConfigSet, replicationFactor int32, autoAddReplicas bool,
+	placement solr.CollectionPlacement, owner solr.CollectionOwner) error {
+	// assume if the collection doesn't exist then the schema doesn't either, so create it ...
+	err := r.uploadChecksumConfigSet(ctx, solrClient, configSet)
+	if err != nil {
+		return err
+	}
+	// create the collection
+	err = solrClient.CreateCollection(ctx, checksumsCollectionName, configSet.name, 1, "", replicationFactor,
+		autoAddReplicas, placement, owner)
+	if err != nil {
+		return err
+	}
+	return setChecksumsSchemaVersion(ctx, solrClient, checksumsCollectionName)
+}
+
+// setChecksumsSchemaVersion records that the given checksums collection has the current version of the embedded
+// config set ...
+func setChecksumsSchemaVersion(ctx context.Context, solrClient solr.SolrClient, checksumsCollectionName string) error {
+	return solrClient.SetCollectionProperty(ctx, checksumsCollectionName, checksumsSchemaVersionProperty,
+		strconv.Itoa(checksumsConfigSetVersion))
+}
+
+// collectionOwner identifies the given collection set as the owner of collections in Solr ...
+func collectionOwner(collectionSet solrCollectionSet.SolrCollectionSet) solr.CollectionOwner {
+	return solr.CollectionOwner{
+		Name:      collectionSet.Name,
+		Namespace: collectionSet.Namespace,
+		Uid:       string(collectionSet.UID),
+	}
+}
+
+// uploadChecksumConfigSet uploads the given config set used by checksums collections ...
+func (r *SolrCollectionSetReconciler) uploadChecksumConfigSet(ctx context.Context, solrClient solr.SolrClient,
+	configSet checksumsConfigSet) error {
+	// The embedded config sets only change with the operator, so the zip can be reused if zips are cached ...
+	if r.ConfigSetCacheSize > 0 {
+		key := fmt.Sprintf("embedded:%s:%d", configSet.name, checksumsConfigSetVersion)
+		zipped, err := r.configSetZips.get(key, r.ConfigSetCacheSize, func() ([]byte, error) {
+			return utils.Zip(configSet.dirs, checksumCollectionSchema)
+		})
+		if err != nil {
+			return err
+		}
+		return solrClient.UploadConfigSet(ctx, configSet.name, bytes.NewReader(zipped), int64(len(zipped)))
+	}
+	// Otherwise zip the config set straight into the request body ...
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(utils.ZipTo(writer, configSet.dirs, checksumCollectionSchema))
+	}()
+	defer func() {
+		_ = reader.Close()
+	}()
+	return solrClient.UploadConfigSet(ctx, configSet.name, reader, -1)
+}
+
+// checksumsCollectionNameFor determines the name of the checksums collection used by the given collection set ...
+func checksumsCollectionNameFor(collectionSet solrCollectionSet.SolrCollectionSet) string {
+	if *collectionSet.Spec.SharedChecksums {
+		return configSharedChecksumsCollectionName
+	}
+	return fmt.Sprintf(configChecksumsCollectionNameTemplate, collectionSet.Name)
+}
+
+// checksumsReplicationFactor determines the replication factor the given checksums collection is kept at. A shared
+// checksums collection keeps the replication factor it was created with, since the collection sets sharing it may not
+// agree on one and would otherwise change it back and forth ...
+func checksumsReplicationFactor(collectionSet solrCollectionSet.SolrCollectionSet, collection solr.Collection) int32 {
+	if *collectionSet.Spec.SharedChecksums && collection.ReplicationFactor > 0 {
+		return collection.ReplicationFactor
+	}
+	return collectionSet.Spec.ChecksumCollectionReplicationFactor()
+}
+
+// checksumsConfigSetFor determines the config set of the checksums collection used by the given collection set ...
+func checksumsConfigSetFor(collectionSet solrCollectionSet.SolrCollectionSet) checksumsConfigSet {
+	if *collectionSet.Spec.SharedChecksums {
+		return sharedChecksumsConfigSet
+	}
+	return perSetChecksumsConfigSet
+}
+
+// checksumsSetId identifies the given collection set's records in a shared checksums collection ...
+func checksumsSetId(collectionSet solrCollectionSet.SolrCollectionSet) string {
+	return collectionSet.Namespace + "/" + collectionSet.Name
+}
+
+// checksumsQueryFor creates the query that finds the checksum records of the given collection set. A shared checksums
+// collection holds the checksums of other collection sets too, so only this set's are queried ...
+func checksumsQueryFor(collectionSet solrCollectionSet.SolrCollectionSet) string {
+	if *collectionSet.Spec.SharedChecksums {
+		return fmt.Sprintf(`set:"%s"`, checksumsSetId(collectionSet))
+	}
+	return "*:*"
+}
+
+// mapCollections maps collection to their collection name ...
+func mapCollections(specCollections []solrCollectionSet.SolrCollection,
+	storage map[string]solrCollectionSet.SolrCollection, isBlueGreenEneabled bool) {
+	// Map the collections collectionsSpec for easy access
+	// Create _blue/_green entries if isBlueGreenEnabled is true. Otherwise, just use the plain collection name.
+	// Disabled collections are left out since there's nothing to do with them ...
+
+	for _, spec := range specCollections {
+		if spec.IsDisabled() {
+			continue
+		}
+		collectionName := spec.Name
+		if isBlueGreenEneabled {
+			storage[collectionName+"_blue"] = spec
+			storage[collectionName+"_green"] = spec
+		} else {
+			storage[collectionName] = spec
+		}
+	}
+}
+
+// checkMaxCollections returns an error if the collection set specifies more collections than its maximum ...
+func checkMaxCollections(collectionSet solrCollectionSet.SolrCollectionSet) error {
+	if collectionSet.Spec.MaxCollections == nil {
+		return nil
+	}
+	count := countSpecifiedCollections(collectionSet.Spec.Collections, *collectionSet.Spec.BlueGreenEnabled)
+	if count > int(*collectionSet.Spec.MaxCollections) {
+		return fmt.Errorf("the spec calls for [%d] collections which exceeds maxCollections [%d]",
+			count, *collectionSet.Spec.MaxCollections)
+	}
+	return nil
+}
+
+// checkNameCollisions returns an error if an alias in the spec has the same name as a collection (including its
+// blue/green instances) or another alias. Aliases and collections share names in Solr, so Solr would otherwise fail the
+// create with a confusing error ...
+func checkNameCollisions(collectionSet solrCollectionSet.SolrCollectionSet) error {
+	// Map the names of the collections in Solr to what they are in the spec ...
+	var names = make(map[string]string)
+	for _, spec := range collectionSet.Spec.Collections {
+		if *collectionSet.Spec.BlueGreenEnabled {
+			names[spec.Name+"_blue"] = fmt.Sprintf("collection [%s_blue]", spec.Name)
+			names[spec.Name+"_green"] = fmt.Sprintf("collection [%s_green]", spec.Name)
+		} else {
+			names[spec.Name] = fmt.Sprintf("collection [%s]", spec.Name)
+		}
+	}
+
+	var collisions []string
+	var collectionAliases = make(map[string]bool)
+	addAlias := func(alias string, description string, isCollectionAlias bool) {
+		if other, exists := names[alias]; exists {
+			// Collections sharing an alias are reported by checkAliasConflicts() ...
+			if !isCollectionAlias || !collectionAliases[alias] {
+				collisions = append(collisions, fmt.Sprintf("%s has the same name as %s", description, other))
+			}
+			return
+		}
+		names[alias] = description
+		collectionAliases[alias] = isCollectionAlias
+	}
+	// Aliases are only created for collections if blue/green is enabled (or aliases are always created, in which case
+	// an alias with the same name as its collection is skipped) ...
+	if *collectionSet.Spec.BlueGreenEnabled {
+		for _, spec := range collectionSet.Spec.Collections {
+			addAlias(spec.Alias, fmt.Sprintf("alias [%s] of collection [%s]", spec.Alias, spec.Name), true)
+		}
+	} else if *collectionSet.Spec.CreateAliasesAlways {
+		for _, spec := range collectionSet.Spec.Collections {
+			if spec.Alias != spec.Name {
+				addAlias(spec.Alias, fmt.Sprintf("alias [%s] of collection [%s]", spec.Alias, spec.Name), true)
+			}
+		}
+	}
+	for _, routedAlias := range collectionSet.Spec.RoutedAliases {
+		addAlias(routedAlias.Name, fmt.Sprintf("routed alias [%s]", routedAlias.Name), false)
+	}
+
+	if len(collisions) == 0 {
+		return nil
+	}
+	return fmt.Errorf("aliases collide with other names: %s", strings.Join(collisions, ", "))
+}
+
+// checkAliasConflicts returns an error naming the collections that claim the same alias. Only the aliases which are
+// created for collections (see checkNameCollisions()) are checked ...
+func checkAliasConflicts(collectionSet solrCollectionSet.SolrCollectionSet) error {
+	if !*collectionSet.Spec.BlueGreenEnabled && !*collectionSet.Spec.CreateAliasesAlways {
+		return nil
+	}
+	var claims = make(map[string][]string)
+	for _, spec := range collectionSet.Spec.Collections {
+		if !*collectionSet.Spec.BlueGreenEnabled && spec.Alias == spec.Name {
+			continue
+		}
+		claims[spec.Alias] = append(claims[spec.Alias], spec.Name)
+	}
+
+	var conflicts []string
+	for _, alias := range slices.Sorted(maps.Keys(claims)) {
+		if collectionNames := claims[alias]; len(collectionNames) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("alias [%s] is claimed by collections [%s]", alias,
+				strings.Join(collectionNames, ", ")))
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("aliases can only belong to one collection: %s", strings.Join(conflicts, ", "))
+}
+
+// checkShardCounts returns an error naming the collections whose shard count in Solr differs from the number of shards
+// in the spec. Collections that don't specify a number of shards aren't checked ...
+func checkShardCounts(collectionSet solrCollectionSet.SolrCollectionSet,
+	solrCollections map[string]solr.Collection) error {
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+
+	var mismatches []string
+	for collectionName, spec := range specCollectionsMap {
+		solrCollection, exists := solrCollections[collectionName]
+		if !exists || spec.NumShards == nil {
+			continue
+		}
+		if shardCount := len(solrCollection.Shards); shardCount != int(*spec.NumShards) {
+			mismatches = append(mismatches, fmt.Sprintf("collection [%s] has [%d] shards rather than [%d]",
+				collectionName, shardCount, *spec.NumShards))
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	sort.Strings(mismatches)
+	return fmt.Errorf("the number of shards of existing collections can't be changed: %s",
+		strings.Join(mismatches, ", "))
+}
+
+// checkPlacements returns an error naming the collections that still need to be created whose replicas can't be
+// placed on the live nodes the way the spec asks (i.e. their createNodeSet has nodes that aren't live, or there
+// aren't enough nodes for maxShardsPerNode). If the live nodes aren't known then nothing is checked ...
+func checkPlacements(collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus) error {
+	if clusterStatus.LiveNodes == nil {
+		return nil
+	}
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+
+	var problems []string
+	for collectionName, spec := range specCollectionsMap {
+		if _, exists := clusterStatus.Collections[collectionName]; exists {
+			continue
+		}
+		nodeCount := len(clusterStatus.LiveNodes)
+		if len(spec.CreateNodeSet) > 0 {
+			nodeCount = len(spec.CreateNodeSet)
+			for _, node := range spec.CreateNodeSet {
+				if !slices.Contains(clusterStatus.LiveNodes, node) {
+					problems = append(problems, fmt.Sprintf("node [%s] of collection [%s] isn't live", node,
+						collectionName))
+				}
+			}
+		}
+		if spec.MaxShardsPerNode != nil {
+			replicaCount := collectionSet.Spec.CollectionNumShards(spec) * collectionSet.Spec.CollectionReplicationFactor(spec)
+			if replicaCount > *spec.MaxShardsPerNode*int32(nodeCount) {
+				problems = append(problems, fmt.Sprintf(
+					"collection [%s] has [%d] replicas but [%d] nodes only hold [%d] at [%d] per node",
+					collectionName, replicaCount, nodeCount, *spec.MaxShardsPerNode*int32(nodeCount),
+					*spec.MaxShardsPerNode))
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("collections can't be placed on the live nodes: %s", strings.Join(problems, ", "))
+}
+
+// collectionPlacement returns where the replicas of the given collection of the set go when it's created ...
+func collectionPlacement(collectionSet solrCollectionSet.SolrCollectionSet,
+	spec solrCollectionSet.SolrCollection) solr.CollectionPlacement {
+	placement := solr.CollectionPlacement{CreateNodeSet: spec.CreateNodeSet, Policy: collectionSet.Spec.PlacementPolicy}
+	if spec.WaitForFinalState != nil {
+		placement.WaitForFinalState = *spec.WaitForFinalState
+	}
+	if spec.MaxShardsPerNode != nil {
+		placement.MaxShardsPerNode = *spec.MaxShardsPerNode
+	}
+	return placement
+}
+
+// missingNodesOf finds the nodes hosting replicas in the given cluster status which aren't live, mapped to when they
+// were first found missing. Nodes that were already missing keep the time they have in the given previous map. If the
+// live nodes aren't known then the previous map is kept as-is ...
+func missingNodesOf(clusterStatus solr.ClusterStatus,
+	previous map[string]metav1.Time) map[string]metav1.Time {
+
+	if clusterStatus.LiveNodes == nil {
+		return previous
+	}
+	var missing map[string]metav1.Time
+	now := metav1.Now()
+	for _, collection := range clusterStatus.Collections {
+		for _, shard := range collection.Shards {
+			for _, replica := range shard.Replicas {
+				if replica.Node == "" || slices.Contains(clusterStatus.LiveNodes, replica.Node) {
+					continue
+				}
+				if missing == nil {
+					missing = make(map[string]metav1.Time)
+				}
+				if since, exists := previous[replica.Node]; exists {
+					missing[replica.Node] = since
+				} else if _, exists := missing[replica.Node]; !exists {
+					missing[replica.Node] = now
+				}
+			}
+		}
+	}
+	return missing
+}
+
+// presumedLiveNodes adds the given missing nodes which haven't been missing for the given delay yet to the given live
+// nodes, so that the replicas on them are still counted while they (most likely) restart. If the live nodes aren't
+// known (i.e. nil) then neither are the presumed live nodes ...
+func presumedLiveNodes(liveNodes []string, missingNodes map[string]metav1.Time, delay time.Duration) []string {
+	if liveNodes == nil {
+		return nil
+	}
+	presumed := slices.Clone(liveNodes)
+	for _, node := range slices.Sorted(maps.Keys(missingNodes)) {
+		if time.Since(missingNodes[node].Time) < delay {
+			presumed = append(presumed, node)
+		}
+	}
+	return presumed
+}
+
+// nextRepairDue works out how long it is until the first of the given missing nodes has been missing for the given
+// delay, at which point the replicas on it are replaced. Ok is false if no node is waiting on the delay ...
+func nextRepairDue(missingNodes map[string]metav1.Time, delay time.Duration) (wait time.Duration, ok bool) {
+	for _, since := range missingNodes {
+		untilDue := time.Until(since.Add(delay))
+		if untilDue > 0 && (!ok || untilDue < wait) {
+			wait = untilDue
+			ok = true
+		}
+	}
+	// Allow for the time being truncated to the second ...
+	return wait + time.Second, ok
+}
+
+// replicaRepairDelay is how long a node has to be missing before the replicas on it are replaced ...
+func replicaRepairDelay(collectionSet solrCollectionSet.SolrCollectionSet) time.Duration {
+	return time.Second * time.Duration(*collectionSet.Spec.ReplicaRepairDelaySeconds)
+}
+
+// checkLiveNodes returns an error if fewer Solr nodes are live than the spec requires before the cluster is changed.
+// If the live nodes aren't known then they aren't checked ...
+func checkLiveNodes(collectionSet solrCollectionSet.SolrCollectionSet, clusterStatus solr.ClusterStatus) error {
+	if collectionSet.Spec.MinLiveNodes == nil || clusterStatus.LiveNodes == nil {
+		return nil
+	}
+	if liveNodeCount := len(clusterStatus.LiveNodes); liveNodeCount < int(*collectionSet.Spec.MinLiveNodes) {
+		return fmt.Errorf("only [%d] Solr nodes are live but at least [%d] are required", liveNodeCount,
+			*collectionSet.Spec.MinLiveNodes)
+	}
+	return nil
+}
+
+// checkClusterStatusShrink returns an error if more than half of the collections of the set (including the checksums
+// collection) that existed when the status was last updated are missing from the given collections ...
+func checkClusterStatusShrink(collectionSet solrCollectionSet.SolrCollectionSet,
+	solrCollections map[string]solr.Collection) error {
+
+	var previousCount, missingCount int
+	for _, collectionStatus := range collectionSet.Status.SolrCollections {
+		if !collectionStatus.Exists {
+			continue
+		}
+		previousCount++
+		if _, exists := solrCollections[instanceName(collectionStatus)]; !exists {
+			missingCount++
+		}
+	}
+	// The checksums collection is expected to be there as well ...
+	if checksumStatus := collectionSet.Status.ChecksumCollection; checksumStatus != nil && checksumStatus.Exists {
+		previousCount++
+		if _, exists := solrCollections[checksumStatus.Name]; !exists {
+			missingCount++
+		}
+	}
+	if missingCount == 0 || missingCount*2 <= previousCount {
+		return nil
+	}
+	return fmt.Errorf("[%d] of the [%d] collections in the status are missing from the cluster status", missingCount,
+		previousCount)
+}
+
+// checkCleanupGuard returns an error if cleanup would delete more of the collections managed by the set in one go than
+// the spec's cleanupMaxDeletions or cleanupMaxPercent allow. Only the collections that are due for deletion count, so
+// collections which are still in their grace period (or whose deletion is in-flight) don't trip the guard. The colors of
+// a blue/green collection are counted as one collection, so removing a collection from the spec counts the same with or
+// without blue/green ...
+func checkCleanupGuard(collectionSet solrCollectionSet.SolrCollectionSet, solrCollections map[string]solr.Collection,
+	pendingDeletions *deletionTracker) error {
+	if !*collectionSet.Spec.CleanupEnabled {
+		return nil
+	}
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+
+	// The managed collections are worked out the same way ManageCollections() finds the collections to clean up ...
+	owner := collectionOwner(collectionSet)
+	var managedCollections = make(map[string]bool)
+	for _, collectionName := range collectionSet.Status.ManagedCollections {
+		managedCollections[collectionName] = true
+	}
+	for collectionName, collection := range solrCollections {
+		if collection.HasOwner() && collection.Owner.Uid == owner.Uid {
+			managedCollections[collectionName] = true
+		}
+	}
+	baseNameOf := func(collectionName string) string {
+		if *collectionSet.Spec.BlueGreenEnabled {
+			collectionName = strings.TrimSuffix(collectionName, "_blue")
+			collectionName = strings.TrimSuffix(collectionName, "_green")
+		}
+		return collectionName
+	}
+	var managed = make(map[string]bool)
+	var deleted = make(map[string]bool)
+	var deletions []string
+	gracePeriod := collectionSet.Spec.CleanupGracePeriodSeconds
+	for collectionName := range managedCollections {
+		collection, exists := solrCollections[collectionName]
+		if !exists || strings.HasPrefix(collectionName, "_") ||
+			(collection.HasOwner() && collection.Owner.Uid != owner.Uid) {
+			continue
+		}
+		managed[baseNameOf(collectionName)] = true
+		if _, specified := specCollectionsMap[collectionName]; specified || pendingDeletions.isPending(collectionName) {
+			continue
+		}
+		if gracePeriod != nil && *gracePeriod > 0 {
+			mark, marked := collectionSet.Status.MarkedForDeletion[collectionName]
+			if !marked || time.Now().Before(mark.DeleteAfter.Time) {
+				continue
+			}
+		}
+		deletions = append(deletions, collectionName)
+		deleted[baseNameOf(collectionName)] = true
+	}
+
+	maxDeletions, maxPercent := *collectionSet.Spec.CleanupMaxDeletions, *collectionSet.Spec.CleanupMaxPercent
+	tooMany := maxDeletions > 0 && int32(len(deleted)) > maxDeletions
+	tooMuch := maxPercent > 0 && len(deleted) > 1 && int32(len(deleted))*100 > maxPercent*int32(len(managed))
+	if !tooMany && !tooMuch {
+		return nil
+	}
+	sort.Strings(deletions)
+	return fmt.Errorf("cleanup would delete [%d] of the [%d] managed collections %v which is more than the [%d] "+
+		"collections or [%d%%] allowed in one go", len(deleted), len(managed), deletions, maxDeletions, maxPercent)
+}
+
+// clusterStatusShrinkPersisted tests if the last few reconciles have all backed off because the cluster status shrank ...
+func clusterStatusShrinkPersisted(status solrCollectionSet.SolrCollectionSetStatus) bool {
+	stableCondition := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetStable)
+	return stableCondition != nil && stableCondition.Reason == reasonSolrCollectionSetClusterStatusShrank &&
+		status.ConsecutiveErrors >= clusterStatusShrinkRetries
+}
+
+// checkRouterFields returns an error naming the collections whose router field in Solr differs from the router field
+// in the spec. Collections that don't specify a router field aren't checked ...
+func checkRouterFields(collectionSet solrCollectionSet.SolrCollectionSet,
+	solrCollections map[string]solr.Collection) error {
+	var specCollectionsMap = make(map[string]solrCollectionSet.SolrCollection)
+	mapCollections(collectionSet.Spec.Collections, specCollectionsMap, *collectionSet.Spec.BlueGreenEnabled)
+
+	var mismatches []string
+	for collectionName, spec := range specCollectionsMap {
+		solrCollection, exists := solrCollections[collectionName]
+		if !exists || spec.RouterField == "" {
+			continue
+		}
+		if solrCollection.RouterField != spec.RouterField {
+			mismatches = append(mismatches, fmt.Sprintf("collection [%s] is routed on [%s] rather than [%s]",
+				collectionName, routerFieldOrId(solrCollection.RouterField), spec.RouterField))
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	sort.Strings(mismatches)
+	return fmt.Errorf("the router field of existing collections can't be changed: %s",
+		strings.Join(mismatches, ", "))
+}
+
+// routerFieldOrId names what a collection routes documents on given its router field ...
+func routerFieldOrId(routerField string) string {
+	if routerField == "" {
+		return "id"
+	}
+	return routerField
+}
+
+// checkBlueGreenTransition makes sure the collections of the set in Solr match whether blue/green is enabled. If blue/green
+// is disabled but the set still has _blue/_green collections (or the other way around) then they'd be treated as no
+// longer specified and cleaned up, while new empty collections were created in their place. Only collections which
+// belong to the set are considered ...
+func checkBlueGreenTransition(collectionSet solrCollectionSet.SolrCollectionSet,
+	solrCollections map[string]solr.Collection) error {
+
+	owner := collectionOwner(collectionSet)
+	isManaged := func(collectionName string) bool {
+		collection, exists := solrCollections[collectionName]
+		if !exists {
+			return false
+		}
+		if collection.HasOwner() {
+			return collection.Owner.Uid == owner.Uid
+		}
+		return contains(collectionSet.Status.ManagedCollections, collectionName)
+	}
+
+	var leftBehind []string
+	for _, spec := range collectionSet.Spec.Collections {
+		if *collectionSet.Spec.BlueGreenEnabled {
+			if isManaged(spec.Name) {
+				leftBehind = append(leftBehind, spec.Name)
+			}
+			continue
+		}
+		for _, instanceName := range []string{spec.Name + "_blue", spec.Name + "_green"} {
+			if isManaged(instanceName) {
+				leftBehind = append(leftBehind, instanceName)
+			}
+		}
+	}
+	if len(leftBehind) == 0 {
+		return nil
+	}
+	if *collectionSet.Spec.BlueGreenEnabled {
+		return fmt.Errorf("blue/green is enabled but collections [%s] were created without it. Turn blue/green back "+
+			"off, or move the data to the _blue/_green collections and remove these collections first",
+			strings.Join(leftBehind, ", "))
+	}
+	return fmt.Errorf("blue/green is disabled but collections [%s] were created with it. Turn blue/green back on, or "+
+		"move the data of the active color to the plain collections and remove these collections (and their aliases) "+
+		"first", strings.Join(leftBehind, ", "))
+}
+
+// findMissingConfigSets returns an error naming the collections (and routed aliases) whose config set isn't in Solr,
+// or nil if none are missing. This is called after the config sets have been managed, so any config set available as
+// a configmap has been uploaded by then. err is only returned if Solr couldn't be asked ...
+func findMissingConfigSets(ctx context.Context, solrClient solr.SolrClient,
+	collectionSet solrCollectionSet.SolrCollectionSet) (missingErr error, err error) {
+	solrConfigSets, err := solrClient.GetConfigSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, spec := range collectionSet.Spec.Collections {
+		if !spec.IsDisabled() && !contains(solrConfigSets, spec.ConfigsetName) {
+			missing = append(missing, fmt.Sprintf("collection [%s] config set [%s]", spec.Name, spec.ConfigsetName))
+		}
+	}
+	for _, spec := range collectionSet.Spec.RoutedAliases {
+		if !contains(solrConfigSets, spec.ConfigsetName) {
+			missing = append(missing, fmt.Sprintf("routed alias [%s] config set [%s]", spec.Name, spec.ConfigsetName))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no config set or configmap found for %s", strings.Join(missing, ", ")), nil
+	}
+	return nil, nil
+}
+
+// RequeueOnError handles reconcile errors ...
+func (r *SolrCollectionSetReconciler) RequeueOnError(
+	ctx context.Context,
+	req ctrl.Request,
+	collectionSet *solrCollectionSet.SolrCollectionSet,
+	error error) (ctrl.Result, error) {
+
+	return r.requeueOnErrorWithReason(ctx, req, collectionSet, reasonSolrCollectionSetReconcileError, error)
+}
+
+// requeueOnErrorWithReason handles reconcile errors using the given reason for the stable condition. Any additional
+// conditions given (e.g. the condition for the part of the reconcile that failed) are applied as well ...
+func (r *SolrCollectionSetReconciler) requeueOnErrorWithReason(
+	ctx context.Context,
+	req ctrl.Request,
+	collectionSet *solrCollectionSet.SolrCollectionSet,
+	reason string,
+	error error,
+	additionalConditions ...metav1.Condition) (ctrl.Result, error) {
+
+	logger := log.FromContext(ctx)
+	logger.Info("requeueing on error")
+
+	// If Solr rejected the credentials (e.g. they expired or were revoked part way through the reconcile) then say so
+	// rather than reporting a generic error, since retrying won't help until the credentials are fixed ...
+	if authReason := authFailureReason(error); authReason != reasonSolrCollectionSetReconcileError {
+		reason = authReason
+		r.Recorder.Eventf(collectionSet, corev1.EventTypeWarning, eventSolrCollectionSetAuthFailed,
+			"Solr rejected the credentials of SolrCollectionSpec [%s] in namespace [%s]: %s",
+			collectionSet.Name, collectionSet.Namespace, error.Error())
+	}
+
+	// If the reconcile timed out then say so, and save the status with a context of its own since the reconcile's
+	// context is done ...
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		reason = reasonSolrCollectionSetReconcileTimeout
+		error = fmt.Errorf("the reconcile timed out after [%s]: %w", r.ReconcileTimeout, error)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), time.Second*interruptedOperationTimeoutSeconds)
+		defer cancel()
+	}
+
+	// Because an error has been hit, the collection set is no longer stable ...
+	stableCondition := metav1.Condition{
+		Type:    typeSolrCollectionSetStable,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: error.Error(),
+	}
+
+	// If the new status object and the old status object differ, then apply the changes ...
+	oldInstance := collectionSet.DeepCopy()
+	// Make a copy of the
+	statusCopy := oldInstance.Status.DeepCopy()
+	// Write the conditions into the status object ...
+	meta.SetStatusCondition(&statusCopy.Conditions, stableCondition)
+	for _, condition := range additionalConditions {
+		meta.SetStatusCondition(&statusCopy.Conditions, condition)
+	}
+	if statusCopy.UnstableSince == nil {
+		unstableSince := metav1.NewTime(time.Now().Truncate(time.Second))
+		statusCopy.UnstableSince = &unstableSince
+	}
+	r.warnIfUnstableTooLong(collectionSet, statusCopy)
+	// Back off for longer the more errors there are in a row ...
+	statusCopy.ConsecutiveErrors++
+	delay := errorBackoffDelay(statusCopy.ConsecutiveErrors)
+	r.errorBackoffs.add(req.NamespacedName.String(), collectionSet.Generation, delay)
+	logger.Info(fmt.Sprintf("[%d] errors in a row so retrying in [%s]", statusCopy.ConsecutiveErrors, delay))
+
+	// If anything changed then write out the new status. This will cause a call to Reconcile() to be queued for
+	// immediate processing, which waits out the backoff ...
+	if !reflect.DeepEqual(collectionSet.Status, *statusCopy) {
+		collectionSet.Status = *statusCopy
+		err := r.Status().Patch(ctx, collectionSet, client.MergeFrom(oldInstance))
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("failed to save collection set status [%s]", collectionSet.Name))
+			return requeueWithBackoff()
+		}
+		// Re-fetch the SolrCollectionSet after updating the status
+		if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to re-fetch SolrCollectionSet [%s]", collectionSet.Name))
+			return requeueWithBackoff()
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: delay}, nil
+}
+
+// authFailureReason returns the Stable condition reason for an auth error from the solr.SolrClient ...
+func authFailureReason(err error) string {
+	switch {
+	case errors.Is(err, solr.ErrAuthFailed):
+		return reasonSolrCollectionSetAuthFailed
+	case errors.Is(err, solr.ErrAuthInsufficient):
+		return reasonSolrCollectionSetAuthInsufficient
+	}
+	return reasonSolrCollectionSetReconcileError
+}
+
+// requeue returns a standard delayed requeue ...
+func requeue() (ctrl.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+// requeueImmediately does just that ...
+func requeueImmediately() (ctrl.Result, error) {
+	return reconcile.Result{RequeueAfter: time.Millisecond}, nil
+}
+
+// requeueWithBackoff requeues after a delay ...
+func requeueWithBackoff() (ctrl.Result, error) {
+	return reconcile.Result{RequeueAfter: time.Second * backoffRequeueSeconds}, nil
+}
+
+// abs calculates the absolute value of an int32 ...
+func abs(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// contains tests if the given list contains the given string ...
+func contains(list []string, target string) bool {
+	for _, v := range list {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// countSolrCollections counts up the number of collections in the given map MINUS the unmanaged ones ...
+func countSolrCollections(collections map[string]solr.Collection, specCollections []solrCollectionSet.SolrCollection, isBlueGreenEnabled bool) (count int) {
+
+	// Make a list of the specified collection names (leaving out disabled collections) ...
+	var specCollectionList = make([]string, 0, len(specCollections))
+	for _, collection := range specCollections {
+		if !collection.IsDisabled() {
+			specCollectionList = append(specCollectionList, collection.Name)
+		}
+	}
+
+	for _, collection := range collections {
+		// Don't count collections that begin with _ ...
+		if !strings.HasPrefix(collection.Name, "_") {
+			var collectionName = collection.Name
+			if isBlueGreenEnabled {
+				// Strip the b/g suffix if b/g is enabled ...
+				collectionName = strings.TrimSuffix(collectionName, "_blue")
+				collectionName = strings.TrimSuffix(collectionName, "_green")
+			}
+			exists := contains(specCollectionList, collectionName)
+			if exists {
+				count++
+			}
+		}
+
+	}
+	return count
+}
+
+// managedCollections determines the collections managed by a collection set from the previously managed collections
+// and the specified collections. Only collections that exist in the cluster are included ...
+func managedCollections(previouslyManaged []string, specified iter.Seq[string],
+	solrCollections map[string]solr.Collection) []string {
+
+	var managed = make(map[string]bool)
+	for _, collectionName := range previouslyManaged {
+		managed[collectionName] = true
+	}
+	for collectionName := range specified {
+		managed[collectionName] = true
+	}
+
+	var names []string
+	for collectionName := range managed {
+		if _, exists := solrCollections[collectionName]; exists {
+			names = append(names, collectionName)
+		}
+	}
+	// Sort the names otherwise DeepEqual won't consider them equal ...
+	sort.Strings(names)
+	return names
+}
+
+// countSpecifiedCollections counts the number of specified collections taking into account blue/green collections
+func countSpecifiedCollections(collections []solrCollectionSet.SolrCollection, isBlueGreenEnabled bool) (count int) {
+	multiplier := 1
+	for _, collection := range collections {
+		// Disabled collections aren't expected to exist ...
+		if !collection.IsDisabled() {
+			count++
+		}
+	}
 	if isBlueGreenEnabled {
 		multiplier = 2
 	}
@@ -1121,5 +4762,7 @@ func countSpecifiedCollections(collections []solrCollectionSet.SolrCollection, i
 // SetupWithManager sets up the controller with the Manager.
 func (r *SolrCollectionSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&solrCollectionSet.SolrCollectionSet{}).Named("solrcollectionset").Complete(r)
+		For(&solrCollectionSet.SolrCollectionSet{}).Named("solrcollectionset").
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
 }
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestSplitShardIsCheckedOnByLaterReconciles(t *testing.T) {
+	tests := []struct {
+		name        string
+		finalState  string
+		expectError bool
+	}{
+		{name: "completed", finalState: solr.AsyncStateCompleted},
+		{name: "failed", finalState: solr.AsyncStateFailed, expectError: true},
+		{name: "lost", finalState: solr.AsyncStateNotFound, expectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeSolr{strict: true, responses: map[string]string{
+				"SPLITSHARD":    "",
+				"REQUESTSTATUS": fmt.Sprintf(`{"status": {"state": "%s"}}`, solr.AsyncStateRunning),
+			}}
+			solrClient := server.start(t)
+			// splits returns the request ids of the splits submitted so far ...
+			splits := func() []string {
+				var requestIds []string
+				for _, query := range server.queries("SPLITSHARD") {
+					requestIds = append(requestIds, query.Get("async"))
+				}
+				return requestIds
+			}
+
+			collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			})
+			collectionSet.Annotations = map[string]string{annotationSplitShard: "books_blue/shard1"}
+
+			scheme := runtime.NewScheme()
+			if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("add to scheme failed: %v", err)
+			}
+			r := &SolrCollectionSetReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+					WithStatusSubresource(collectionSet).Build(),
+				Recorder: record.NewFakeRecorder(100),
+			}
+			solrCollections := map[string]solr.Collection{
+				"books_blue": {Shards: map[string]solr.Shard{"shard1": {}}},
+			}
+			ctx := context.Background()
+			refetch := func() {
+				if err := r.Get(ctx, client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+					t.Fatalf("get collection set failed: %v", err)
+				}
+			}
+
+			// The split is submitted and recorded rather than waited on ...
+			changed, pending, err := r.SplitShard(ctx, solrClient, collectionSet, solrCollections)
+			if err != nil || !changed || pending {
+				t.Fatalf("expected the split to be submitted, got changed [%t] pending [%t] error [%v]",
+					changed, pending, err)
+			}
+			refetch()
+			if len(splits()) != 1 || len(collectionSet.Status.PendingOperations) != 1 ||
+				collectionSet.Status.PendingOperations[0].RequestId != splits()[0] {
+				t.Fatalf("expected the split to be recorded, got splits %v and pending operations %v",
+					splits(), collectionSet.Status.PendingOperations)
+			}
+
+			// ... later reconciles check on it while it's running without submitting it again ...
+			changed, pending, err = r.SplitShard(ctx, solrClient, collectionSet, solrCollections)
+			if err != nil || changed || !pending {
+				t.Fatalf("expected the split to be pending, got changed [%t] pending [%t] error [%v]",
+					changed, pending, err)
+			}
+			if len(splits()) != 1 {
+				t.Fatalf("expected the split to be submitted once, got %v", splits())
+			}
+
+			// ... and once it's finished it's no longer tracked and the annotation is removed ...
+			server.responses["REQUESTSTATUS"] = fmt.Sprintf(`{"status": {"state": "%s"}}`, test.finalState)
+			_, pending, err = r.SplitShard(ctx, solrClient, collectionSet, solrCollections)
+			if (err != nil) != test.expectError || pending {
+				t.Fatalf("expected error [%t], got pending [%t] error [%v]", test.expectError, pending, err)
+			}
+			refetch()
+			if len(collectionSet.Status.PendingOperations) != 0 {
+				t.Fatalf("expected no pending operations, got %v", collectionSet.Status.PendingOperations)
+			}
+			if _, exists := collectionSet.Annotations[annotationSplitShard]; exists {
+				t.Fatalf("expected the split shard annotation to be removed")
+			}
+			if len(splits()) != 1 {
+				t.Fatalf("expected the split to be submitted once, got %v", splits())
+			}
+		})
+	}
+}
+
+func TestSplitShardIsRefusedWithNumShards(t *testing.T) {
+	numShards := int32(1)
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books", NumShards: &numShards}},
+	})
+	collectionSet.Annotations = map[string]string{annotationSplitShard: "books_blue/shard1"}
+
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	solrCollections := map[string]solr.Collection{
+		"books_blue": {Shards: map[string]solr.Shard{"shard1": {}}},
+	}
+	ctx := context.Background()
+
+	// Splitting would leave the collection with more shards than numShards, so the split is dropped ...
+	_, pending, err := r.SplitShard(ctx, (&fakeSolr{strict: true}).start(t), collectionSet, solrCollections)
+	if err != nil || pending {
+		t.Fatalf("expected the split to be dropped, got pending [%t] error [%v]", pending, err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(collectionSet), collectionSet); err != nil {
+		t.Fatalf("get collection set failed: %v", err)
+	}
+	if _, exists := collectionSet.Annotations[annotationSplitShard]; exists {
+		t.Fatalf("expected the split shard annotation to be removed")
+	}
+	if len(collectionSet.Status.PendingOperations) != 0 {
+		t.Fatalf("expected no pending operations, got %v", collectionSet.Status.PendingOperations)
+	}
+}
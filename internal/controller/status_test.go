@@ -0,0 +1,646 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+	solr "github.com/uw-it-sis/solr-collections-operator/internal/controller/solr_api"
+)
+
+func TestCollectionCreationTimeInStatus(t *testing.T) {
+	blueGreenEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections:      []solrcollectionsv1.SolrCollection{{Name: "authors"}, {Name: "books"}},
+	})
+	// Solr doesn't report the creation time of authors ...
+	clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{
+		"authors": {Name: "authors", ReplicationFactor: 1},
+		"books":   {Name: "books", ReplicationFactor: 1, CreationTimeMillis: 1700000000123},
+	}}
+
+	var status solrcollectionsv1.SolrCollectionSetStatus
+	populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+	if len(status.SolrCollections) != 2 {
+		t.Fatalf("expected two collections, got %+v", status.SolrCollections)
+	}
+	if createdAt := status.SolrCollections[0].CreatedAt; createdAt != nil {
+		t.Fatalf("expected no creation time for [authors], got [%s]", createdAt)
+	}
+	// The creation time is truncated to the second as it would be once it's been saved ...
+	createdAt := status.SolrCollections[1].CreatedAt
+	if createdAt == nil || createdAt.Unix() != 1700000000 || createdAt.Nanosecond() != 0 {
+		t.Fatalf("expected [books] to have been created at [1700000000], got [%v]", createdAt)
+	}
+}
+
+func TestReadinessConditions(t *testing.T) {
+	shard := func(replicas int32) map[string]solr.Shard {
+		return map[string]solr.Shard{"shard1": {Name: "shard1", State: "active", NrtReplicaCount: replicas}}
+	}
+	collection := func(name string, replicas int32) solr.Collection {
+		return solr.Collection{Name: name, ReplicationFactor: 2, NrtReplicas: 2, NrtReplicaCount: replicas,
+			Shards: shard(replicas)}
+	}
+	synced := metav1.Condition{Type: typeSolrCollectionSetConfigSetsSynced, Status: metav1.ConditionTrue,
+		Reason: reasonSolrCollectionSetConfigSetsSynced}
+	syncFailed := metav1.Condition{Type: typeSolrCollectionSetConfigSetsSynced, Status: metav1.ConditionFalse,
+		Reason: reasonSolrCollectionSetConfigSetSyncFailed}
+
+	tests := []struct {
+		name              string
+		collections       map[string]solr.Collection
+		configSets        metav1.Condition
+		configSetsReason  string
+		collectionsReason string
+		replicasReason    string
+		stableReason      string
+	}{
+		{name: "ready", collections: map[string]solr.Collection{
+			"books": collection("books", 2), "authors": collection("authors", 2)}, configSets: synced,
+			stableReason: reasonSolrCollectionSetStable},
+		{name: "missing collection", collections: map[string]solr.Collection{"books": collection("books", 2)},
+			configSets: synced, collectionsReason: reasonSolrCollectionAddingCollections,
+			stableReason: reasonSolrCollectionAddingCollections},
+		{name: "missing replicas", collections: map[string]solr.Collection{
+			"books": collection("books", 1), "authors": collection("authors", 2)}, configSets: synced,
+			replicasReason: reasonSolrCollectionSetScalingOut, stableReason: reasonSolrCollectionSetScalingOut},
+		// The config sets condition is maintained elsewhere, but it still counts towards stability ...
+		{name: "config sets not synced", collections: map[string]solr.Collection{
+			"books": collection("books", 2), "authors": collection("authors", 2)}, configSets: syncFailed,
+			configSetsReason: reasonSolrCollectionSetConfigSetSyncFailed,
+			stableReason:     reasonSolrCollectionSetConfigSetSyncFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blueGreenEnabled := false
+			replicationFactor := int32(2)
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+				BlueGreenEnabled:  &blueGreenEnabled,
+				ReplicationFactor: &replicationFactor,
+				Collections:       []solrcollectionsv1.SolrCollection{{Name: "authors"}, {Name: "books"}},
+			})
+			collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{Conditions: []metav1.Condition{test.configSets}}
+
+			var status solrcollectionsv1.SolrCollectionSetStatus
+			populateCollectionSetStatus(&status, &collectionSet, solr.ClusterStatus{Collections: test.collections},
+				logr.Discard())
+
+			expectCondition := func(conditionType string, notReadyReason string) {
+				condition := meta.FindStatusCondition(status.Conditions, conditionType)
+				if condition == nil {
+					t.Fatalf("expected a [%s] condition, got %+v", conditionType, status.Conditions)
+				}
+				expectedStatus := metav1.ConditionTrue
+				if notReadyReason != "" {
+					expectedStatus = metav1.ConditionFalse
+					if condition.Reason != notReadyReason {
+						t.Fatalf("expected [%s] to have reason [%s], got [%s]", conditionType, notReadyReason,
+							condition.Reason)
+					}
+				}
+				if condition.Status != expectedStatus {
+					t.Fatalf("expected [%s] to be [%s], got [%s]", conditionType, expectedStatus, condition.Status)
+				}
+			}
+			expectCondition(typeSolrCollectionSetCollectionsReady, test.collectionsReason)
+			expectCondition(typeSolrCollectionSetReplicasReady, test.replicasReason)
+			expectCondition(typeSolrCollectionSetConfigSetsSynced, test.configSetsReason)
+			if stable := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetStable); stable == nil ||
+				stable.Reason != test.stableReason {
+				t.Fatalf("expected the stable condition to have reason [%s], got %+v", test.stableReason, stable)
+			}
+		})
+	}
+}
+
+func TestReplicasThatArentActiveAreUnstable(t *testing.T) {
+	blueGreenEnabled := false
+	replicationFactor := int32(2)
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled:  &blueGreenEnabled,
+		ReplicationFactor: &replicationFactor,
+		Collections:       []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+
+	tests := []struct {
+		name              string
+		states            []string
+		replicasNotActive int32
+		stableReason      string
+	}{
+		{name: "active", states: []string{"active", "active"}, stableReason: reasonSolrCollectionSetStable},
+		// Replicas without a state are treated as active ...
+		{name: "no state", states: []string{"active", ""}, stableReason: reasonSolrCollectionSetStable},
+		{name: "recovering", states: []string{"active", "recovering"}, replicasNotActive: 1,
+			stableReason: reasonSolrCollectionSetReplicasRecovering},
+		{name: "down", states: []string{"down", "recovery_failed"}, replicasNotActive: 2,
+			stableReason: reasonSolrCollectionSetReplicasRecovering},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The number of replicas is right, so only their states can make the set unstable ...
+			var replicas []solr.Replica
+			for i, state := range test.states {
+				replicas = append(replicas, solr.Replica{Name: fmt.Sprintf("core_node%d", i+1), Shard: "shard1",
+					Type: solr.ReplicaTypeNRT, State: state})
+			}
+			clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{
+				"books": {Name: "books", ReplicationFactor: 2, NrtReplicas: 2, NrtReplicaCount: 2, Replicas: replicas,
+					Shards: map[string]solr.Shard{"shard1": {Name: "shard1", State: "active", NrtReplicaCount: 2}}},
+			}}
+
+			var status solrcollectionsv1.SolrCollectionSetStatus
+			populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+			if len(status.SolrCollections) != 1 || status.SolrCollections[0].ReplicasNotActive != test.replicasNotActive {
+				t.Fatalf("expected [%d] replicas not active, got %+v", test.replicasNotActive, status.SolrCollections)
+			}
+			if stable := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetStable); stable == nil ||
+				stable.Reason != test.stableReason {
+				t.Fatalf("expected the stable condition to have reason [%s], got %+v", test.stableReason, stable)
+			}
+			replicasReady := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetReplicasReady)
+			if expected := test.replicasNotActive == 0; replicasReady == nil ||
+				(replicasReady.Status == metav1.ConditionTrue) != expected {
+				t.Fatalf("expected replicas ready to be [%t], got %+v", expected, replicasReady)
+			}
+		})
+	}
+}
+
+func TestNoCollectionsSpecified(t *testing.T) {
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{})
+
+	// A set without collections is stable, and says why there's nothing else in the status ...
+	var status solrcollectionsv1.SolrCollectionSetStatus
+	events := populateCollectionSetStatus(&status, &collectionSet, solr.ClusterStatus{}, logr.Discard())
+	stable := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetStable)
+	if stable == nil || stable.Status != metav1.ConditionTrue ||
+		stable.Reason != reasonSolrCollectionSetNoCollectionsSpecified {
+		t.Fatalf("expected the stable condition to be true with reason [%s], got %+v",
+			reasonSolrCollectionSetNoCollectionsSpecified, stable)
+	}
+	collectionsReady := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetCollectionsReady)
+	if collectionsReady == nil || collectionsReady.Reason != reasonSolrCollectionSetNoCollectionsSpecified {
+		t.Fatalf("expected the collections ready condition to have reason [%s], got %+v",
+			reasonSolrCollectionSetNoCollectionsSpecified, collectionsReady)
+	}
+	if _, exists := events[eventSolrCollectionSetNoCollectionsSpecified]; !exists {
+		t.Fatalf("expected a [%s] event, got %v", eventSolrCollectionSetNoCollectionsSpecified, events)
+	}
+
+	// ... but the event is only emitted when the set first ends up without collections ...
+	collectionSet.Status = status
+	var nextStatus solrcollectionsv1.SolrCollectionSetStatus
+	events = populateCollectionSetStatus(&nextStatus, &collectionSet, solr.ClusterStatus{}, logr.Discard())
+	if _, exists := events[eventSolrCollectionSetNoCollectionsSpecified]; exists {
+		t.Fatalf("expected no [%s] event the second time, got %v", eventSolrCollectionSetNoCollectionsSpecified, events)
+	}
+}
+
+func TestObservedGeneration(t *testing.T) {
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	collectionSet.Generation = 2
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{ObservedGeneration: 1}
+
+	// Updating the status from the cluster doesn't mean the new generation has been reconciled ...
+	var status solrcollectionsv1.SolrCollectionSetStatus
+	populateCollectionSetStatus(&status, collectionSet, solr.ClusterStatus{}, logr.Discard())
+	if status.ObservedGeneration != 1 {
+		t.Fatalf("expected the observed generation to be carried forward, got [%d]", status.ObservedGeneration)
+	}
+
+	// ... that's only recorded once the reconcile gets all the way through ...
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	if err := r.UpdateObservedGeneration(context.Background(), req, collectionSet); err != nil {
+		t.Fatalf("update observed generation failed: %v", err)
+	}
+	if collectionSet.Status.ObservedGeneration != 2 {
+		t.Fatalf("expected the observed generation [2], got [%d]", collectionSet.Status.ObservedGeneration)
+	}
+}
+
+func TestMissingConfigSetsAreReportedInStatus(t *testing.T) {
+	collectionSet := &solrcollectionsv1.SolrCollectionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default"},
+	}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	// Config sets that are missing are reported by the config sets synced condition ...
+	missing := errors.New("no config set or configmap found for collection [authors] config set [authors]")
+	if err := r.UpdateConfigSetStatus(ctx, req, collectionSet, nil, missing); err != nil {
+		t.Fatalf("update config set status failed: %v", err)
+	}
+	synced := meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetConfigSetsSynced)
+	if synced == nil || synced.Status != metav1.ConditionFalse ||
+		synced.Reason != reasonSolrCollectionSetMissingConfigSet || synced.Message != missing.Error() {
+		t.Fatalf("expected the config sets synced condition to report the missing config set, got %+v", synced)
+	}
+
+	// ... and once the config set turns up the condition goes back to true ...
+	if err := r.UpdateConfigSetStatus(ctx, req, collectionSet, nil, nil); err != nil {
+		t.Fatalf("update config set status failed: %v", err)
+	}
+	synced = meta.FindStatusCondition(collectionSet.Status.Conditions, typeSolrCollectionSetConfigSetsSynced)
+	if synced == nil || synced.Status != metav1.ConditionTrue {
+		t.Fatalf("expected the config sets synced condition to be true, got %+v", synced)
+	}
+}
+
+func TestWarnIfUnstableTooLong(t *testing.T) {
+	tests := []struct {
+		name          string
+		threshold     time.Duration
+		unstableFor   time.Duration
+		stable        bool
+		alreadyWarned bool
+		expectWarning bool
+	}{
+		{name: "unstable too long", threshold: time.Minute, unstableFor: time.Hour, expectWarning: true},
+		{name: "not unstable long enough", threshold: time.Hour, unstableFor: time.Minute},
+		{name: "already warned", threshold: time.Minute, unstableFor: time.Hour, alreadyWarned: true},
+		{name: "stable", threshold: time.Minute, stable: true},
+		{name: "disabled", unstableFor: time.Hour},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(100)
+			r := &SolrCollectionSetReconciler{Recorder: recorder, UnstableWarningThreshold: test.threshold}
+			collectionSet := &solrcollectionsv1.SolrCollectionSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "default"},
+			}
+			status := solrcollectionsv1.SolrCollectionSetStatus{
+				UnstableWarningEmitted: test.alreadyWarned,
+				Conditions: []metav1.Condition{{Type: typeSolrCollectionSetStable, Status: metav1.ConditionFalse,
+					Reason: reasonSolrCollectionSetReconcileError, Message: "solr is down"}},
+			}
+			if !test.stable {
+				unstableSince := metav1.NewTime(time.Now().Add(-test.unstableFor))
+				status.UnstableSince = &unstableSince
+			}
+
+			r.warnIfUnstableTooLong(collectionSet, &status)
+			if warned := len(recorder.Events) == 1; warned != test.expectWarning {
+				t.Fatalf("expected a warning [%t], got [%d] events", test.expectWarning, len(recorder.Events))
+			}
+			if test.expectWarning {
+				event := <-recorder.Events
+				if !strings.HasPrefix(event, corev1.EventTypeWarning+" "+eventSolrCollectionSetUnstableTooLong) ||
+					!strings.Contains(event, reasonSolrCollectionSetReconcileError) {
+					t.Fatalf("expected an [%s] warning with the reason, got [%s]", eventSolrCollectionSetUnstableTooLong,
+						event)
+				}
+			}
+			// The warning is only emitted once each time the set becomes unstable ...
+			if status.UnstableWarningEmitted != (test.expectWarning || test.alreadyWarned) {
+				t.Fatalf("expected the warning to be recorded in the status [%t], got [%t]",
+					test.expectWarning || test.alreadyWarned, status.UnstableWarningEmitted)
+			}
+		})
+	}
+}
+
+func TestChecksumCollectionStatus(t *testing.T) {
+	leader := solr.Replica{Name: "core_node1", State: "active", Leader: true}
+	follower := solr.Replica{Name: "core_node2", State: "active"}
+	down := solr.Replica{Name: "core_node2", State: "down"}
+	tests := []struct {
+		name            string
+		replicas        []solr.Replica
+		missing         bool
+		sharedChecksums bool
+		countFails      bool
+		expected        solrcollectionsv1.ChecksumCollectionStatus
+		expectedQuery   string
+	}{
+		{name: "healthy", replicas: []solr.Replica{leader, follower}, expectedQuery: "*:*",
+			expected: solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums", Exists: true, Healthy: true,
+				ReplicationStatus: "2/2"}},
+		{name: "replica down", replicas: []solr.Replica{leader, down}, expectedQuery: "*:*",
+			expected: solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums", Exists: true,
+				ReplicationStatus: "2/2"}},
+		{name: "no leader", replicas: []solr.Replica{follower}, expectedQuery: "*:*",
+			expected: solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums", Exists: true,
+				ReplicationStatus: "2/2"}},
+		{name: "shared", replicas: []solr.Replica{leader, follower}, sharedChecksums: true,
+			expectedQuery: `set:"default/books"`,
+			expected: solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums", Exists: true, Healthy: true,
+				ReplicationStatus: "2/2"}},
+		{name: "count fails", replicas: []solr.Replica{leader, follower}, countFails: true, expectedQuery: "*:*",
+			expected: solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums", Exists: true, Healthy: true,
+				ReplicationStatus: "2/2"}},
+		{name: "missing", missing: true,
+			expected: solrcollectionsv1.ChecksumCollectionStatus{Name: "_booksChecksums", ReplicationStatus: "--"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeSolr{strict: true, failing: test.countFails, responses: map[string]string{
+				"select": `{"response": {"numFound": 3, "docs": []}}`,
+			}}
+			solrClient := server.start(t)
+
+			collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{SharedChecksums: &test.sharedChecksums})
+			clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{}}
+			if !test.missing {
+				clusterStatus.Collections["_booksChecksums"] = solr.Collection{Name: "_booksChecksums",
+					ReplicationFactor: 2, NrtReplicaCount: 2,
+					Shards: map[string]solr.Shard{"shard1": {Name: "shard1", Replicas: test.replicas}}}
+			}
+
+			status := checksumCollectionStatusOf(context.Background(), solrClient, collectionSet, clusterStatus,
+				"_booksChecksums")
+			// The record count is only known if the collection could be counted ...
+			if !test.missing && !test.countFails {
+				count := int64(3)
+				test.expected.RecordCount = &count
+			}
+			if !reflect.DeepEqual(*status, test.expected) {
+				t.Fatalf("expected the status %+v, got %+v", test.expected, *status)
+			}
+			var query string
+			for _, requestUrl := range server.requests {
+				query = requestUrl.Query().Get("q")
+			}
+			if query != test.expectedQuery {
+				t.Fatalf("expected the records to be counted with [%s], got [%s]", test.expectedQuery, query)
+			}
+		})
+	}
+}
+
+func TestInitializedCondition(t *testing.T) {
+	collectionSet := &solrcollectionsv1.SolrCollectionSet{}
+
+	// The set is initializing until the checksums collection exists ...
+	var status solrcollectionsv1.SolrCollectionSetStatus
+	setInitializedCondition(&status, collectionSet, false)
+	initialized := meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetInitialized)
+	if initialized == nil || initialized.Status != metav1.ConditionFalse ||
+		initialized.Reason != reasonSolrCollectionSetInitializing {
+		t.Fatalf("expected the initialized condition to be false with reason [%s], got %+v",
+			reasonSolrCollectionSetInitializing, initialized)
+	}
+
+	status = solrcollectionsv1.SolrCollectionSetStatus{}
+	setInitializedCondition(&status, collectionSet, true)
+	initialized = meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetInitialized)
+	if initialized == nil || initialized.Status != metav1.ConditionTrue ||
+		initialized.Reason != reasonSolrCollectionSetInitialized {
+		t.Fatalf("expected the initialized condition to be true with reason [%s], got %+v",
+			reasonSolrCollectionSetInitialized, initialized)
+	}
+
+	// ... and an unchanged condition is carried forward as it was ...
+	initializedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	existing := *initialized
+	existing.LastTransitionTime = initializedAt
+	collectionSet.Status.Conditions = []metav1.Condition{existing}
+	status = solrcollectionsv1.SolrCollectionSetStatus{}
+	setInitializedCondition(&status, collectionSet, true)
+	initialized = meta.FindStatusCondition(status.Conditions, typeSolrCollectionSetInitialized)
+	if initialized == nil || !initialized.LastTransitionTime.Equal(&initializedAt) {
+		t.Fatalf("expected the initialized condition to be carried forward, got %+v", initialized)
+	}
+}
+
+func TestObserveModeOnlyReportsStatus(t *testing.T) {
+	// Solr is only read, so any other request fails the test ...
+	solrClient := (&fakeSolr{strict: true, responses: map[string]string{
+		"LIST": `{"configSets": ["books"]}`,
+		"CLUSTERSTATUS": `{"cluster": {"collections": {
+			"books": {"configName": "books", "replicationFactor": 1, "shards": {}}
+		}, "aliases": {}, "live_nodes": ["node1"]}}`,
+	}}).start(t)
+
+	blueGreenEnabled := false
+	cleanupEnabled := true
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		SolrClusterUrl:   solrClient.Url,
+		SecretRef:        "solr-auth",
+		Mode:             solrcollectionsv1.SolrCollectionSetModeObserve,
+		BlueGreenEnabled: &blueGreenEnabled,
+		CleanupEnabled:   &cleanupEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "books", ConfigsetName: "books"}, {Name: "authors", ConfigsetName: "authors"},
+		},
+	})
+	collectionSet.Status = solrcollectionsv1.SolrCollectionSetStatus{ManagedCollections: []string{"books", "titles"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "solr-auth", Namespace: "default"}}
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet, secret).
+			WithStatusSubresource(collectionSet).Build(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	// Nothing is created (not even the checksums collection) or removed, but the status is still reported ...
+	if err := r.Get(ctx, req.NamespacedName, collectionSet); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if collectionSet.Status.ObservedGeneration != 1 {
+		t.Fatalf("expected the observed generation to be [1], got [%d]", collectionSet.Status.ObservedGeneration)
+	}
+	exists := make(map[string]bool)
+	for _, collection := range collectionSet.Status.SolrCollections {
+		exists[collection.Name] = collection.Exists
+	}
+	if expected := map[string]bool{"books": true, "authors": false}; !reflect.DeepEqual(exists, expected) {
+		t.Fatalf("expected collections %v in the status, got %v", expected, exists)
+	}
+}
+
+func TestPopulateCollectionSetStatusIsDeterministic(t *testing.T) {
+	blueGreenEnabled := false
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled: &blueGreenEnabled,
+		Collections: []solrcollectionsv1.SolrCollection{
+			{Name: "titles"}, {Name: "books"}, {Name: "authors"}, {Name: "series"}, {Name: "genres"},
+		},
+	})
+	clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{
+		"titles": {Name: "titles", ReplicationFactor: 1}, "books": {Name: "books", ReplicationFactor: 1},
+		"authors": {Name: "authors", ReplicationFactor: 1}, "series": {Name: "series", ReplicationFactor: 1},
+		"genres": {Name: "genres", ReplicationFactor: 1},
+	}}
+
+	// Without blue/green every instance name is empty, so the collections have to be ordered by name ...
+	var first solrcollectionsv1.SolrCollectionSetStatus
+	populateCollectionSetStatus(&first, &collectionSet, clusterStatus, logr.Discard())
+	var names []string
+	for _, collectionStatus := range first.SolrCollections {
+		names = append(names, collectionStatus.Name)
+	}
+	if expected := []string{"authors", "books", "genres", "series", "titles"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected collections %v, got %v", expected, names)
+	}
+	for i := 0; i < 20; i++ {
+		var status solrcollectionsv1.SolrCollectionSetStatus
+		populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+		if !reflect.DeepEqual(status.SolrCollections, first.SolrCollections) {
+			t.Fatalf("expected the same collections every time, got %+v then %+v", first.SolrCollections,
+				status.SolrCollections)
+		}
+	}
+}
+
+func TestShardStatusesReportTheWorstShard(t *testing.T) {
+	blueGreenEnabled := false
+	replicationFactor := int32(2)
+	collectionSet := *newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		BlueGreenEnabled:  &blueGreenEnabled,
+		ReplicationFactor: &replicationFactor,
+		Collections:       []solrcollectionsv1.SolrCollection{{Name: "books"}},
+	})
+	replica := func(state string) solr.Replica {
+		return solr.Replica{Type: solr.ReplicaTypeNRT, State: state}
+	}
+	shard := func(replicas ...solr.Replica) solr.Shard {
+		return solr.Shard{State: "active", NrtReplicaCount: int32(len(replicas)), Replicas: replicas}
+	}
+	// Only shard2 is short of replicas, which a single aggregate replication status would hide ...
+	clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{
+		"books": {Name: "books", ReplicationFactor: 2, NrtReplicas: 2, NrtReplicaCount: 1, Shards: map[string]solr.Shard{
+			"shard3": shard(replica("active"), replica("recovering")),
+			"shard1": shard(replica("active"), replica("active")),
+			"shard2": shard(replica("active")),
+		}},
+	}}
+
+	var status solrcollectionsv1.SolrCollectionSetStatus
+	populateCollectionSetStatus(&status, &collectionSet, clusterStatus, logr.Discard())
+	if len(status.SolrCollections) != 1 {
+		t.Fatalf("expected one collection, got %+v", status.SolrCollections)
+	}
+	expected := []solrcollectionsv1.ShardStatus{
+		{Name: "shard1", ReplicaCount: 2, TargetReplicas: 2, Health: solrcollectionsv1.ShardHealthHealthy},
+		{Name: "shard2", ReplicaCount: 1, TargetReplicas: 2, Health: solrcollectionsv1.ShardHealthUnderReplicated},
+		{Name: "shard3", ReplicaCount: 2, TargetReplicas: 2, ReplicasNotActive: 1,
+			Health: solrcollectionsv1.ShardHealthRecovering},
+	}
+	if !reflect.DeepEqual(status.SolrCollections[0].Shards, expected) {
+		t.Fatalf("expected shards %+v, got %+v", expected, status.SolrCollections[0].Shards)
+	}
+	if status.SolrCollections[0].ReplicationStatus != "1/2" {
+		t.Fatalf("expected the worst shard [1/2], got [%s]", status.SolrCollections[0].ReplicationStatus)
+	}
+	if meta.IsStatusConditionPresentAndEqual(status.Conditions, typeSolrCollectionSetStable, metav1.ConditionTrue) {
+		t.Fatalf("expected the set not to be stable, got %+v", status.Conditions)
+	}
+
+	// An over-replicated shard is only the worst if no shard is short of replicas ...
+	worst, _ := worstShard([]solrcollectionsv1.ShardStatus{
+		{Name: "shard1", ReplicaCount: 3, TargetReplicas: 2}, {Name: "shard2", ReplicaCount: 2, TargetReplicas: 2},
+	})
+	if worst.Name != "shard1" {
+		t.Fatalf("expected the over-replicated shard1, got %+v", worst)
+	}
+}
+
+func TestStatusesAreSortedByName(t *testing.T) {
+	collectionSet := newCollectionSet(solrcollectionsv1.SolrCollectionSetSpec{
+		Collections: []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "authors"}},
+	})
+	clusterStatus := solr.ClusterStatus{Collections: map[string]solr.Collection{
+		"books_green":   {Name: "books_green", ReplicationFactor: 1},
+		"authors_blue":  {Name: "authors_blue", ReplicationFactor: 1},
+		"books_blue":    {Name: "books_blue", ReplicationFactor: 1},
+		"authors_green": {Name: "authors_green", ReplicationFactor: 1},
+	}}
+
+	// With blue/green the collections are sorted by name and then instance name ...
+	var status solrcollectionsv1.SolrCollectionSetStatus
+	populateCollectionSetStatus(&status, collectionSet, clusterStatus, logr.Discard())
+	var names []string
+	for _, collectionStatus := range status.SolrCollections {
+		names = append(names, collectionStatus.Name+"/"+collectionStatus.InstanceName)
+	}
+	expected := []string{"authors/authors_blue", "authors/authors_green", "books/books_blue", "books/books_green"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected collections %v, got %v", expected, names)
+	}
+
+	// The config sets are sorted by name ...
+	scheme := runtime.NewScheme()
+	if err := solrcollectionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add to scheme failed: %v", err)
+	}
+	r := &SolrCollectionSetReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(collectionSet).
+			WithStatusSubresource(collectionSet).Build(),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "books", Namespace: "default"}}
+	configSetStatuses := []solrcollectionsv1.ConfigSetStatus{{Name: "books"}, {Name: "series"}, {Name: "authors"}}
+	if err := r.UpdateConfigSetStatus(context.Background(), req, collectionSet, configSetStatuses, nil); err != nil {
+		t.Fatalf("update config set status failed: %v", err)
+	}
+	names = nil
+	for _, configSetStatus := range collectionSet.Status.ConfigSets {
+		names = append(names, configSetStatus.Name)
+	}
+	if expected := []string{"authors", "books", "series"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected config sets %v, got %v", expected, names)
+	}
+}
@@ -3,38 +3,128 @@ package utils
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
 )
 
-// Zip creates a zip archive of the files within the given directory ...
-func Zip(dirName string, files embed.FS) ([]byte, error) {
+// Zip creates a zip archive of the files within the given directories. Files in later directories replace files of the
+// same name in earlier ones ...
+func Zip(dirNames []string, files embed.FS) ([]byte, error) {
 
 	buf := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buf)
+	if err := ZipTo(buf, dirNames, files); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ZipTo writes a zip archive of the files within the given directories to the given writer (see Zip()). This avoids
+// holding the whole archive in memory when the writer streams it elsewhere (e.g. an io.Pipe) ...
+func ZipTo(writer io.Writer, dirNames []string, files embed.FS) error {
+
+	// Map the files by name so that later directories replace the files of earlier ones ...
+	var filePaths = make(map[string]string)
+	for _, dirName := range dirNames {
+		entries, _ := files.ReadDir(dirName)
+		for _, file := range entries {
+			filePaths[file.Name()] = fmt.Sprintf("%s/%s", dirName, file.Name())
+		}
+	}
 
-	// Iterate through the files in the directory ...
-	entries, _ := files.ReadDir(dirName)
-	for _, file := range entries {
-		var fileName = file.Name()
+	zipWriter := zip.NewWriter(writer)
+
+	// Iterate through the files ...
+	for _, fileName := range slices.Sorted(maps.Keys(filePaths)) {
 		// Create an entry in the zip file ...
 		w, err := zipWriter.Create(fileName)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		data, _ := files.ReadFile(fmt.Sprintf("%s/%s", dirName, fileName))
+		data, _ := files.ReadFile(filePaths[fileName])
 
 		// Write the data into the file ...
 		_, err = w.Write(data)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	if err := zipWriter.Close(); err != nil {
+	return zipWriter.Close()
+}
+
+// ZipFileNames returns the names of the files (not directories) within the given zip archive ...
+func ZipFileNames(data []byte) ([]string, error) {
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	var names []string
+	for _, file := range zipReader.File {
+		if !file.FileInfo().IsDir() {
+			names = append(names, strings.TrimPrefix(file.Name, "/"))
+		}
+	}
+
+	return names, nil
+}
+
+// ZipContentChecksum computes a checksum of the files within the given zip archive. Only the names and contents of the
+// files are considered (not timestamps, compression, or order), so two archives of the same files have the same
+// checksum even if they were zipped differently. Files named in renames are checksummed under the name they map to, or
+// are skipped if they map to "" ...
+func ZipContentChecksum(data []byte, renames map[string]string) (string, error) {
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	// Name the files as they're checksummed and sort them so that the order within the archive doesn't matter ...
+	type namedFile struct {
+		name string
+		file *zip.File
+	}
+	var files []namedFile
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.TrimPrefix(file.Name, "/")
+		if renamed, exists := renames[name]; exists {
+			if renamed == "" {
+				continue
+			}
+			name = renamed
+		}
+		files = append(files, namedFile{name: name, file: file})
+	}
+	slices.SortFunc(files, func(a, b namedFile) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	hash := sha256.New()
+	for _, file := range files {
+		contents, err := file.file.Open()
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(hash, "%s\n%d\n", file.name, file.file.UncompressedSize64)
+		_, err = io.Copy(hash, contents)
+		_ = contents.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"testing"
+)
+
+//go:embed testdata
+var testFiles embed.FS
+
+// zipOf zips the given name/contents pairs, in order, with the given compression method ...
+func zipOf(t *testing.T, method uint16, files ...string) []byte {
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for i := 0; i < len(files); i += 2 {
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{Name: files[i], Method: method})
+		if err != nil {
+			t.Fatalf("create zip entry failed: %v", err)
+		}
+		if _, err := w.Write([]byte(files[i+1])); err != nil {
+			t.Fatalf("write zip entry failed: %v", err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("close zip failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestZipContentChecksum(t *testing.T) {
+	checksumOf := func(data []byte, renames map[string]string) string {
+		sum, err := ZipContentChecksum(data, renames)
+		if err != nil {
+			t.Fatalf("checksum failed: %v", err)
+		}
+		return sum
+	}
+	original := checksumOf(zipOf(t, zip.Deflate, "schema.xml", "<schema/>", "solrconfig.xml", "<config/>"), nil)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		renames map[string]string
+		same    bool
+	}{
+		{name: "reordered and stored", same: true,
+			data: zipOf(t, zip.Store, "solrconfig.xml", "<config/>", "/schema.xml", "<schema/>")},
+		{name: "directory entries", same: true,
+			data: zipOf(t, zip.Deflate, "lang/", "", "schema.xml", "<schema/>", "solrconfig.xml", "<config/>")},
+		{name: "changed contents",
+			data: zipOf(t, zip.Deflate, "schema.xml", "<schema></schema>", "solrconfig.xml", "<config/>")},
+		{name: "renamed file",
+			data: zipOf(t, zip.Deflate, "schema.xml.bak", "<schema/>", "solrconfig.xml", "<config/>")},
+		{name: "renamed file mapped back", same: true,
+			data:    zipOf(t, zip.Deflate, "schema.xml.bak", "<schema/>", "solrconfig.xml", "<config/>"),
+			renames: map[string]string{"schema.xml.bak": "schema.xml"}},
+		{name: "extra file",
+			data: zipOf(t, zip.Deflate, "schema.xml", "<schema/>", "solrconfig.xml", "<config/>", "managed-schema.xml",
+				"<schema/>")},
+		{name: "extra file skipped", same: true,
+			data: zipOf(t, zip.Deflate, "schema.xml", "<schema/>", "solrconfig.xml", "<config/>", "managed-schema.xml",
+				"<schema/>"),
+			renames: map[string]string{"managed-schema.xml": ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if same := checksumOf(test.data, test.renames) == original; same != test.same {
+				t.Fatalf("expected the checksums to match [%t], got [%t]", test.same, same)
+			}
+		})
+	}
+
+	if _, err := ZipContentChecksum([]byte("not a zip"), nil); err == nil {
+		t.Fatalf("expected an error for data that isn't a zip")
+	}
+}
+
+func TestZipReplacesFilesOfEarlierDirectories(t *testing.T) {
+	zipped, err := Zip([]string{"testdata/base", "testdata/override"}, testFiles)
+	if err != nil {
+		t.Fatalf("zip failed: %v", err)
+	}
+	expected := zipOf(t, zip.Deflate, "a.txt", "base a\n", "b.txt", "override b\n", "c.txt", "override c\n")
+	if checksum(t, zipped) != checksum(t, expected) {
+		t.Fatalf("expected b.txt to be replaced and c.txt to be added")
+	}
+}
+
+func checksum(t *testing.T, data []byte) string {
+	sum, err := ZipContentChecksum(data, nil)
+	if err != nil {
+		t.Fatalf("checksum failed: %v", err)
+	}
+	return sum
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	solrCollectionSet "github.com/uw-it-sis/solr-collections-operator/api/v1"
+)
+
+// ValidationIssue is a problem found in a collection set (or one of its config set configmaps) which would stop it
+// from being reconciled ...
+type ValidationIssue struct {
+	// The collection set (namespace/name) the problem was found in
+	CollectionSet string `json:"collectionSet"`
+	// The field (or configmap) the problem was found in
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidateCollectionSet checks the given collection set, along with the configmaps holding its config sets, for the
+// problems the operator would otherwise only find once it's reconciling. Nothing is read from Kubernetes or Solr, so
+// only the given configmaps are considered ...
+func ValidateCollectionSet(collectionSet solrCollectionSet.SolrCollectionSet,
+	configMaps []corev1.ConfigMap) []ValidationIssue {
+
+	// Validate the spec as the reconcile would see it ...
+	collectionSet = *collectionSet.DeepCopy()
+	collectionSet.WithDefaults(logr.Discard())
+
+	namespace := namespaceOrDefault(collectionSet.Namespace)
+	setName := namespace + "/" + collectionSet.Name
+	var issues []ValidationIssue
+	addIssue := func(field string, format string, args ...any) {
+		issues = append(issues, ValidationIssue{CollectionSet: setName, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Names have to be unique ...
+	var collectionNames = make(map[string]bool)
+	for i, spec := range collectionSet.Spec.Collections {
+		if collectionNames[spec.Name] {
+			addIssue(fmt.Sprintf("spec.collections[%d].name", i), "collection [%s] is specified more than once", spec.Name)
+		}
+		collectionNames[spec.Name] = true
+	}
+	var routedAliasNames = make(map[string]bool)
+	for i, spec := range collectionSet.Spec.RoutedAliases {
+		if routedAliasNames[spec.Name] {
+			addIssue(fmt.Sprintf("spec.routedAliases[%d].name", i), "routed alias [%s] is specified more than once",
+				spec.Name)
+		}
+		routedAliasNames[spec.Name] = true
+	}
+
+	if err := checkMaxCollections(collectionSet); err != nil {
+		addIssue("spec.maxCollections", "%s", err.Error())
+	}
+	if err := checkNameCollisions(collectionSet); err != nil {
+		addIssue("spec.collections", "%s", err.Error())
+	}
+	if err := checkAliasConflicts(collectionSet); err != nil {
+		addIssue("spec.collections", "%s", err.Error())
+	}
+
+	// Map the configmaps of the collection set by the config set they hold (see ManageConfigSets()) ...
+	var configSets = make(map[string]corev1.ConfigMap)
+	for _, configMap := range configMaps {
+		if namespaceOrDefault(configMap.Namespace) != namespace ||
+			configMap.Labels[configMapLabelCollectionSet] != collectionSet.Name {
+			continue
+		}
+		field := fmt.Sprintf("configmap [%s]", configMap.Name)
+		name, err := configSetNameOf(configMap)
+		if err != nil {
+			addIssue(field, "%s", err.Error())
+			continue
+		}
+		if other, exists := configSets[name]; exists {
+			addIssue(field, "config set [%s] is also held by configmap [%s]", name, other.Name)
+		}
+		configSets[name] = configMap
+		if err := checkConfigSetEncoding(configMap.Data["configset"]); err != nil {
+			addIssue(field, "config set [%s] %s", name, err.Error())
+		}
+	}
+
+	// Every config set that's used has to be held by a configmap ...
+	for i, spec := range collectionSet.Spec.Collections {
+		if _, exists := configSets[spec.ConfigsetName]; !exists && !spec.IsDisabled() {
+			addIssue(fmt.Sprintf("spec.collections[%d].configsetName", i),
+				"no configmap found for config set [%s] of collection [%s]", spec.ConfigsetName, spec.Name)
+		}
+	}
+	for i, spec := range collectionSet.Spec.RoutedAliases {
+		if _, exists := configSets[spec.ConfigsetName]; !exists {
+			addIssue(fmt.Sprintf("spec.routedAliases[%d].configsetName", i),
+				"no configmap found for config set [%s] of routed alias [%s]", spec.ConfigsetName, spec.Name)
+		}
+	}
+
+	return issues
+}
+
+// checkConfigSetEncoding makes sure a config set from a configmap is a base64 encoded zip ...
+func checkConfigSetEncoding(configSetEncoded string) error {
+	if configSetEncoded == "" {
+		return fmt.Errorf("is empty (expected a base64 encoded zip under the 'configset' key)")
+	}
+	configSetDecoded, err := base64.StdEncoding.DecodeString(configSetEncoded)
+	if err != nil {
+		return fmt.Errorf("isn't valid base64: %w", err)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(configSetDecoded), int64(len(configSetDecoded))); err != nil {
+		return fmt.Errorf("isn't a zip: %w", err)
+	}
+	return nil
+}
+
+// namespaceOrDefault returns the given namespace, or the default namespace if none is given ...
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	solrcollectionsv1 "github.com/uw-it-sis/solr-collections-operator/api/v1"
+)
+
+func TestValidateCollectionSet(t *testing.T) {
+	zipped := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(zipped)
+	_, _ = zipWriter.Create("solrconfig.xml")
+	_ = zipWriter.Close()
+	configMap := func(name string, collection string, configSet string) corev1.ConfigMap {
+		return corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"collectionSet": "books", "collection": collection}},
+			Data:       map[string]string{"configset": configSet},
+		}
+	}
+	validConfigSet := base64.StdEncoding.EncodeToString(zipped.Bytes())
+
+	tests := []struct {
+		name       string
+		collection []solrcollectionsv1.SolrCollection
+		configMaps []corev1.ConfigMap
+		expected   []string
+	}{
+		{
+			name:       "valid",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			configMaps: []corev1.ConfigMap{configMap("books", "books", validConfigSet)},
+		},
+		{
+			name:       "duplicate name",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "books"}},
+			configMaps: []corev1.ConfigMap{configMap("books", "books", validConfigSet)},
+			expected:   []string{"spec.collections[1].name", "spec.collections"},
+		},
+		{
+			name:       "alias collision",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books"}, {Name: "authors", Alias: "books_green", ConfigsetName: "books"}},
+			configMaps: []corev1.ConfigMap{configMap("books", "books", validConfigSet)},
+			expected:   []string{"spec.collections"},
+		},
+		{
+			name: "shared alias",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books", Alias: "library"},
+				{Name: "authors", Alias: "library", ConfigsetName: "books"}},
+			configMaps: []corev1.ConfigMap{configMap("books", "books", validConfigSet)},
+			expected:   []string{"spec.collections"},
+		},
+		{
+			name:       "missing config set",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			expected:   []string{"spec.collections[0].configsetName"},
+		},
+		{
+			name:       "bad base64",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			configMaps: []corev1.ConfigMap{configMap("books", "books", "not base64!")},
+			expected:   []string{"configmap [books]"},
+		},
+		{
+			name:       "not a zip",
+			collection: []solrcollectionsv1.SolrCollection{{Name: "books"}},
+			configMaps: []corev1.ConfigMap{configMap("books", "books", base64.StdEncoding.EncodeToString([]byte("xml")))},
+			expected:   []string{"configmap [books]"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionSet := solrcollectionsv1.SolrCollectionSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "books"},
+				Spec:       solrcollectionsv1.SolrCollectionSetSpec{Collections: test.collection},
+			}
+			issues := ValidateCollectionSet(collectionSet, test.configMaps)
+			var fields []string
+			for _, issue := range issues {
+				fields = append(fields, issue.Field)
+			}
+			if len(fields) != len(test.expected) {
+				t.Fatalf("expected issues with %v, got %v", test.expected, issues)
+			}
+			for i := range fields {
+				if fields[i] != test.expected[i] {
+					t.Fatalf("expected issues with %v, got %v", test.expected, issues)
+				}
+			}
+		})
+	}
+}